@@ -16,6 +16,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
 	"github.com/LyricTian/gin-admin/v8/pkg/logger"
 )
 
@@ -41,10 +42,15 @@ func main() {
 	app.Usage = "RBAC scaffolding based on GIN + GORM + CASBIN + WIRE."
 	app.Commands = []*cli.Command{
 		newWebCmd(ctx),
+		newSuperAdminCmd(ctx),
+		newRouteCheckCmd(ctx),
+		newMenuSyncCmd(ctx),
+		newMenuExportCmd(ctx),
 	}
 	err := app.Run(os.Args)
 	if err != nil {
 		logger.WithContext(ctx).Errorf(err.Error())
+		os.Exit(1)
 	}
 }
 
@@ -84,3 +90,158 @@ func newWebCmd(ctx context.Context) *cli.Command {
 		},
 	}
 }
+
+func newSuperAdminCmd(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "superadmin",
+		Usage: "Create a super admin (promote the user if the username already exists)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "conf",
+				Aliases:  []string{"c"},
+				Usage:    "App configuration file(.json,.yaml,.toml)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "model",
+				Aliases:  []string{"m"},
+				Usage:    "Casbin model configuration(.conf)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "username",
+				Aliases:  []string{"u"},
+				Usage:    "Login username",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "password",
+				Aliases: []string{"p"},
+				Usage:   "Login password (required for a new user, resets the password of an existing user)",
+			},
+			&cli.StringFlag{
+				Name:  "realname",
+				Usage: "Display name",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			params := schema.SuperAdminParam{
+				UserName: c.String("username"),
+				Password: c.String("password"),
+				RealName: c.String("realname"),
+			}
+			return app.CreateSuperAdmin(ctx, params,
+				app.SetConfigFile(c.String("conf")),
+				app.SetModelFile(c.String("model")))
+		},
+	}
+}
+
+func newRouteCheckCmd(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "routecheck",
+		Usage: "Check api routes against menu action resources",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "conf",
+				Aliases:  []string{"c"},
+				Usage:    "App configuration file(.json,.yaml,.toml)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "model",
+				Aliases:  []string{"m"},
+				Usage:    "Casbin model configuration(.conf)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "menu",
+				Usage: "Initialize menu's data configuration(.yaml) before checking",
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "Exit with an error if any problem is found",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return app.RunRouteCheck(ctx, c.Bool("strict"),
+				app.SetConfigFile(c.String("conf")),
+				app.SetModelFile(c.String("model")),
+				app.SetMenuFile(c.String("menu")))
+		},
+	}
+}
+
+func newMenuSyncCmd(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "menusync",
+		Usage: "Reconcile menus, actions and resources with the menu's data configuration(.yaml)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "conf",
+				Aliases:  []string{"c"},
+				Usage:    "App configuration file(.json,.yaml,.toml)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "model",
+				Aliases:  []string{"m"},
+				Usage:    "Casbin model configuration(.conf)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "menu",
+				Usage: "Menu's data configuration(.yaml), defaults to the configured file",
+			},
+			&cli.BoolFlag{
+				Name:  "prune",
+				Usage: "Delete menus, actions and resources missing from the data configuration",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print the changes without applying them",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			params := schema.MenuSyncOptions{
+				Prune:  c.Bool("prune"),
+				DryRun: c.Bool("dry-run"),
+			}
+			return app.SyncMenu(ctx, params,
+				app.SetConfigFile(c.String("conf")),
+				app.SetModelFile(c.String("model")),
+				app.SetMenuFile(c.String("menu")))
+		},
+	}
+}
+
+func newMenuExportCmd(ctx context.Context) *cli.Command {
+	return &cli.Command{
+		Name:  "menuexport",
+		Usage: "Export menus, actions and resources as the menu's data configuration(.yaml)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "conf",
+				Aliases:  []string{"c"},
+				Usage:    "App configuration file(.json,.yaml,.toml)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "model",
+				Aliases:  []string{"m"},
+				Usage:    "Casbin model configuration(.conf)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file, defaults to stdout",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return app.ExportMenu(ctx, c.String("output"),
+				app.SetConfigFile(c.String("conf")),
+				app.SetModelFile(c.String("model")))
+		},
+	}
+}
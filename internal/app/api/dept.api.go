@@ -0,0 +1,146 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/internal/app/service"
+)
+
+var DeptSet = wire.NewSet(wire.Struct(new(DeptAPI), "*"))
+
+type DeptAPI struct {
+	DeptSrv *service.DeptSrv
+}
+
+func (a *DeptAPI) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.DeptQueryParam
+	if err := ginx.ParseQuery(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	params.Pagination = true
+	result, err := a.DeptSrv.Query(ctx, params, schema.DeptQueryOptions{
+		OrderFields: schema.NewOrderFields(
+			schema.NewOrderField("sequence", schema.OrderByDESC),
+			schema.NewOrderField("id", schema.OrderByDESC),
+		),
+	})
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResPage(c, result.Data, result.PageResult)
+}
+
+func (a *DeptAPI) QueryTree(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.DeptQueryParam
+	if err := ginx.ParseQuery(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	result, err := a.DeptSrv.Query(ctx, params, schema.DeptQueryOptions{
+		OrderFields: schema.NewOrderFields(schema.NewOrderField("sequence", schema.OrderByDESC)),
+	})
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResList(c, result.Data.ToTree())
+}
+
+func (a *DeptAPI) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	item, err := a.DeptSrv.Get(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, item)
+}
+
+func (a *DeptAPI) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.Dept
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	item.Creator = contextx.FromUserID(ctx)
+	result, err := a.DeptSrv.Create(ctx, item)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, result)
+}
+
+func (a *DeptAPI) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.Dept
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	err := a.DeptSrv.Update(ctx, ginx.ParseParamID(c, "id"), item)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *DeptAPI) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.DeptSrv.Delete(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *DeptAPI) Enable(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.DeptSrv.UpdateStatus(ctx, ginx.ParseParamID(c, "id"), 1)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *DeptAPI) Disable(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.DeptSrv.UpdateStatus(ctx, ginx.ParseParamID(c, "id"), 2)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *DeptAPI) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.DeptMoveParam
+	if err := ginx.ParseJSON(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	err := a.DeptSrv.Move(ctx, ginx.ParseParamID(c, "id"), params)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
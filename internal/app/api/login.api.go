@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/LyricTian/captcha"
@@ -19,7 +20,12 @@ import (
 var LoginSet = wire.NewSet(wire.Struct(new(LoginAPI), "*"))
 
 type LoginAPI struct {
-	LoginSrv *service.LoginSrv
+	LoginSrv      *service.LoginSrv
+	SessionSrv    *service.SessionSrv
+	MFASrv        *service.MFASrv
+	APIKeySrv     *service.APIKeySrv
+	OIDCSrv       *service.OIDCSrv
+	PermissionSrv *service.PermissionSrv
 }
 
 func (a *LoginAPI) GetCaptcha(c *gin.Context) {
@@ -73,6 +79,70 @@ func (a *LoginAPI) Login(c *gin.Context) {
 		return
 	}
 
+	// 启用两步验证的用户需要先通过动态口令校验
+	challenge, err := a.LoginSrv.GenerateMFAChallenge(ctx, user)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	} else if challenge != nil {
+		ginx.ResSuccess(c, &schema.LoginTokenInfo{MFAChallenge: challenge})
+		return
+	}
+
+	a.resToken(c, user)
+}
+
+func (a *LoginAPI) LoginMFA(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.LoginMFAParam
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	user, err := a.LoginSrv.VerifyMFA(ctx, item.ChallengeToken, item.Code)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	a.resToken(c, user)
+}
+
+func (a *LoginAPI) GetOIDCURL(c *gin.Context) {
+	ctx := c.Request.Context()
+	item, err := a.OIDCSrv.GetAuthURL(ctx)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, item)
+}
+
+func (a *LoginAPI) LoginOIDC(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.LoginOIDCParam
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	user, err := a.OIDCSrv.Login(ctx, item.Code, item.State)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	// 密码有效期与两步验证由身份提供方负责，不限制令牌的权限范围
+	a.issueToken(ctx, c, user)
+}
+
+func (a *LoginAPI) resToken(c *gin.Context, user *schema.User) {
+	ctx := a.LoginSrv.NewTokenScopeContext(c.Request.Context(), user)
+	a.issueToken(ctx, c, user)
+}
+
+func (a *LoginAPI) issueToken(ctx context.Context, c *gin.Context, user *schema.User) {
 	tokenInfo, err := a.LoginSrv.GenerateToken(ctx, a.formatTokenUserID(user.ID, user.UserName))
 	if err != nil {
 		ginx.ResError(c, err)
@@ -108,7 +178,13 @@ func (a *LoginAPI) Logout(c *gin.Context) {
 
 func (a *LoginAPI) RefreshToken(c *gin.Context) {
 	ctx := c.Request.Context()
-	tokenInfo, err := a.LoginSrv.GenerateToken(ctx, a.formatTokenUserID(contextx.FromUserID(ctx), contextx.FromUserName(ctx)))
+	var item schema.RefreshTokenParam
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	tokenInfo, err := a.LoginSrv.RefreshToken(ctx, item.RefreshToken)
 	if err != nil {
 		ginx.ResError(c, err)
 		return
@@ -116,6 +192,11 @@ func (a *LoginAPI) RefreshToken(c *gin.Context) {
 	ginx.ResSuccess(c, tokenInfo)
 }
 
+func (a *LoginAPI) GetJWKS(c *gin.Context) {
+	ctx := c.Request.Context()
+	ginx.ResSuccess(c, a.LoginSrv.GetJWKS(ctx))
+}
+
 func (a *LoginAPI) GetUserInfo(c *gin.Context) {
 	ctx := c.Request.Context()
 	info, err := a.LoginSrv.GetLoginInfo(ctx, contextx.FromUserID(ctx))
@@ -136,6 +217,22 @@ func (a *LoginAPI) QueryUserMenuTree(c *gin.Context) {
 	ginx.ResList(c, menus)
 }
 
+func (a *LoginAPI) CheckPermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.PermissionCheckParam
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	result, err := a.PermissionSrv.Check(ctx, contextx.FromUserID(ctx), item.Items)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResList(c, result)
+}
+
 func (a *LoginAPI) UpdatePassword(c *gin.Context) {
 	ctx := c.Request.Context()
 	var item schema.UpdatePasswordParam
@@ -151,3 +248,103 @@ func (a *LoginAPI) UpdatePassword(c *gin.Context) {
 	}
 	ginx.ResOK(c)
 }
+
+func (a *LoginAPI) EnrollMFA(c *gin.Context) {
+	ctx := c.Request.Context()
+	item, err := a.MFASrv.Enroll(ctx, contextx.FromUserID(ctx))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, item)
+}
+
+func (a *LoginAPI) ResMFAQRCode(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.MFASrv.ResQRCode(ctx, c.Writer, contextx.FromUserID(ctx))
+	if err != nil {
+		ginx.ResError(c, err)
+	}
+}
+
+func (a *LoginAPI) ActivateMFA(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.MFACodeParam
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	codes, err := a.MFASrv.Activate(ctx, contextx.FromUserID(ctx), item.Code)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, codes)
+}
+
+func (a *LoginAPI) QueryAPIKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+	keys, err := a.APIKeySrv.Query(ctx, contextx.FromUserID(ctx))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResList(c, keys)
+}
+
+func (a *LoginAPI) CreateAPIKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.UserAPIKeyCreateParam
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	result, err := a.APIKeySrv.Create(ctx, contextx.FromUserID(ctx), item)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, result)
+}
+
+func (a *LoginAPI) DeleteAPIKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.APIKeySrv.Delete(ctx, contextx.FromUserID(ctx), ginx.ParseParamID(c, "kid"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *LoginAPI) QuerySessions(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessions, err := a.SessionSrv.Query(ctx, contextx.FromUserID(ctx))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResList(c, sessions)
+}
+
+func (a *LoginAPI) RevokeSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.SessionSrv.Revoke(ctx, contextx.FromUserID(ctx), c.Param("sid"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *LoginAPI) RevokeAllSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.SessionSrv.RevokeAll(ctx, contextx.FromUserID(ctx))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 
@@ -56,6 +58,19 @@ func (a *MenuAPI) QueryTree(c *gin.Context) {
 	ginx.ResList(c, result.Data.ToTree())
 }
 
+// Export 导出菜单数据文件(yaml)
+func (a *MenuAPI) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+	data, err := a.MenuSrv.Export(ctx)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="menu.yaml"`)
+	c.Data(http.StatusOK, "application/x-yaml; charset=utf-8", data)
+}
+
 func (a *MenuAPI) Get(c *gin.Context) {
 	ctx := c.Request.Context()
 	item, err := a.MenuSrv.Get(ctx, ginx.ParseParamID(c, "id"))
@@ -101,7 +116,26 @@ func (a *MenuAPI) Update(c *gin.Context) {
 
 func (a *MenuAPI) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
-	err := a.MenuSrv.Delete(ctx, ginx.ParseParamID(c, "id"))
+	id := ginx.ParseParamID(c, "id")
+
+	// 级联删除时返回实际的影响范围
+	if c.Query("cascade") == "true" {
+		var params schema.MenuDeleteImpactParam
+		if err := ginx.ParseQuery(c, &params); err != nil {
+			ginx.ResError(c, err)
+			return
+		}
+
+		impact, err := a.MenuSrv.DeleteCascade(ctx, id, params)
+		if err != nil {
+			ginx.ResError(c, err)
+			return
+		}
+		ginx.ResSuccess(c, impact)
+		return
+	}
+
+	err := a.MenuSrv.Delete(ctx, id)
 	if err != nil {
 		ginx.ResError(c, err)
 		return
@@ -109,6 +143,23 @@ func (a *MenuAPI) Delete(c *gin.Context) {
 	ginx.ResOK(c)
 }
 
+// DeleteImpact 预览级联删除的影响范围
+func (a *MenuAPI) DeleteImpact(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.MenuDeleteImpactParam
+	if err := ginx.ParseQuery(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	impact, err := a.MenuSrv.DeleteImpact(ctx, ginx.ParseParamID(c, "id"), params)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, impact)
+}
+
 func (a *MenuAPI) Enable(c *gin.Context) {
 	ctx := c.Request.Context()
 	err := a.MenuSrv.UpdateStatus(ctx, ginx.ParseParamID(c, "id"), 1)
@@ -128,3 +179,36 @@ func (a *MenuAPI) Disable(c *gin.Context) {
 	}
 	ginx.ResOK(c)
 }
+
+func (a *MenuAPI) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.MenuMoveParam
+	if err := ginx.ParseJSON(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	err := a.MenuSrv.Move(ctx, ginx.ParseParamID(c, "id"), params)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+// Sort 批量调整菜单的父级及排序值
+func (a *MenuAPI) Sort(c *gin.Context) {
+	ctx := c.Request.Context()
+	var items []*schema.MenuSortItem
+	if err := ginx.ParseJSON(c, &items); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	err := a.MenuSrv.Sort(ctx, items)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
@@ -0,0 +1,120 @@
+package mock
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+var DeptSet = wire.NewSet(wire.Struct(new(DeptMock), "*"))
+
+type DeptMock struct{}
+
+// @Tags DeptAPI
+// @Summary 查询数据
+// @Security ApiKeyAuth
+// @Param current query int true "分页索引" default(1)
+// @Param pageSize query int true "分页大小" default(10)
+// @Param queryValue query string false "查询值"
+// @Param status query int false "状态(1:启用 2:禁用)"
+// @Param parentID query int false "父级ID"
+// @Success 200 {object} schema.ListResult{list=[]schema.Dept} "查询结果"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts [get]
+func (a *DeptMock) Query(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 查询部门树
+// @Security ApiKeyAuth
+// @Param status query int false "状态(1:启用 2:禁用)"
+// @Param parentID query int false "父级ID"
+// @Success 200 {object} schema.ListResult{list=[]schema.DeptTree} "查询结果"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts.tree [get]
+func (a *DeptMock) QueryTree(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 查询指定数据
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.Dept
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts/{id} [get]
+func (a *DeptMock) Get(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 创建数据
+// @Security ApiKeyAuth
+// @Param body body schema.Dept true "创建数据"
+// @Success 200 {object} schema.IDResult
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts [post]
+func (a *DeptMock) Create(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 更新数据
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param body body schema.Dept true "更新数据"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts/{id} [put]
+func (a *DeptMock) Update(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 删除数据
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts/{id} [delete]
+func (a *DeptMock) Delete(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 启用数据
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts/{id}/enable [patch]
+func (a *DeptMock) Enable(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 禁用数据
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts/{id}/disable [patch]
+func (a *DeptMock) Disable(c *gin.Context) {
+}
+
+// @Tags DeptAPI
+// @Summary 移动部门(包含下级部门)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param body body schema.DeptMoveParam true "移动参数"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/depts/{id}/move [put]
+func (a *DeptMock) Move(c *gin.Context) {
+}
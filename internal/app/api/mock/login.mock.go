@@ -30,15 +30,49 @@ func (a *LoginMock) ResCaptcha(c *gin.Context) {
 }
 
 // @Tags LoginAPI
-// @Summary 用户登录
+// @Summary 用户登录(提交明文密码)
 // @Param body body schema.LoginParam true "请求参数"
 // @Success 200 {object} schema.LoginTokenInfo
 // @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 429 {object} schema.ErrorResult "{error:{code:0,message:登录失败次数过多,details:{retry_after:30}}}"
 // @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
 // @Router /api/v1/pub/login [post]
 func (a *LoginMock) Login(c *gin.Context) {
 }
 
+// @Tags LoginAPI
+// @Summary 两步验证登录(使用登录返回的挑战令牌和动态口令或恢复码换取令牌)
+// @Param body body schema.LoginMFAParam true "请求参数"
+// @Success 200 {object} schema.LoginTokenInfo
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:动态口令不正确}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 429 {object} schema.ErrorResult "{error:{code:0,message:登录失败次数过多,details:{retry_after:30}}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/login/mfa [post]
+func (a *LoginMock) LoginMFA(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 获取OIDC授权地址(前端跳转到该地址，身份提供方回调时携带code与state)
+// @Success 200 {object} schema.LoginOIDCURL
+// @Failure 404 {object} schema.ErrorResult "{error:{code:0,message:未启用OIDC登录}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/login/oidc [get]
+func (a *LoginMock) GetOIDCURL(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary OIDC登录(使用回调中的code与state换取令牌)
+// @Param body body schema.LoginOIDCParam true "请求参数"
+// @Success 200 {object} schema.LoginTokenInfo
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:无效的授权码或状态}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:用户未开通，请联系管理员}}"
+// @Failure 404 {object} schema.ErrorResult "{error:{code:0,message:未启用OIDC登录}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/login/oidc [post]
+func (a *LoginMock) LoginOIDC(c *gin.Context) {
+}
+
 // @Tags LoginAPI
 // @Summary 用户登出
 // @Success 200 {object} schema.StatusResult "{status:OK}"
@@ -47,15 +81,23 @@ func (a *LoginMock) Logout(c *gin.Context) {
 }
 
 // @Tags LoginAPI
-// @Summary 刷新令牌
-// @Security ApiKeyAuth
+// @Summary 刷新令牌(刷新令牌每次使用后轮换，重复使用将吊销该登录的全部令牌)
+// @Param body body schema.RefreshTokenParam true "请求参数"
 // @Success 200 {object} schema.LoginTokenInfo
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
 // @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
 // @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
 // @Router /api/v1/pub/refresh-token [post]
 func (a *LoginMock) RefreshToken(c *gin.Context) {
 }
 
+// @Tags LoginAPI
+// @Summary 获取令牌验证公钥集合(JWKS)
+// @Success 200 {object} jwtauth.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (a *LoginMock) GetJWKS(c *gin.Context) {
+}
+
 // @Tags LoginAPI
 // @Summary 获取当前用户信息
 // @Security ApiKeyAuth
@@ -77,10 +119,123 @@ func (a *LoginMock) QueryUserMenuTree(c *gin.Context) {
 }
 
 // @Tags LoginAPI
-// @Summary 更新个人密码
+// @Summary 批量校验当前用户的权限
+// @Security ApiKeyAuth
+// @Param body body schema.PermissionCheckParam true "校验项(菜单路由及动作编号，或请求方式及请求路径)"
+// @Success 200 {object} schema.ListResult{list=[]schema.PermissionCheckResult} "校验结果"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/permissions [post]
+func (a *LoginMock) CheckPermissions(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 获取两步验证密钥(重新获取将替换未完成绑定的密钥)
+// @Security ApiKeyAuth
+// @Success 200 {object} schema.MFAEnrollment
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:已启用两步验证}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/mfa [post]
+func (a *LoginMock) EnrollMFA(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 响应两步验证密钥二维码
+// @Security ApiKeyAuth
+// @Produce image/png
+// @Success 200 "二维码"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:请先获取两步验证密钥}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/mfa/qrcode [get]
+func (a *LoginMock) ResMFAQRCode(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 校验动态口令完成两步验证绑定(返回一次性恢复码)
+// @Security ApiKeyAuth
+// @Param body body schema.MFACodeParam true "请求参数"
+// @Success 200 {object} schema.MFARecoveryCodes
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:动态口令不正确}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/mfa [put]
+func (a *LoginMock) ActivateMFA(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 查询当前用户API密钥
+// @Security ApiKeyAuth
+// @Success 200 {object} schema.ListResult{list=[]schema.UserAPIKey} "查询结果"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/apikeys [get]
+func (a *LoginMock) QueryAPIKeys(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 创建当前用户API密钥(密钥仅返回一次，使用方式 Authorization: Bearer <key>)
+// @Security ApiKeyAuth
+// @Param body body schema.UserAPIKeyCreateParam true "请求参数"
+// @Success 200 {object} schema.UserAPIKeyCreateResult
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:API密钥不能用于创建新的API密钥}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/apikeys [post]
+func (a *LoginMock) CreateAPIKey(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 吊销当前用户API密钥
+// @Security ApiKeyAuth
+// @Param kid path int true "API密钥ID"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/apikeys/{kid} [delete]
+func (a *LoginMock) DeleteAPIKey(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 查询当前用户登录会话
+// @Security ApiKeyAuth
+// @Success 200 {object} schema.ListResult{list=[]schema.UserSession} "查询结果"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/sessions [get]
+func (a *LoginMock) QuerySessions(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 吊销当前用户登录会话
+// @Security ApiKeyAuth
+// @Param sid path string true "会话ID"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/sessions/{sid} [delete]
+func (a *LoginMock) RevokeSession(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 吊销当前用户全部登录会话
+// @Security ApiKeyAuth
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/pub/current/sessions [delete]
+func (a *LoginMock) RevokeAllSessions(c *gin.Context) {
+}
+
+// @Tags LoginAPI
+// @Summary 更新个人密码(旧密码及新密码均为明文，新密码需符合密码策略)
 // @Security ApiKeyAuth
 // @Param body body schema.UpdatePasswordParam true "请求参数"
 // @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult{error=schema.ErrorItem{details=[]schema.PasswordViolation}} "{error:{code:0,message:密码不符合安全策略,details:[]}}"
 // @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
 // @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
 // @Router /api/v1/pub/current/password [put]
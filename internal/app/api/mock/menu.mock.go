@@ -38,6 +38,18 @@ func (a *MenuMock) Query(c *gin.Context) {
 func (a *MenuMock) QueryTree(c *gin.Context) {
 }
 
+// @Tags MenuAPI
+// @Summary 导出菜单数据文件(yaml，可通过菜单初始化或同步导入)
+// @Security ApiKeyAuth
+// @Produce application/x-yaml
+// @Success 200 {string} string "菜单数据文件"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/menus.export [get]
+func (a *MenuMock) Export(c *gin.Context) {
+}
+
 // @Tags MenuAPI
 // @Summary 查询指定数据
 // @Security ApiKeyAuth
@@ -75,16 +87,35 @@ func (a *MenuMock) Update(c *gin.Context) {
 }
 
 // @Tags MenuAPI
-// @Summary 删除数据
+// @Summary 删除数据(级联删除时同时删除下级菜单、动作、资源及所有租户角色的授权，并返回影响范围)
 // @Security ApiKeyAuth
 // @Param id path int true "唯一标识"
-// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Param cascade query bool false "级联删除"
+// @Param current query int false "失去访问权限的用户的分页索引(级联删除时有效)" default(1)
+// @Param pageSize query int false "失去访问权限的用户的分页大小(级联删除时有效，最大100)" default(10)
+// @Success 200 {object} schema.MenuDeleteImpact "{status:OK}或级联删除的影响范围"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
 // @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
 // @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
 // @Router /api/v1/menus/{id} [delete]
 func (a *MenuMock) Delete(c *gin.Context) {
 }
 
+// @Tags MenuAPI
+// @Summary 预览级联删除的影响范围(删除的菜单、动作，移除授权的角色及失去访问权限的用户，用户分页返回)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param current query int false "失去访问权限的用户的分页索引" default(1)
+// @Param pageSize query int false "失去访问权限的用户的分页大小(最大100)" default(10)
+// @Success 200 {object} schema.MenuDeleteImpact
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/menus/{id}/impact [get]
+func (a *MenuMock) DeleteImpact(c *gin.Context) {
+}
+
 // @Tags MenuAPI
 // @Summary 启用数据
 // @Security ApiKeyAuth
@@ -106,3 +137,28 @@ func (a *MenuMock) Enable(c *gin.Context) {
 // @Router /api/v1/menus/{id}/disable [patch]
 func (a *MenuMock) Disable(c *gin.Context) {
 }
+
+// @Tags MenuAPI
+// @Summary 移动菜单(包含下级菜单)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param body body schema.MenuMoveParam true "移动参数"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/menus/{id}/move [put]
+func (a *MenuMock) Move(c *gin.Context) {
+}
+
+// @Tags MenuAPI
+// @Summary 批量调整菜单的父级及排序值(拖拽排序的结果，在同一事务中执行)
+// @Security ApiKeyAuth
+// @Param body body []schema.MenuSortItem true "排序项列表"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/menus.sort [put]
+func (a *MenuMock) Sort(c *gin.Context) {
+}
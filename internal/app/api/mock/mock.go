@@ -5,6 +5,9 @@ import "github.com/google/wire"
 var MockSet = wire.NewSet(
 	LoginSet,
 	MenuSet,
+	DeptSet,
 	RoleSet,
 	UserSet,
+	PermissionSet,
+	TenantSet,
 ) // end
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+var PermissionSet = wire.NewSet(wire.Struct(new(PermissionMock), "*"))
+
+type PermissionMock struct{}
+
+// @Tags PermissionAPI
+// @Summary 权限诊断(查看用户或角色访问接口的判定过程)
+// @Security ApiKeyAuth
+// @Param userID query int false "用户ID(与角色ID二选一)"
+// @Param roleID query int false "角色ID(与用户ID二选一)"
+// @Param method query string true "请求方式"
+// @Param path query string true "请求路径"
+// @Success 200 {object} schema.PermissionExplain
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 404 {object} schema.ErrorResult "{error:{code:0,message:not found}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/permissions.explain [get]
+func (a *PermissionMock) Explain(c *gin.Context) {
+}
@@ -0,0 +1,103 @@
+package mock
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+var TenantSet = wire.NewSet(wire.Struct(new(TenantMock), "*"))
+
+type TenantMock struct{}
+
+// @Tags TenantAPI
+// @Summary 查询数据(仅平台用户)
+// @Security ApiKeyAuth
+// @Param current query int true "分页索引" default(1)
+// @Param pageSize query int true "分页大小" default(10)
+// @Param queryValue query string false "查询值(租户编号或名称)"
+// @Param status query int false "状态(1:启用 2:停用)"
+// @Success 200 {object} schema.ListResult{list=[]schema.Tenant} "查询结果"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants [get]
+func (a *TenantMock) Query(c *gin.Context) {
+}
+
+// @Tags TenantAPI
+// @Summary 查询指定数据(仅平台用户)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.Tenant
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 404 {object} schema.ErrorResult "{error:{code:0,message:not found}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants/{id} [get]
+func (a *TenantMock) Get(c *gin.Context) {
+}
+
+// @Tags TenantAPI
+// @Summary 创建数据(仅平台用户，指定管理员用户名时同时创建租户的超级管理员，管理员密码为明文)
+// @Security ApiKeyAuth
+// @Param body body schema.Tenant true "创建数据"
+// @Success 200 {object} schema.IDResult
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants [post]
+func (a *TenantMock) Create(c *gin.Context) {
+}
+
+// @Tags TenantAPI
+// @Summary 更新数据(仅平台用户)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param body body schema.Tenant true "更新数据"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants/{id} [put]
+func (a *TenantMock) Update(c *gin.Context) {
+}
+
+// @Tags TenantAPI
+// @Summary 删除数据(仅平台用户，租户下存在用户或角色时不允许删除)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 400 {object} schema.ErrorResult "{error:{code:0,message:bad request}}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants/{id} [delete]
+func (a *TenantMock) Delete(c *gin.Context) {
+}
+
+// @Tags TenantAPI
+// @Summary 启用数据(仅平台用户)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants/{id}/enable [patch]
+func (a *TenantMock) Enable(c *gin.Context) {
+}
+
+// @Tags TenantAPI
+// @Summary 停用数据(仅平台用户，停用后该租户的请求均被拒绝)
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 403 {object} schema.ErrorResult "{error:{code:0,message:仅平台用户可以执行该操作}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/tenants/{id}/disable [patch]
+func (a *TenantMock) Disable(c *gin.Context) {
+}
@@ -17,6 +17,7 @@ type UserMock struct {
 // @Param pageSize query int true "分页大小" default(10)
 // @Param queryValue query string false "查询值"
 // @Param roleIDs query string false "角色ID(多个以英文逗号分隔)"
+// @Param deptID query int false "部门ID(包含下级部门)"
 // @Param status query int false "状态(1:启用 2:停用)"
 // @Success 200 {object} schema.ListResult{list=[]schema.UserShow} "查询结果"
 // @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
@@ -37,7 +38,7 @@ func (a *UserMock) Get(c *gin.Context) {
 }
 
 // @Tags UserAPI
-// @Summary 创建数据
+// @Summary 创建数据(密码为明文，需符合密码策略)
 // @Security ApiKeyAuth
 // @Param body body schema.User true "创建数据"
 // @Success 200 {object} schema.IDResult
@@ -49,7 +50,7 @@ func (a *UserMock) Create(c *gin.Context) {
 }
 
 // @Tags UserAPI
-// @Summary 更新数据
+// @Summary 更新数据(密码为明文，为空时不修改，需符合密码策略)
 // @Security ApiKeyAuth
 // @Param id path int true "唯一标识"
 // @Param body body schema.User true "更新数据"
@@ -93,3 +94,82 @@ func (a *UserMock) Enable(c *gin.Context) {
 // @Router /api/v1/users/{id}/disable [patch]
 func (a *UserMock) Disable(c *gin.Context) {
 }
+
+// @Tags UserAPI
+// @Summary 解除登录锁定
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/unlock [patch]
+func (a *UserMock) Unlock(c *gin.Context) {
+}
+
+// @Tags UserAPI
+// @Summary 重置两步验证
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/mfa [delete]
+func (a *UserMock) ResetMFA(c *gin.Context) {
+}
+
+// @Tags UserAPI
+// @Summary 查询用户API密钥
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.ListResult{list=[]schema.UserAPIKey} "查询结果"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/apikeys [get]
+func (a *UserMock) QueryAPIKeys(c *gin.Context) {
+}
+
+// @Tags UserAPI
+// @Summary 吊销用户API密钥
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param kid path int true "API密钥ID"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/apikeys/{kid} [delete]
+func (a *UserMock) DeleteAPIKey(c *gin.Context) {
+}
+
+// @Tags UserAPI
+// @Summary 查询用户登录会话
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.ListResult{list=[]schema.UserSession} "查询结果"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/sessions [get]
+func (a *UserMock) QuerySessions(c *gin.Context) {
+}
+
+// @Tags UserAPI
+// @Summary 吊销用户登录会话
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Param sid path string true "会话ID"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/sessions/{sid} [delete]
+func (a *UserMock) RevokeSession(c *gin.Context) {
+}
+
+// @Tags UserAPI
+// @Summary 吊销用户全部登录会话
+// @Security ApiKeyAuth
+// @Param id path int true "唯一标识"
+// @Success 200 {object} schema.StatusResult "{status:OK}"
+// @Failure 401 {object} schema.ErrorResult "{error:{code:9999,message:invalid signature}}"
+// @Failure 500 {object} schema.ErrorResult "{error:{code:0,message:internal server error}}"
+// @Router /api/v1/users/{id}/sessions [delete]
+func (a *UserMock) RevokeAllSessions(c *gin.Context) {
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/internal/app/service"
+)
+
+var PermissionSet = wire.NewSet(wire.Struct(new(PermissionAPI), "*"))
+
+type PermissionAPI struct {
+	PermissionSrv *service.PermissionSrv
+}
+
+func (a *PermissionAPI) Explain(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.PermissionExplainParam
+	if err := ginx.ParseQuery(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	result, err := a.PermissionSrv.Explain(ctx, params)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, result)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/internal/app/service"
+)
+
+var TenantSet = wire.NewSet(wire.Struct(new(TenantAPI), "*"))
+
+type TenantAPI struct {
+	TenantSrv *service.TenantSrv
+}
+
+func (a *TenantAPI) Query(c *gin.Context) {
+	ctx := c.Request.Context()
+	var params schema.TenantQueryParam
+	if err := ginx.ParseQuery(c, &params); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	params.Pagination = true
+	result, err := a.TenantSrv.Query(ctx, params, schema.TenantQueryOptions{
+		OrderFields: schema.NewOrderFields(schema.NewOrderField("id", schema.OrderByDESC)),
+	})
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResPage(c, result.Data, result.PageResult)
+}
+
+func (a *TenantAPI) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	item, err := a.TenantSrv.Get(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, item)
+}
+
+func (a *TenantAPI) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.Tenant
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	item.Creator = contextx.FromUserID(ctx)
+	result, err := a.TenantSrv.Create(ctx, item)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResSuccess(c, result)
+}
+
+func (a *TenantAPI) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+	var item schema.Tenant
+	if err := ginx.ParseJSON(c, &item); err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+
+	err := a.TenantSrv.Update(ctx, ginx.ParseParamID(c, "id"), item)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *TenantAPI) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.TenantSrv.Delete(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *TenantAPI) Enable(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.TenantSrv.UpdateStatus(ctx, ginx.ParseParamID(c, "id"), 1)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *TenantAPI) Disable(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.TenantSrv.UpdateStatus(ctx, ginx.ParseParamID(c, "id"), 2)
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
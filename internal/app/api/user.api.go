@@ -17,7 +17,8 @@ import (
 var UserSet = wire.NewSet(wire.Struct(new(UserAPI), "*"))
 
 type UserAPI struct {
-	UserSrv *service.UserSrv
+	UserSrv    *service.UserSrv
+	SessionSrv *service.SessionSrv
 }
 
 func (a *UserAPI) Query(c *gin.Context) {
@@ -115,3 +116,73 @@ func (a *UserAPI) Disable(c *gin.Context) {
 	}
 	ginx.ResOK(c)
 }
+
+func (a *UserAPI) Unlock(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.UserSrv.Unlock(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *UserAPI) ResetMFA(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.UserSrv.ResetMFA(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *UserAPI) QueryAPIKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+	keys, err := a.UserSrv.QueryAPIKeys(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResList(c, keys)
+}
+
+func (a *UserAPI) DeleteAPIKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.UserSrv.DeleteAPIKey(ctx, ginx.ParseParamID(c, "id"), ginx.ParseParamID(c, "kid"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *UserAPI) QuerySessions(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessions, err := a.SessionSrv.Query(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResList(c, sessions)
+}
+
+func (a *UserAPI) RevokeSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.SessionSrv.Revoke(ctx, ginx.ParseParamID(c, "id"), c.Param("sid"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
+
+func (a *UserAPI) RevokeAllSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+	err := a.SessionSrv.RevokeAll(ctx, ginx.ParseParamID(c, "id"))
+	if err != nil {
+		ginx.ResError(c, err)
+		return
+	}
+	ginx.ResOK(c)
+}
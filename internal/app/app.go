@@ -3,16 +3,20 @@ package app
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/LyricTian/captcha"
 	"github.com/LyricTian/captcha/store"
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
 	"github.com/LyricTian/gin-admin/v8/pkg/logger"
 	"github.com/go-redis/redis"
 	"github.com/google/gops/agent"
@@ -94,8 +98,26 @@ func Init(ctx context.Context, opts ...Option) (func(), error) {
 		return nil, err
 	}
 
-	if config.C.Menu.Enable && config.C.Menu.Data != "" {
-		err = injector.MenuSrv.InitData(ctx, config.C.Menu.Data)
+	err = injector.SuperAdminSrv.InitData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg := config.C.Menu; cfg.Enable && cfg.Data != "" && cfg.Sync {
+		result, err := injector.MenuSrv.Sync(ctx, cfg.Data, schema.MenuSyncOptions{Prune: cfg.Prune})
+		if err != nil {
+			return nil, err
+		}
+		logMenuSyncResult(ctx, result)
+	} else if cfg.Enable && cfg.Data != "" {
+		err = injector.MenuSrv.InitData(ctx, cfg.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.C.RouteCheck.Enable {
+		err = CheckRoutes(ctx, injector, config.C.RouteCheck.Strict)
 		if err != nil {
 			return nil, err
 		}
@@ -176,6 +198,199 @@ func InitHTTPServer(ctx context.Context, handler http.Handler) func() {
 	}
 }
 
+// CheckRoutes 检查需要权限校验的接口路由与菜单动作资源，strict为true时存在问题返回错误
+func CheckRoutes(ctx context.Context, injector *Injector, strict bool) error {
+	routes := injector.Router.PermissionRoutes(injector.Engine)
+	result, err := injector.PermissionSrv.CheckRoutes(ctx, routes)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range result.UnprotectedRoutes {
+		logger.WithContext(ctx).Warnf("Route [%s %s] has no permission resource", item.Method, item.Path)
+	}
+	for _, item := range result.StaleResources {
+		logger.WithContext(ctx).Warnf("Resource [%s %s] of menu [%s] action [%s] matches no route",
+			item.Method, item.Path, item.MenuName, item.ActionCode)
+	}
+	for _, item := range result.OverlappingRoutes {
+		patterns := make([]string, len(item.Patterns))
+		for i, p := range item.Patterns {
+			patterns[i] = p.Method + " " + p.Path
+		}
+		logger.WithContext(ctx).Warnf("Route [%s %s] is matched by multiple resources: %s",
+			item.Method, item.Path, strings.Join(patterns, ", "))
+	}
+
+	if result.HasProblems() {
+		msg := fmt.Sprintf("Route check: %d routes, %d unprotected routes, %d stale resources, %d overlapping routes",
+			len(routes), len(result.UnprotectedRoutes), len(result.StaleResources), len(result.OverlappingRoutes))
+		if strict {
+			return errors.New(msg)
+		}
+		logger.WithContext(ctx).Warnf(msg)
+		return nil
+	}
+
+	logger.WithContext(ctx).Infof("Route check: %d routes passed", len(routes))
+	return nil
+}
+
+// RunRouteCheck 初始化菜单数据(指定了菜单数据文件时)并检查接口路由与菜单动作资源
+func RunRouteCheck(ctx context.Context, strict bool, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	config.MustLoad(o.ConfigFile)
+	if v := o.ModelFile; v != "" {
+		config.C.Casbin.Model = v
+	}
+	if v := o.MenuFile; v != "" {
+		config.C.Menu.Data = v
+	}
+
+	loggerCleanFunc, err := InitLogger()
+	if err != nil {
+		return err
+	}
+	defer loggerCleanFunc()
+
+	injector, injectorCleanFunc, err := BuildInjector()
+	if err != nil {
+		return err
+	}
+	defer injectorCleanFunc()
+
+	if v := o.MenuFile; v != "" {
+		err = injector.MenuSrv.InitData(ctx, v)
+		if err != nil {
+			return err
+		}
+	}
+	return CheckRoutes(ctx, injector, strict)
+}
+
+// SyncMenu 按菜单数据文件同步菜单并输出变更(预览时不修改数据)
+func SyncMenu(ctx context.Context, params schema.MenuSyncOptions, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	config.MustLoad(o.ConfigFile)
+	if v := o.ModelFile; v != "" {
+		config.C.Casbin.Model = v
+	}
+	if v := o.MenuFile; v != "" {
+		config.C.Menu.Data = v
+	}
+	if config.C.Menu.Data == "" {
+		return errors.New("menu data file is not specified")
+	}
+
+	loggerCleanFunc, err := InitLogger()
+	if err != nil {
+		return err
+	}
+	defer loggerCleanFunc()
+
+	injector, injectorCleanFunc, err := BuildInjector()
+	if err != nil {
+		return err
+	}
+	defer injectorCleanFunc()
+
+	result, err := injector.MenuSrv.Sync(ctx, config.C.Menu.Data, params)
+	if err != nil {
+		return err
+	}
+	logMenuSyncResult(ctx, result)
+	return nil
+}
+
+func logMenuSyncResult(ctx context.Context, result *schema.MenuSyncResult) {
+	for _, item := range result.Changes {
+		logger.WithContext(ctx).Infof("Menu sync %s", item.String())
+	}
+
+	msg := fmt.Sprintf("Menu sync: %d changes", len(result.Changes))
+	if result.DryRun {
+		msg += " (dry run, nothing is changed)"
+	}
+	logger.WithContext(ctx).Infof(msg)
+}
+
+// ExportMenu 导出菜单数据文件(未指定输出文件时输出到标准输出)
+func ExportMenu(ctx context.Context, output string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	config.MustLoad(o.ConfigFile)
+	if v := o.ModelFile; v != "" {
+		config.C.Casbin.Model = v
+	}
+
+	loggerCleanFunc, err := InitLogger()
+	if err != nil {
+		return err
+	}
+	defer loggerCleanFunc()
+
+	injector, injectorCleanFunc, err := BuildInjector()
+	if err != nil {
+		return err
+	}
+	defer injectorCleanFunc()
+
+	data, err := injector.MenuSrv.Export(ctx)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(output, data, 0644)
+}
+
+// CreateSuperAdmin 创建超级管理员(用户名已存在时将该用户设为超级管理员)
+func CreateSuperAdmin(ctx context.Context, params schema.SuperAdminParam, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	config.MustLoad(o.ConfigFile)
+	if v := o.ModelFile; v != "" {
+		config.C.Casbin.Model = v
+	}
+
+	loggerCleanFunc, err := InitLogger()
+	if err != nil {
+		return err
+	}
+	defer loggerCleanFunc()
+
+	injector, injectorCleanFunc, err := BuildInjector()
+	if err != nil {
+		return err
+	}
+	defer injectorCleanFunc()
+
+	user, err := injector.SuperAdminSrv.Create(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	logger.WithContext(ctx).Infof("Super admin [%s] is ready,#id %d", user.UserName, user.ID)
+	return nil
+}
+
 func Run(ctx context.Context, opts ...Option) error {
 	state := 1
 	sc := make(chan os.Signal, 1)
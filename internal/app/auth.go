@@ -1,6 +1,8 @@
 package app
 
 import (
+	"io/ioutil"
+
 	jwt "github.com/dgrijalva/jwt-go"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
@@ -10,29 +12,58 @@ import (
 	"github.com/LyricTian/gin-admin/v8/pkg/auth/jwtauth/store/redis"
 )
 
-func InitAuth() (auth.Auther, func(), error) {
+func InitJWTKeySet() (*jwtauth.KeySet, error) {
+	cfg := config.C.JWTAuth
+
+	var method jwt.SigningMethod = jwt.SigningMethodHS512
+	if cfg.SigningMethod != "" {
+		m, err := jwtauth.ParseSigningMethod(cfg.SigningMethod)
+		if err != nil {
+			return nil, err
+		}
+		method = m
+	}
+
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		return jwtauth.NewKeySet(method, []byte(cfg.SigningKey))
+	}
+
+	buf, err := ioutil.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := jwtauth.ParsePrivateKeyPEM(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifyKeys []interface{}
+	for _, name := range cfg.PublicKeyFiles {
+		buf, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, err := jwtauth.ParsePublicKeyPEM(buf)
+		if err != nil {
+			return nil, err
+		}
+		verifyKeys = append(verifyKeys, publicKey)
+	}
+
+	return jwtauth.NewKeySet(method, privateKey, verifyKeys...)
+}
+
+func InitAuth(keySet *jwtauth.KeySet) (auth.Auther, func(), error) {
 	cfg := config.C.JWTAuth
 
 	var opts []jwtauth.Option
 	opts = append(opts, jwtauth.SetExpired(cfg.Expired))
-	opts = append(opts, jwtauth.SetSigningKey([]byte(cfg.SigningKey)))
-	opts = append(opts, jwtauth.SetKeyfunc(func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, auth.ErrInvalidToken
-		}
-		return []byte(cfg.SigningKey), nil
-	}))
-
-	var method jwt.SigningMethod
-	switch cfg.SigningMethod {
-	case "HS256":
-		method = jwt.SigningMethodHS256
-	case "HS384":
-		method = jwt.SigningMethodHS384
-	default:
-		method = jwt.SigningMethodHS512
+	if cfg.RefreshExpired > 0 {
+		opts = append(opts, jwtauth.SetRefreshExpired(cfg.RefreshExpired))
 	}
-	opts = append(opts, jwtauth.SetSigningMethod(method))
+	opts = append(opts, jwtauth.SetKeySet(keySet))
 
 	var store jwtauth.Storer
 	switch cfg.Store {
@@ -1,15 +1,23 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/casbin/casbin/v2/persist"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher/poll"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher/redis"
+	"github.com/LyricTian/gin-admin/v8/pkg/logger"
 )
 
-func InitCasbin(adapter persist.Adapter) (*casbin.SyncedEnforcer, func(), error) {
+func InitCasbin(adapter persist.Adapter, w *watcher.Watcher) (*casbin.SyncedEnforcer, func(), error) {
 	cfg := config.C.Casbin
 	if cfg.Model == "" {
 		return new(casbin.SyncedEnforcer), nil, nil
@@ -27,6 +35,15 @@ func InitCasbin(adapter persist.Adapter) (*casbin.SyncedEnforcer, func(), error)
 	}
 	e.EnableEnforce(cfg.Enable)
 
+	// 不绑定到enforcer(按需加载用户角色的增量变更无需通知其他实例)，策略变更由各服务显式通知
+	if config.C.CasbinWatcher.Enable {
+		_ = w.SetUpdateCallback(func(string) {
+			if err := e.LoadPolicy(); err != nil {
+				logger.WithContext(context.Background()).Errorf("Reload casbin policy error: %s", err.Error())
+			}
+		})
+	}
+
 	cleanFunc := func() {}
 	if cfg.AutoLoad {
 		e.StartAutoLoadPolicy(time.Duration(cfg.AutoLoadInternal) * time.Second)
@@ -37,3 +54,37 @@ func InitCasbin(adapter persist.Adapter) (*casbin.SyncedEnforcer, func(), error)
 
 	return e, cleanFunc, nil
 }
+
+func InitCasbinWatcher(repo *dao.PolicyVersionRepo) (*watcher.Watcher, func(), error) {
+	cfg := config.C.CasbinWatcher
+
+	var backends []watcher.Backend
+	if cfg.Enable {
+		for _, name := range cfg.Backends {
+			switch strings.ToLower(name) {
+			case "redis":
+				rcfg := config.C.Redis
+				backends = append(backends, redis.NewBackend(&redis.Config{
+					Addr:     rcfg.Addr,
+					Password: rcfg.Password,
+					Channel:  cfg.Channel,
+				}))
+			case "db":
+				backends = append(backends, poll.NewBackend(repo, time.Duration(cfg.PollInterval)*time.Second))
+			default:
+				for _, b := range backends {
+					_ = b.Close()
+				}
+				return nil, nil, fmt.Errorf("unknown casbin watcher backend: %s", name)
+			}
+		}
+	}
+
+	w := watcher.New(backends, watcher.SetErrorHandler(func(err error) {
+		logger.WithContext(context.Background()).Errorf("Casbin watcher error: %s", err.Error())
+	}))
+	cleanFunc := func() {
+		w.Close()
+	}
+	return w, cleanFunc, nil
+}
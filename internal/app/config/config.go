@@ -56,28 +56,38 @@ func PrintWithJSON() {
 }
 
 type Config struct {
-	RunMode      string
-	WWW          string
-	Swagger      bool
-	PrintConfig  bool
-	HTTP         HTTP
-	Menu         Menu
-	Casbin       Casbin
-	Log          Log
-	LogGormHook  LogGormHook
-	LogMongoHook LogMongoHook
-	Root         Root
-	JWTAuth      JWTAuth
-	Monitor      Monitor
-	Captcha      Captcha
-	RateLimiter  RateLimiter
-	CORS         CORS
-	GZIP         GZIP
-	Redis        Redis
-	Gorm         Gorm
-	MySQL        MySQL
-	Postgres     Postgres
-	Sqlite3      Sqlite3
+	RunMode        string
+	WWW            string
+	Swagger        bool
+	PrintConfig    bool
+	HTTP           HTTP
+	Menu           Menu
+	RouteCheck     RouteCheck
+	Casbin         Casbin
+	CasbinWatcher  CasbinWatcher
+	Log            Log
+	LogGormHook    LogGormHook
+	LogMongoHook   LogMongoHook
+	SuperAdmin     SuperAdmin
+	Tenant         Tenant
+	JWTAuth        JWTAuth
+	PasswordHash   PasswordHash
+	PasswordPolicy PasswordPolicy
+	LoginLockout   LoginLockout
+	MFA            MFA
+	OIDC           OIDC
+	Login          Login
+	LDAP           LDAP
+	Monitor        Monitor
+	Captcha        Captcha
+	RateLimiter    RateLimiter
+	CORS           CORS
+	GZIP           GZIP
+	Redis          Redis
+	Gorm           Gorm
+	MySQL          MySQL
+	Postgres       Postgres
+	Sqlite3        Sqlite3
 }
 
 func (c *Config) IsDebugMode() bool {
@@ -87,6 +97,13 @@ func (c *Config) IsDebugMode() bool {
 type Menu struct {
 	Enable bool
 	Data   string
+	Sync   bool
+	Prune  bool
+}
+
+type RouteCheck struct {
+	Enable bool
+	Strict bool
 }
 
 type Casbin struct {
@@ -95,6 +112,15 @@ type Casbin struct {
 	Model            string
 	AutoLoad         bool
 	AutoLoadInternal int
+	LazyLoadUser     bool
+	UserCacheSize    int
+}
+
+type CasbinWatcher struct {
+	Enable       bool
+	Backends     []string
+	Channel      string
+	PollInterval int
 }
 
 type LogHook string
@@ -129,24 +155,115 @@ type LogMongoHook struct {
 	Collection string
 }
 
-type Root struct {
-	UserID   uint64
+type SuperAdmin struct {
 	UserName string
 	Password string
 	RealName string
 }
 
+type Tenant struct {
+	Enable bool
+	Header string
+	Domain string
+}
+
 type JWTAuth struct {
+	Enable         bool
+	SigningMethod  string
+	SigningKey     string
+	PrivateKeyFile string
+	PublicKeyFiles []string
+	Expired        int
+	RefreshExpired int
+	Store          string
+	FilePath       string
+	RedisDB        int
+	RedisPrefix    string
+}
+
+type PasswordHash struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Time    int
+	Argon2Memory  int
+	Argon2Threads int
+}
+
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	HistoryCount  int
+	MaxAge        int
+}
+
+type LoginLockout struct {
+	Enable         bool
+	Store          string
+	RedisDB        int
+	RedisPrefix    string
+	MaxFailures    int
+	IPMaxFailures  int
+	Window         int
+	LockDuration   int
+	IPLockDuration int
+	BaseDelay      int
+	MaxDelay       int
+}
+
+type MFA struct {
+	Issuer           string
+	ChallengeExpired int
+	Skew             int
+	RecoveryCodes    int
+}
+
+type OIDC struct {
 	Enable        bool
-	SigningMethod string
-	SigningKey    string
-	Expired       int
-	Store         string
-	FilePath      string
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	UsernameClaim string
+	GroupsClaim   string
+	AutoCreate    bool
+	LinkByEmail   bool
+	GroupRoles    map[string][]string
+	StateStore    string
 	RedisDB       int
 	RedisPrefix   string
 }
 
+type Login struct {
+	Authenticators []string
+}
+
+type LDAP struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	Timeout            int
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	IDAttr             string
+	UserNameAttr       string
+	RealNameAttr       string
+	EmailAttr          string
+	PhoneAttr          string
+	GroupAttr          string
+	GroupBaseDN        string
+	GroupFilter        string
+	GroupNameAttr      string
+	AutoCreate         bool
+	LinkByUserName     bool
+	GroupRoles         map[string][]string
+}
+
 type HTTP struct {
 	Host               string
 	Port               int
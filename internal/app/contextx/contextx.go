@@ -10,7 +10,12 @@ type (
 	transLockCtx struct{}
 	userIDCtx    struct{}
 	userNameCtx  struct{}
+	scopeCtx     struct{}
+	apiKeyCtx    struct{}
+	dataScopeCtx struct{}
+	noScopeCtx   struct{}
 	traceIDCtx   struct{}
+	tenantIDCtx  struct{}
 )
 
 // Wrap transaction context
@@ -69,6 +74,80 @@ func FromUserName(ctx context.Context) string {
 	return ""
 }
 
+func NewTokenScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeCtx{}, scope)
+}
+
+func FromTokenScope(ctx context.Context) string {
+	v := ctx.Value(scopeCtx{})
+	if v != nil {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+type apiKey struct {
+	id         uint64
+	roleIDs    []string
+	restricted bool
+}
+
+// NewAPIKey 记录通过API密钥认证的密钥ID及限定的角色(restricted为false表示不限定角色)
+func NewAPIKey(ctx context.Context, id uint64, roleIDs []string, restricted bool) context.Context {
+	return context.WithValue(ctx, apiKeyCtx{}, apiKey{id: id, roleIDs: roleIDs, restricted: restricted})
+}
+
+func FromAPIKeyID(ctx context.Context) uint64 {
+	if v, ok := ctx.Value(apiKeyCtx{}).(apiKey); ok {
+		return v.id
+	}
+	return 0
+}
+
+func FromAPIKeyRoles(ctx context.Context) ([]string, bool) {
+	if v, ok := ctx.Value(apiKeyCtx{}).(apiKey); ok {
+		return v.roleIDs, v.restricted
+	}
+	return nil, false
+}
+
+// NewDataScope 限定只能访问指定创建者的数据(未设置表示不限定)
+func NewDataScope(ctx context.Context, creators []uint64) context.Context {
+	return context.WithValue(ctx, dataScopeCtx{}, creators)
+}
+
+func FromDataScope(ctx context.Context) ([]uint64, bool) {
+	v, ok := ctx.Value(dataScopeCtx{}).([]uint64)
+	return v, ok
+}
+
+// NewNoDataScope 忽略数据范围(用于唯一性校验等内部查询)
+func NewNoDataScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noScopeCtx{}, true)
+}
+
+func FromNoDataScope(ctx context.Context) bool {
+	v := ctx.Value(noScopeCtx{})
+	return v != nil && v.(bool)
+}
+
+// NewTenantID 记录当前请求所属的租户(0为平台，未设置表示不按租户隔离)
+func NewTenantID(ctx context.Context, tenantID uint64) context.Context {
+	return context.WithValue(ctx, tenantIDCtx{}, tenantID)
+}
+
+// NewNoTenant 不按租户隔离(平台维护所有租户共享的数据时使用)
+func NewNoTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantIDCtx{}, nil)
+}
+
+func FromTenantID(ctx context.Context) (uint64, bool) {
+	v, ok := ctx.Value(tenantIDCtx{}).(uint64)
+	return v, ok
+}
+
 func NewTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, traceIDCtx{}, traceID)
 }
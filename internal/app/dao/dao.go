@@ -7,8 +7,11 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/dept"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/menu"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/policy"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/role"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/tenant"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/user"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
 ) // end
@@ -19,9 +22,21 @@ var RepoSet = wire.NewSet(
 	menu.MenuActionResourceSet,
 	menu.MenuActionSet,
 	menu.MenuSet,
+	dept.DeptSet,
+	policy.PolicyVersionSet,
 	role.RoleMenuSet,
+	role.RoleDataUserSet,
+	role.RoleParentSet,
 	role.RoleSet,
+	tenant.TenantSet,
 	user.UserRoleSet,
+	user.UserDeptSet,
+	user.UserPasswordSet,
+	user.UserMFASet,
+	user.UserRecoveryCodeSet,
+	user.UserAPIKeySet,
+	user.UserAPIKeyRoleSet,
+	user.UserIdentitySet,
 	user.UserSet,
 ) // end
 
@@ -31,9 +46,21 @@ type (
 	MenuActionResourceRepo = menu.MenuActionResourceRepo
 	MenuActionRepo         = menu.MenuActionRepo
 	MenuRepo               = menu.MenuRepo
+	DeptRepo               = dept.DeptRepo
+	PolicyVersionRepo      = policy.PolicyVersionRepo
 	RoleMenuRepo           = role.RoleMenuRepo
+	RoleDataUserRepo       = role.RoleDataUserRepo
+	RoleParentRepo         = role.RoleParentRepo
 	RoleRepo               = role.RoleRepo
+	TenantRepo             = tenant.TenantRepo
 	UserRoleRepo           = user.UserRoleRepo
+	UserDeptRepo           = user.UserDeptRepo
+	UserPasswordRepo       = user.UserPasswordRepo
+	UserMFARepo            = user.UserMFARepo
+	UserRecoveryCodeRepo   = user.UserRecoveryCodeRepo
+	UserAPIKeyRepo         = user.UserAPIKeyRepo
+	UserAPIKeyRoleRepo     = user.UserAPIKeyRoleRepo
+	UserIdentityRepo       = user.UserIdentityRepo
 	UserRepo               = user.UserRepo
 ) // end
 
@@ -43,13 +70,44 @@ func AutoMigrate(db *gorm.DB) error {
 		db = db.Set("gorm:table_options", "ENGINE=InnoDB")
 	}
 
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		new(menu.MenuActionResource),
 		new(menu.MenuAction),
 		new(menu.Menu),
+		new(dept.Dept),
+		new(policy.PolicyVersion),
 		new(role.RoleMenu),
+		new(role.RoleDataUser),
+		new(role.RoleParent),
 		new(role.Role),
+		new(tenant.Tenant),
 		new(user.UserRole),
+		new(user.UserDept),
+		new(user.UserPassword),
+		new(user.UserMFA),
+		new(user.UserRecoveryCode),
+		new(user.UserAPIKey),
+		new(user.UserAPIKeyRole),
+		new(user.UserIdentity),
 		new(user.User),
 	) // end
+	if err != nil {
+		return err
+	}
+
+	return dropUserNameIndex(db)
+}
+
+// 用户名调整为租户内唯一(tenant_id, user_name)，移除原用户名唯一索引
+func dropUserNameIndex(db *gorm.DB) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(user.User)); err != nil {
+		return err
+	}
+
+	name := db.NamingStrategy.IndexName(stmt.Schema.Table, "user_name")
+	if m := db.Migrator(); m.HasIndex(new(user.User), name) {
+		return m.DropIndex(new(user.User), name)
+	}
+	return nil
 }
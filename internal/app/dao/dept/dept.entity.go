@@ -0,0 +1,54 @@
+package dept
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetDeptDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(Dept))
+}
+
+type SchemaDept schema.Dept
+
+func (a SchemaDept) ToDept() *Dept {
+	item := new(Dept)
+	structure.Copy(a, item)
+	return item
+}
+
+type Dept struct {
+	util.Model
+	util.TenantModel
+	Name       string  `gorm:"size:100;index;default:'';not null;"` // 部门名称
+	ParentID   *uint64 `gorm:"index;default:0;"`                    // 父级内码
+	ParentPath *string `gorm:"size:512;index;default:'';"`          // 父级路径
+	Leader     *string `gorm:"size:50;"`                            // 负责人
+	Phone      *string `gorm:"size:20;"`                            // 联系电话
+	Email      *string `gorm:"size:255;"`                           // 邮箱
+	Status     int     `gorm:"index;default:0;"`                    // 状态(1:启用 2:禁用)
+	Sequence   int     `gorm:"index;default:0;"`                    // 排序值
+	Memo       *string `gorm:"size:1024;"`                          // 备注
+	Creator    uint64  `gorm:""`                                    // 创建人
+}
+
+func (a Dept) ToSchemaDept() *schema.Dept {
+	item := new(schema.Dept)
+	structure.Copy(a, item)
+	return item
+}
+
+type Depts []*Dept
+
+func (a Depts) ToSchemaDepts() []*schema.Dept {
+	list := make([]*schema.Dept, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaDept()
+	}
+	return list
+}
@@ -0,0 +1,125 @@
+package dept
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var DeptSet = wire.NewSet(wire.Struct(new(DeptRepo), "*"))
+
+type DeptRepo struct {
+	DB *gorm.DB
+}
+
+func (a *DeptRepo) getQueryOption(opts ...schema.DeptQueryOptions) schema.DeptQueryOptions {
+	var opt schema.DeptQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *DeptRepo) Query(ctx context.Context, params schema.DeptQueryParam, opts ...schema.DeptQueryOptions) (*schema.DeptQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetDeptDB(ctx, a.DB)
+	if v := params.IDs; len(v) > 0 {
+		db = db.Where("id IN (?)", v)
+	}
+	if v := params.Name; v != "" {
+		db = db.Where("name=?", v)
+	}
+	if v := params.ParentID; v != nil {
+		db = db.Where("parent_id=?", *v)
+	}
+	if v := params.PrefixParentPath; v != "" {
+		db = db.Where("parent_path LIKE ?", v+"%")
+	}
+	if v := params.Status; v != 0 {
+		db = db.Where("status=?", v)
+	}
+	if v := params.QueryValue; v != "" {
+		v = "%" + v + "%"
+		db = db.Where("name LIKE ?", v)
+	}
+
+	if len(opt.SelectFields) > 0 {
+		db = db.Select(opt.SelectFields)
+	}
+
+	if len(opt.OrderFields) > 0 {
+		db = db.Order(util.ParseOrder(opt.OrderFields))
+	}
+
+	var list Depts
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	qr := &schema.DeptQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaDepts(),
+	}
+
+	return qr, nil
+}
+
+func (a *DeptRepo) Get(ctx context.Context, id uint64, opts ...schema.DeptQueryOptions) (*schema.Dept, error) {
+	var item Dept
+	ok, err := util.FindOne(ctx, GetDeptDB(ctx, a.DB).Where("id=?", id), &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaDept(), nil
+}
+
+func (a *DeptRepo) Create(ctx context.Context, item schema.Dept) error {
+	eitem := SchemaDept(item).ToDept()
+	result := GetDeptDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *DeptRepo) Update(ctx context.Context, id uint64, item schema.Dept) error {
+	eitem := SchemaDept(item).ToDept()
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	return errors.WithStack(result.Error)
+}
+
+// Move 更新父级及父级路径(移动到顶级时需要更新零值)
+func (a *DeptRepo) Move(ctx context.Context, id, parentID uint64, parentPath string) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Updates(map[string]interface{}{
+		"parent_id":   parentID,
+		"parent_path": parentPath,
+	})
+	return errors.WithStack(result.Error)
+}
+
+func (a *DeptRepo) UpdateParentPath(ctx context.Context, id uint64, parentPath string) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Update("parent_path", parentPath)
+	return errors.WithStack(result.Error)
+}
+
+func (a *DeptRepo) UpdateSequence(ctx context.Context, id uint64, sequence int) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Update("sequence", sequence)
+	return errors.WithStack(result.Error)
+}
+
+func (a *DeptRepo) Delete(ctx context.Context, id uint64) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Delete(Dept{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *DeptRepo) UpdateStatus(ctx context.Context, id uint64, status int) error {
+	result := GetDeptDB(ctx, a.DB).Where("id=?", id).Update("status", status)
+	return errors.WithStack(result.Error)
+}
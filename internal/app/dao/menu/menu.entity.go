@@ -24,6 +24,7 @@ func (a SchemaMenu) ToMenu() *Menu {
 
 type Menu struct {
 	util.Model
+	Code       *string `gorm:"size:100;index;default:'';"`         // 菜单编号
 	Name       string  `gorm:"size:50;index;default:'';not null;"` // 菜单名称
 	Icon       *string `gorm:"size:255;"`                          // 菜单图标
 	Router     *string `gorm:"size:255;"`                          // 访问路由
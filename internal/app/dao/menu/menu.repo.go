@@ -32,6 +32,9 @@ func (a *MenuRepo) Query(ctx context.Context, params schema.MenuQueryParam, opts
 	if v := params.IDs; len(v) > 0 {
 		db = db.Where("id IN (?)", v)
 	}
+	if v := params.Code; v != "" {
+		db = db.Where("code=?", v)
+	}
 	if v := params.Name; v != "" {
 		db = db.Where("name=?", v)
 	}
@@ -41,6 +44,9 @@ func (a *MenuRepo) Query(ctx context.Context, params schema.MenuQueryParam, opts
 	if v := params.PrefixParentPath; v != "" {
 		db = db.Where("parent_path LIKE ?", v+"%")
 	}
+	if v := params.Routers; len(v) > 0 {
+		db = db.Where("router IN (?)", v)
+	}
 	if v := params.IsShow; v != 0 {
 		db = db.Where("show_status=?", v)
 	}
@@ -98,11 +104,33 @@ func (a *MenuRepo) Update(ctx context.Context, id uint64, item schema.Menu) erro
 	return errors.WithStack(result.Error)
 }
 
+// UpdateDefinition 更新菜单数据文件中定义的字段(包括零值)
+func (a *MenuRepo) UpdateDefinition(ctx context.Context, id uint64, item schema.Menu) error {
+	eitem := SchemaMenu(item).ToMenu()
+	result := GetMenuDB(ctx, a.DB).Where("id=?", id).
+		Select("code", "name", "icon", "router", "is_show", "status", "sequence", "parent_id", "parent_path").Updates(eitem)
+	return errors.WithStack(result.Error)
+}
+
+// Move 更新父级及父级路径(移动到顶级时需要更新零值)
+func (a *MenuRepo) Move(ctx context.Context, id, parentID uint64, parentPath string) error {
+	result := GetMenuDB(ctx, a.DB).Where("id=?", id).Updates(map[string]interface{}{
+		"parent_id":   parentID,
+		"parent_path": parentPath,
+	})
+	return errors.WithStack(result.Error)
+}
+
 func (a *MenuRepo) UpdateParentPath(ctx context.Context, id uint64, parentPath string) error {
 	result := GetMenuDB(ctx, a.DB).Where("id=?", id).Update("parent_path", parentPath)
 	return errors.WithStack(result.Error)
 }
 
+func (a *MenuRepo) UpdateSequence(ctx context.Context, id uint64, sequence int) error {
+	result := GetMenuDB(ctx, a.DB).Where("id=?", id).Update("sequence", sequence)
+	return errors.WithStack(result.Error)
+}
+
 func (a *MenuRepo) Delete(ctx context.Context, id uint64) error {
 	result := GetMenuDB(ctx, a.DB).Where("id=?", id).Delete(Menu{})
 	return errors.WithStack(result.Error)
@@ -24,6 +24,7 @@ func (a SchemaMenuAction) ToMenuAction() *MenuAction {
 
 type MenuAction struct {
 	util.Model
+	util.SharedTenantModel
 	MenuID uint64 `gorm:"index;not null;"` // 菜单ID
 	Code   string `gorm:"size:100;"`       // 动作编号
 	Name   string `gorm:"size:100;"`       // 动作名称
@@ -32,6 +32,9 @@ func (a *MenuActionRepo) Query(ctx context.Context, params schema.MenuActionQuer
 	if v := params.MenuID; v > 0 {
 		db = db.Where("menu_id=?", v)
 	}
+	if v := params.MenuIDs; len(v) > 0 {
+		db = db.Where("menu_id IN (?)", v)
+	}
 	if v := params.IDs; len(v) > 0 {
 		db = db.Where("id IN (?)", v)
 	}
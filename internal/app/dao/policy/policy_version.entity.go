@@ -0,0 +1,21 @@
+package policy
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+)
+
+// 版本号只保存一条记录
+const policyVersionID = 1
+
+func GetPolicyVersionDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(PolicyVersion))
+}
+
+type PolicyVersion struct {
+	util.Model
+	Version int64 `gorm:"not null;default:0;"` // 权限策略版本号(策略变更时加1)
+}
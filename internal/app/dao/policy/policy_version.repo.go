@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var PolicyVersionSet = wire.NewSet(wire.Struct(new(PolicyVersionRepo), "*"))
+
+type PolicyVersionRepo struct {
+	DB *gorm.DB
+}
+
+func (a *PolicyVersionRepo) Get(ctx context.Context) (int64, error) {
+	var item PolicyVersion
+	ok, err := util.FindOne(ctx, GetPolicyVersionDB(ctx, a.DB).Where("id=?", policyVersionID), &item)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	} else if !ok {
+		return 0, nil
+	}
+	return item.Version, nil
+}
+
+func (a *PolicyVersionRepo) Incr(ctx context.Context) error {
+	ok, err := a.incr(ctx)
+	if err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	// 首次变更时创建记录，并发创建失败时重新更新
+	result := GetPolicyVersionDB(ctx, a.DB).Create(&PolicyVersion{
+		Model:   util.Model{ID: policyVersionID},
+		Version: 1,
+	})
+	if result.Error == nil {
+		return nil
+	}
+
+	ok, err = a.incr(ctx)
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.WithStack(result.Error)
+	}
+	return nil
+}
+
+func (a *PolicyVersionRepo) incr(ctx context.Context) (bool, error) {
+	result := GetPolicyVersionDB(ctx, a.DB).Where("id=?", policyVersionID).UpdateColumn("version", gorm.Expr("version+1"))
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
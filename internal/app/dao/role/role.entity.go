@@ -24,11 +24,13 @@ func (a SchemaRole) ToRole() *Role {
 
 type Role struct {
 	util.Model
-	Name     string  `gorm:"size:100;index;default:'';not null;"` // 角色名称
-	Sequence int     `gorm:"index;default:0;"`                    // 排序值
-	Memo     *string `gorm:"size:1024;"`                          // 备注
-	Status   int     `gorm:"index;default:0;"`                    // 状态(1:启用 2:禁用)
-	Creator  uint64  `gorm:""`                                    // 创建者
+	util.TenantModel
+	Name      string  `gorm:"size:100;index;default:'';not null;"` // 角色名称
+	Sequence  int     `gorm:"index;default:0;"`                    // 排序值
+	Memo      *string `gorm:"size:1024;"`                          // 备注
+	Status    int     `gorm:"index;default:0;"`                    // 状态(1:启用 2:禁用)
+	DataScope int     `gorm:"default:1;"`                          // 数据范围(1:全部 2:仅本人 3:自定义 4:本部门及以下)
+	Creator   uint64  `gorm:""`                                    // 创建者
 }
 
 func (a Role) ToSchemaRole() *schema.Role {
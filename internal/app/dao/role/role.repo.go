@@ -28,13 +28,16 @@ func (a *RoleRepo) getQueryOption(opts ...schema.RoleQueryOptions) schema.RoleQu
 func (a *RoleRepo) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
 	opt := a.getQueryOption(opts...)
 
-	db := GetRoleDB(ctx, a.DB)
+	db := util.WrapDataScope(ctx, GetRoleDB(ctx, a.DB))
 	if v := params.IDs; len(v) > 0 {
 		db = db.Where("id IN (?)", v)
 	}
 	if v := params.Name; v != "" {
 		db = db.Where("name=?", v)
 	}
+	if v := params.Status; v > 0 {
+		db = db.Where("status=?", v)
+	}
 	if v := params.QueryValue; v != "" {
 		v = "%" + v + "%"
 		db = db.Where("name LIKE ?", v)
@@ -63,7 +66,7 @@ func (a *RoleRepo) Query(ctx context.Context, params schema.RoleQueryParam, opts
 
 func (a *RoleRepo) Get(ctx context.Context, id uint64, opts ...schema.RoleQueryOptions) (*schema.Role, error) {
 	var role Role
-	ok, err := util.FindOne(ctx, GetRoleDB(ctx, a.DB).Where("id=?", id), &role)
+	ok, err := util.FindOne(ctx, util.WrapDataScope(ctx, GetRoleDB(ctx, a.DB)).Where("id=?", id), &role)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	} else if !ok {
@@ -81,16 +84,16 @@ func (a *RoleRepo) Create(ctx context.Context, item schema.Role) error {
 
 func (a *RoleRepo) Update(ctx context.Context, id uint64, item schema.Role) error {
 	eitem := SchemaRole(item).ToRole()
-	result := GetRoleDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	result := util.WrapDataScope(ctx, GetRoleDB(ctx, a.DB)).Where("id=?", id).Updates(eitem)
 	return errors.WithStack(result.Error)
 }
 
 func (a *RoleRepo) Delete(ctx context.Context, id uint64) error {
-	result := GetRoleDB(ctx, a.DB).Where("id=?", id).Delete(Role{})
+	result := util.WrapDataScope(ctx, GetRoleDB(ctx, a.DB)).Where("id=?", id).Delete(Role{})
 	return errors.WithStack(result.Error)
 }
 
 func (a *RoleRepo) UpdateStatus(ctx context.Context, id uint64, status int) error {
-	result := GetRoleDB(ctx, a.DB).Where("id=?", id).Update("status", status)
+	result := util.WrapDataScope(ctx, GetRoleDB(ctx, a.DB)).Where("id=?", id).Update("status", status)
 	return errors.WithStack(result.Error)
 }
@@ -0,0 +1,45 @@
+package role
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetRoleDataUserDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(RoleDataUser))
+}
+
+type SchemaRoleDataUser schema.RoleDataUser
+
+func (a SchemaRoleDataUser) ToRoleDataUser() *RoleDataUser {
+	item := new(RoleDataUser)
+	structure.Copy(a, item)
+	return item
+}
+
+type RoleDataUser struct {
+	util.Model
+	RoleID uint64 `gorm:"index;not null;"` // 角色ID
+	UserID uint64 `gorm:"index;not null;"` // 用户ID
+}
+
+func (a RoleDataUser) ToSchemaRoleDataUser() *schema.RoleDataUser {
+	item := new(schema.RoleDataUser)
+	structure.Copy(a, item)
+	return item
+}
+
+type RoleDataUsers []*RoleDataUser
+
+func (a RoleDataUsers) ToSchemaRoleDataUsers() []*schema.RoleDataUser {
+	list := make([]*schema.RoleDataUser, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaRoleDataUser()
+	}
+	return list
+}
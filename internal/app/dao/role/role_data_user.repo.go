@@ -0,0 +1,98 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var RoleDataUserSet = wire.NewSet(wire.Struct(new(RoleDataUserRepo), "*"))
+
+type RoleDataUserRepo struct {
+	DB *gorm.DB
+}
+
+func (a *RoleDataUserRepo) getQueryOption(opts ...schema.RoleDataUserQueryOptions) schema.RoleDataUserQueryOptions {
+	var opt schema.RoleDataUserQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *RoleDataUserRepo) Query(ctx context.Context, params schema.RoleDataUserQueryParam, opts ...schema.RoleDataUserQueryOptions) (*schema.RoleDataUserQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetRoleDataUserDB(ctx, a.DB)
+	if v := params.RoleID; v > 0 {
+		db = db.Where("role_id=?", v)
+	}
+	if v := params.RoleIDs; len(v) > 0 {
+		db = db.Where("role_id IN (?)", v)
+	}
+
+	if len(opt.SelectFields) > 0 {
+		db = db.Select(opt.SelectFields)
+	}
+
+	if len(opt.OrderFields) > 0 {
+		db = db.Order(util.ParseOrder(opt.OrderFields))
+	}
+
+	var list RoleDataUsers
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.RoleDataUserQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaRoleDataUsers(),
+	}
+
+	return qr, nil
+}
+
+func (a *RoleDataUserRepo) Get(ctx context.Context, id uint64, opts ...schema.RoleDataUserQueryOptions) (*schema.RoleDataUser, error) {
+	db := GetRoleDataUserDB(ctx, a.DB).Where("id=?", id)
+	var item RoleDataUser
+	ok, err := util.FindOne(ctx, db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaRoleDataUser(), nil
+}
+
+func (a *RoleDataUserRepo) Create(ctx context.Context, item schema.RoleDataUser) error {
+	eitem := SchemaRoleDataUser(item).ToRoleDataUser()
+	result := GetRoleDataUserDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleDataUserRepo) Update(ctx context.Context, id uint64, item schema.RoleDataUser) error {
+	eitem := SchemaRoleDataUser(item).ToRoleDataUser()
+	result := GetRoleDataUserDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleDataUserRepo) Delete(ctx context.Context, id uint64) error {
+	result := GetRoleDataUserDB(ctx, a.DB).Where("id=?", id).Delete(RoleDataUser{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleDataUserRepo) DeleteByRoleID(ctx context.Context, roleID uint64) error {
+	result := GetRoleDataUserDB(ctx, a.DB).Where("role_id=?", roleID).Delete(RoleDataUser{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleDataUserRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetRoleDataUserDB(ctx, a.DB).Where("user_id=?", userID).Delete(RoleDataUser{})
+	return errors.WithStack(result.Error)
+}
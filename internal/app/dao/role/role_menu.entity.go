@@ -24,6 +24,7 @@ func (a SchemaRoleMenu) ToRoleMenu() *RoleMenu {
 
 type RoleMenu struct {
 	util.Model
+	util.TenantModel
 	RoleID   uint64 `gorm:"index;not null;"` // 角色ID
 	MenuID   uint64 `gorm:"index;not null;"` // 菜单ID
 	ActionID uint64 `gorm:"index;not null;"` // 动作ID
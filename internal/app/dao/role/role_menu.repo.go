@@ -35,6 +35,9 @@ func (a *RoleMenuRepo) Query(ctx context.Context, params schema.RoleMenuQueryPar
 	if v := params.RoleIDs; len(v) > 0 {
 		db = db.Where("role_id IN (?)", v)
 	}
+	if v := params.MenuIDs; len(v) > 0 {
+		db = db.Where("menu_id IN (?)", v)
+	}
 
 	if len(opt.SelectFields) > 0 {
 		db = db.Select(opt.SelectFields)
@@ -87,6 +90,11 @@ func (a *RoleMenuRepo) Delete(ctx context.Context, id uint64) error {
 	return errors.WithStack(result.Error)
 }
 
+func (a *RoleMenuRepo) DeleteByMenuID(ctx context.Context, menuID uint64) error {
+	result := GetRoleMenuDB(ctx, a.DB).Where("menu_id=?", menuID).Delete(RoleMenu{})
+	return errors.WithStack(result.Error)
+}
+
 func (a *RoleMenuRepo) DeleteByRoleID(ctx context.Context, roleID uint64) error {
 	result := GetRoleMenuDB(ctx, a.DB).Where("role_id=?", roleID).Delete(RoleMenu{})
 	return errors.WithStack(result.Error)
@@ -0,0 +1,45 @@
+package role
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetRoleParentDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(RoleParent))
+}
+
+type SchemaRoleParent schema.RoleParent
+
+func (a SchemaRoleParent) ToRoleParent() *RoleParent {
+	item := new(RoleParent)
+	structure.Copy(a, item)
+	return item
+}
+
+type RoleParent struct {
+	util.Model
+	RoleID   uint64 `gorm:"index;not null;"` // 角色ID
+	ParentID uint64 `gorm:"index;not null;"` // 上级角色ID
+}
+
+func (a RoleParent) ToSchemaRoleParent() *schema.RoleParent {
+	item := new(schema.RoleParent)
+	structure.Copy(a, item)
+	return item
+}
+
+type RoleParents []*RoleParent
+
+func (a RoleParents) ToSchemaRoleParents() []*schema.RoleParent {
+	list := make([]*schema.RoleParent, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaRoleParent()
+	}
+	return list
+}
@@ -0,0 +1,96 @@
+package role
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var RoleParentSet = wire.NewSet(wire.Struct(new(RoleParentRepo), "*"))
+
+type RoleParentRepo struct {
+	DB *gorm.DB
+}
+
+func (a *RoleParentRepo) getQueryOption(opts ...schema.RoleParentQueryOptions) schema.RoleParentQueryOptions {
+	var opt schema.RoleParentQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *RoleParentRepo) Query(ctx context.Context, params schema.RoleParentQueryParam, opts ...schema.RoleParentQueryOptions) (*schema.RoleParentQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetRoleParentDB(ctx, a.DB)
+	if v := params.RoleID; v > 0 {
+		db = db.Where("role_id=?", v)
+	}
+	if v := params.RoleIDs; len(v) > 0 {
+		db = db.Where("role_id IN (?)", v)
+	}
+	if v := params.ParentIDs; len(v) > 0 {
+		db = db.Where("parent_id IN (?)", v)
+	}
+
+	if len(opt.SelectFields) > 0 {
+		db = db.Select(opt.SelectFields)
+	}
+
+	if len(opt.OrderFields) > 0 {
+		db = db.Order(util.ParseOrder(opt.OrderFields))
+	}
+
+	var list RoleParents
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.RoleParentQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaRoleParents(),
+	}
+
+	return qr, nil
+}
+
+func (a *RoleParentRepo) Get(ctx context.Context, id uint64, opts ...schema.RoleParentQueryOptions) (*schema.RoleParent, error) {
+	db := GetRoleParentDB(ctx, a.DB).Where("id=?", id)
+	var item RoleParent
+	ok, err := util.FindOne(ctx, db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaRoleParent(), nil
+}
+
+func (a *RoleParentRepo) Create(ctx context.Context, item schema.RoleParent) error {
+	eitem := SchemaRoleParent(item).ToRoleParent()
+	result := GetRoleParentDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleParentRepo) Update(ctx context.Context, id uint64, item schema.RoleParent) error {
+	eitem := SchemaRoleParent(item).ToRoleParent()
+	result := GetRoleParentDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleParentRepo) Delete(ctx context.Context, id uint64) error {
+	result := GetRoleParentDB(ctx, a.DB).Where("id=?", id).Delete(RoleParent{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *RoleParentRepo) DeleteByRoleID(ctx context.Context, roleID uint64) error {
+	result := GetRoleParentDB(ctx, a.DB).Where("role_id=?", roleID).Delete(RoleParent{})
+	return errors.WithStack(result.Error)
+}
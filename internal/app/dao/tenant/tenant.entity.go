@@ -0,0 +1,48 @@
+package tenant
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetTenantDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(Tenant))
+}
+
+type SchemaTenant schema.Tenant
+
+func (a SchemaTenant) ToTenant() *Tenant {
+	item := new(Tenant)
+	structure.Copy(a, item)
+	return item
+}
+
+type Tenant struct {
+	util.Model
+	Code    string  `gorm:"size:64;uniqueIndex;default:'';not null;"` // 租户编号
+	Name    string  `gorm:"size:100;index;default:'';not null;"`      // 租户名称
+	Status  int     `gorm:"index;default:0;"`                         // 状态(1:启用 2:停用)
+	Memo    *string `gorm:"size:1024;"`                               // 备注
+	Creator uint64  `gorm:""`                                         // 创建者
+}
+
+func (a Tenant) ToSchemaTenant() *schema.Tenant {
+	item := new(schema.Tenant)
+	structure.Copy(a, item)
+	return item
+}
+
+type Tenants []*Tenant
+
+func (a Tenants) ToSchemaTenants() []*schema.Tenant {
+	list := make([]*schema.Tenant, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaTenant()
+	}
+	return list
+}
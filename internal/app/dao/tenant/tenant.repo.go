@@ -0,0 +1,92 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var TenantSet = wire.NewSet(wire.Struct(new(TenantRepo), "*"))
+
+type TenantRepo struct {
+	DB *gorm.DB
+}
+
+func (a *TenantRepo) getQueryOption(opts ...schema.TenantQueryOptions) schema.TenantQueryOptions {
+	var opt schema.TenantQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *TenantRepo) Query(ctx context.Context, params schema.TenantQueryParam, opts ...schema.TenantQueryOptions) (*schema.TenantQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetTenantDB(ctx, a.DB)
+	if v := params.Code; v != "" {
+		db = db.Where("code=?", v)
+	}
+	if v := params.Status; v != 0 {
+		db = db.Where("status=?", v)
+	}
+	if v := params.QueryValue; v != "" {
+		v = "%" + v + "%"
+		db = db.Where("code LIKE ? OR name LIKE ?", v, v)
+	}
+
+	if len(opt.OrderFields) > 0 {
+		db = db.Order(util.ParseOrder(opt.OrderFields))
+	}
+
+	var list Tenants
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	qr := &schema.TenantQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaTenants(),
+	}
+	return qr, nil
+}
+
+func (a *TenantRepo) Get(ctx context.Context, id uint64, opts ...schema.TenantQueryOptions) (*schema.Tenant, error) {
+	var item Tenant
+	ok, err := util.FindOne(ctx, GetTenantDB(ctx, a.DB).Where("id=?", id), &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaTenant(), nil
+}
+
+func (a *TenantRepo) Create(ctx context.Context, item schema.Tenant) error {
+	eitem := SchemaTenant(item).ToTenant()
+	result := GetTenantDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *TenantRepo) Update(ctx context.Context, id uint64, item schema.Tenant) error {
+	eitem := SchemaTenant(item).ToTenant()
+	result := GetTenantDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *TenantRepo) Delete(ctx context.Context, id uint64) error {
+	result := GetTenantDB(ctx, a.DB).Where("id=?", id).Delete(Tenant{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *TenantRepo) UpdateStatus(ctx context.Context, id uint64, status int) error {
+	result := GetTenantDB(ctx, a.DB).Where("id=?", id).Update("status", status)
+	return errors.WithStack(result.Error)
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -24,13 +25,19 @@ func (a SchemaUser) ToUser() *User {
 
 type User struct {
 	util.Model
-	UserName string  `gorm:"size:64;uniqueIndex;default:'';not null;"` // 用户名
-	RealName string  `gorm:"size:64;index;default:'';"`                // 真实姓名
-	Password string  `gorm:"size:40;default:'';"`                      // 密码
-	Email    *string `gorm:"size:255;"`                                // 邮箱
-	Phone    *string `gorm:"size:20;"`                                 // 手机号
-	Status   int     `gorm:"index;default:0;"`                         // 状态(1:启用 2:停用)
-	Creator  uint64  `gorm:""`                                         // 创建者
+	TenantID           uint64     `gorm:"uniqueIndex:idx_user_tenant_user_name;default:0;"`                   // 租户ID(0为平台)
+	UserName           string     `gorm:"size:64;uniqueIndex:idx_user_tenant_user_name;default:'';not null;"` // 用户名(租户内唯一)
+	RealName           string     `gorm:"size:64;index;default:'';"`                                          // 真实姓名
+	Password           string     `gorm:"size:255;default:'';"`                                               // 密码
+	Email              *string    `gorm:"size:255;"`                                                          // 邮箱
+	Phone              *string    `gorm:"size:20;"`                                                           // 手机号
+	Status             int        `gorm:"index;default:0;"`                                                   // 状态(1:启用 2:停用)
+	DeptID             *uint64    `gorm:"index;default:0;"`                                                   // 主部门
+	Creator            uint64     `gorm:""`                                                                   // 创建者
+	MustChangePassword bool       `gorm:"default:false;"`                                                     // 下次登录必须修改密码
+	PasswordChangedAt  *time.Time `gorm:""`                                                                   // 密码修改时间
+	Source             string     `gorm:"size:20;default:'';"`                                                // 用户来源
+	IsSuperAdmin       bool       `gorm:"index;default:false;"`                                               // 是否超级管理员
 }
 
 func (a User) ToSchemaUser() *schema.User {
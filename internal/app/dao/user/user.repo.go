@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/wire"
 	"gorm.io/gorm"
@@ -28,10 +29,16 @@ func (a *UserRepo) getQueryOption(opts ...schema.UserQueryOptions) schema.UserQu
 func (a *UserRepo) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
 	opt := a.getQueryOption(opts...)
 
-	db := GetUserDB(ctx, a.DB)
+	db := util.WrapDataScope(ctx, GetUserDB(ctx, a.DB))
+	if v := params.IDs; len(v) > 0 {
+		db = db.Where("id IN (?)", v)
+	}
 	if v := params.UserName; v != "" {
 		db = db.Where("user_name=?", v)
 	}
+	if v := params.Email; v != "" {
+		db = db.Where("email=?", v)
+	}
 	if v := params.Status; v > 0 {
 		db = db.Where("status=?", v)
 	}
@@ -41,10 +48,19 @@ func (a *UserRepo) Query(ctx context.Context, params schema.UserQueryParam, opts
 			Where("role_id IN (?)", v)
 		db = db.Where("id IN (?)", subQuery)
 	}
+	if v := params.DeptIDs; len(v) > 0 {
+		subQuery := GetUserDeptDB(ctx, a.DB).
+			Select("user_id").
+			Where("dept_id IN (?)", v)
+		db = db.Where("dept_id IN (?) OR id IN (?)", v, subQuery)
+	}
 	if v := params.QueryValue; v != "" {
 		v = "%" + v + "%"
 		db = db.Where("user_name LIKE ? OR real_name LIKE ?", v, v)
 	}
+	if params.SuperAdmin {
+		db = db.Where("is_super_admin=?", true)
+	}
 
 	if len(opt.SelectFields) > 0 {
 		db = db.Select(opt.SelectFields)
@@ -69,7 +85,7 @@ func (a *UserRepo) Query(ctx context.Context, params schema.UserQueryParam, opts
 
 func (a *UserRepo) Get(ctx context.Context, id uint64, opts ...schema.UserQueryOptions) (*schema.User, error) {
 	var item User
-	ok, err := util.FindOne(ctx, GetUserDB(ctx, a.DB).Where("id=?", id), &item)
+	ok, err := util.FindOne(ctx, util.WrapDataScope(ctx, GetUserDB(ctx, a.DB)).Where("id=?", id), &item)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	} else if !ok {
@@ -87,17 +103,17 @@ func (a *UserRepo) Create(ctx context.Context, item schema.User) error {
 
 func (a *UserRepo) Update(ctx context.Context, id uint64, item schema.User) error {
 	eitem := SchemaUser(item).ToUser()
-	result := GetUserDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	result := util.WrapDataScope(ctx, GetUserDB(ctx, a.DB)).Where("id=?", id).Updates(eitem)
 	return errors.WithStack(result.Error)
 }
 
 func (a *UserRepo) Delete(ctx context.Context, id uint64) error {
-	result := GetUserDB(ctx, a.DB).Where("id=?", id).Delete(User{})
+	result := util.WrapDataScope(ctx, GetUserDB(ctx, a.DB)).Where("id=?", id).Delete(User{})
 	return errors.WithStack(result.Error)
 }
 
 func (a *UserRepo) UpdateStatus(ctx context.Context, id uint64, status int) error {
-	result := GetUserDB(ctx, a.DB).Where("id=?", id).Update("status", status)
+	result := util.WrapDataScope(ctx, GetUserDB(ctx, a.DB)).Where("id=?", id).Update("status", status)
 	return errors.WithStack(result.Error)
 }
 
@@ -105,3 +121,23 @@ func (a *UserRepo) UpdatePassword(ctx context.Context, id uint64, password strin
 	result := GetUserDB(ctx, a.DB).Where("id=?", id).Update("password", password)
 	return errors.WithStack(result.Error)
 }
+
+// ChangePassword 修改密码，同时更新密码修改时间与强制修改标记
+func (a *UserRepo) ChangePassword(ctx context.Context, id uint64, password string, mustChange bool) error {
+	result := GetUserDB(ctx, a.DB).Where("id=?", id).Updates(map[string]interface{}{
+		"password":             password,
+		"password_changed_at":  time.Now(),
+		"must_change_password": mustChange,
+	})
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserRepo) UpdateMustChangePassword(ctx context.Context, id uint64, mustChange bool) error {
+	result := GetUserDB(ctx, a.DB).Where("id=?", id).Update("must_change_password", mustChange)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserRepo) UpdateSuperAdmin(ctx context.Context, id uint64, superAdmin bool) error {
+	result := util.WrapDataScope(ctx, GetUserDB(ctx, a.DB)).Where("id=?", id).Update("is_super_admin", superAdmin)
+	return errors.WithStack(result.Error)
+}
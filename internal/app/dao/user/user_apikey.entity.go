@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserAPIKeyDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserAPIKey))
+}
+
+type SchemaUserAPIKey schema.UserAPIKey
+
+func (a SchemaUserAPIKey) ToUserAPIKey() *UserAPIKey {
+	item := new(UserAPIKey)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserAPIKey struct {
+	util.Model
+	UserID     uint64     `gorm:"index;default:0;"`                         // 用户内码
+	Name       string     `gorm:"size:64;default:'';"`                      // 名称
+	Prefix     string     `gorm:"size:16;default:'';"`                      // 密钥前缀
+	KeyHash    string     `gorm:"size:64;uniqueIndex;default:'';not null;"` // 密钥哈希
+	ExpiresAt  *time.Time `gorm:""`                                         // 过期时间
+	LastUsedAt *time.Time `gorm:""`                                         // 最近使用时间
+}
+
+func (a UserAPIKey) ToSchemaUserAPIKey() *schema.UserAPIKey {
+	item := new(schema.UserAPIKey)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserAPIKeys []*UserAPIKey
+
+func (a UserAPIKeys) ToSchemaUserAPIKeys() []*schema.UserAPIKey {
+	list := make([]*schema.UserAPIKey, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaUserAPIKey()
+	}
+	return list
+}
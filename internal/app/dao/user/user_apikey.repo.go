@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserAPIKeySet = wire.NewSet(wire.Struct(new(UserAPIKeyRepo), "*"))
+
+type UserAPIKeyRepo struct {
+	DB *gorm.DB
+}
+
+func (a *UserAPIKeyRepo) getQueryOption(opts ...schema.UserAPIKeyQueryOptions) schema.UserAPIKeyQueryOptions {
+	var opt schema.UserAPIKeyQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *UserAPIKeyRepo) Query(ctx context.Context, params schema.UserAPIKeyQueryParam, opts ...schema.UserAPIKeyQueryOptions) (*schema.UserAPIKeyQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetUserAPIKeyDB(ctx, a.DB)
+	if v := params.UserID; v > 0 {
+		db = db.Where("user_id=?", v)
+	}
+
+	opt.OrderFields = append(opt.OrderFields, schema.NewOrderField("id", schema.OrderByDESC))
+	db = db.Order(util.ParseOrder(opt.OrderFields))
+
+	var list UserAPIKeys
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.UserAPIKeyQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaUserAPIKeys(),
+	}
+
+	return qr, nil
+}
+
+func (a *UserAPIKeyRepo) Get(ctx context.Context, id uint64) (*schema.UserAPIKey, error) {
+	db := GetUserAPIKeyDB(ctx, a.DB).Where("id=?", id)
+	var item UserAPIKey
+	ok, err := util.FindOne(ctx, db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaUserAPIKey(), nil
+}
+
+func (a *UserAPIKeyRepo) GetByKeyHash(ctx context.Context, keyHash string) (*schema.UserAPIKey, error) {
+	db := GetUserAPIKeyDB(ctx, a.DB).Where("key_hash=?", keyHash)
+	var item UserAPIKey
+	ok, err := util.FindOne(ctx, db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaUserAPIKey(), nil
+}
+
+func (a *UserAPIKeyRepo) Create(ctx context.Context, item schema.UserAPIKey) error {
+	eitem := SchemaUserAPIKey(item).ToUserAPIKey()
+	result := GetUserAPIKeyDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserAPIKeyRepo) UpdateLastUsedAt(ctx context.Context, id uint64, lastUsedAt time.Time) error {
+	result := GetUserAPIKeyDB(ctx, a.DB).Where("id=?", id).Update("last_used_at", lastUsedAt)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserAPIKeyRepo) Delete(ctx context.Context, id uint64) error {
+	result := GetUserAPIKeyDB(ctx, a.DB).Where("id=?", id).Delete(UserAPIKey{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserAPIKeyRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetUserAPIKeyDB(ctx, a.DB).Where("user_id=?", userID).Delete(UserAPIKey{})
+	return errors.WithStack(result.Error)
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserAPIKeyRoleDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserAPIKeyRole))
+}
+
+type SchemaUserAPIKeyRole schema.UserAPIKeyRole
+
+func (a SchemaUserAPIKeyRole) ToUserAPIKeyRole() *UserAPIKeyRole {
+	item := new(UserAPIKeyRole)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserAPIKeyRole struct {
+	util.Model
+	APIKeyID uint64 `gorm:"index;default:0;"` // API密钥内码
+	RoleID   uint64 `gorm:"index;default:0;"` // 角色内码
+}
+
+func (a UserAPIKeyRole) ToSchemaUserAPIKeyRole() *schema.UserAPIKeyRole {
+	item := new(schema.UserAPIKeyRole)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserAPIKeyRoles []*UserAPIKeyRole
+
+func (a UserAPIKeyRoles) ToSchemaUserAPIKeyRoles() []*schema.UserAPIKeyRole {
+	list := make([]*schema.UserAPIKeyRole, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaUserAPIKeyRole()
+	}
+	return list
+}
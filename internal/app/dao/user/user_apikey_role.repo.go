@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserAPIKeyRoleSet = wire.NewSet(wire.Struct(new(UserAPIKeyRoleRepo), "*"))
+
+type UserAPIKeyRoleRepo struct {
+	DB *gorm.DB
+}
+
+func (a *UserAPIKeyRoleRepo) getQueryOption(opts ...schema.UserAPIKeyRoleQueryOptions) schema.UserAPIKeyRoleQueryOptions {
+	var opt schema.UserAPIKeyRoleQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *UserAPIKeyRoleRepo) Query(ctx context.Context, params schema.UserAPIKeyRoleQueryParam, opts ...schema.UserAPIKeyRoleQueryOptions) (*schema.UserAPIKeyRoleQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetUserAPIKeyRoleDB(ctx, a.DB)
+	if v := params.APIKeyID; v > 0 {
+		db = db.Where("api_key_id=?", v)
+	}
+	if v := params.APIKeyIDs; len(v) > 0 {
+		db = db.Where("api_key_id IN (?)", v)
+	}
+
+	if len(opt.OrderFields) > 0 {
+		db = db.Order(util.ParseOrder(opt.OrderFields))
+	}
+
+	var list UserAPIKeyRoles
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.UserAPIKeyRoleQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaUserAPIKeyRoles(),
+	}
+
+	return qr, nil
+}
+
+func (a *UserAPIKeyRoleRepo) Create(ctx context.Context, item schema.UserAPIKeyRole) error {
+	eitem := SchemaUserAPIKeyRole(item).ToUserAPIKeyRole()
+	result := GetUserAPIKeyRoleDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserAPIKeyRoleRepo) DeleteByAPIKeyID(ctx context.Context, apiKeyID uint64) error {
+	result := GetUserAPIKeyRoleDB(ctx, a.DB).Where("api_key_id=?", apiKeyID).Delete(UserAPIKeyRole{})
+	return errors.WithStack(result.Error)
+}
+
+// DeleteByUserID 删除用户全部API密钥限定的角色
+func (a *UserAPIKeyRoleRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	subQuery := GetUserAPIKeyDB(ctx, a.DB).Where("user_id=?", userID).Select("id")
+	result := GetUserAPIKeyRoleDB(ctx, a.DB).Where("api_key_id IN (?)", subQuery).Delete(UserAPIKeyRole{})
+	return errors.WithStack(result.Error)
+}
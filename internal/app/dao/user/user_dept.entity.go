@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserDeptDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserDept))
+}
+
+type SchemaUserDept schema.UserDept
+
+func (a SchemaUserDept) ToUserDept() *UserDept {
+	item := new(UserDept)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserDept struct {
+	util.Model
+	UserID uint64 `gorm:"index;default:0;"` // 用户内码
+	DeptID uint64 `gorm:"index;default:0;"` // 部门内码
+}
+
+func (a UserDept) ToSchemaUserDept() *schema.UserDept {
+	item := new(schema.UserDept)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserDepts []*UserDept
+
+func (a UserDepts) ToSchemaUserDepts() []*schema.UserDept {
+	list := make([]*schema.UserDept, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaUserDept()
+	}
+	return list
+}
@@ -0,0 +1,92 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserDeptSet = wire.NewSet(wire.Struct(new(UserDeptRepo), "*"))
+
+type UserDeptRepo struct {
+	DB *gorm.DB
+}
+
+func (a *UserDeptRepo) getQueryOption(opts ...schema.UserDeptQueryOptions) schema.UserDeptQueryOptions {
+	var opt schema.UserDeptQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+func (a *UserDeptRepo) Query(ctx context.Context, params schema.UserDeptQueryParam, opts ...schema.UserDeptQueryOptions) (*schema.UserDeptQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+
+	db := GetUserDeptDB(ctx, a.DB)
+	if v := params.UserID; v > 0 {
+		db = db.Where("user_id=?", v)
+	}
+	if v := params.UserIDs; len(v) > 0 {
+		db = db.Where("user_id IN (?)", v)
+	}
+	if v := params.DeptIDs; len(v) > 0 {
+		db = db.Where("dept_id IN (?)", v)
+	}
+
+	if len(opt.OrderFields) > 0 {
+		db = db.Order(util.ParseOrder(opt.OrderFields))
+	}
+
+	var list UserDepts
+	pr, err := util.WrapPageQuery(ctx, db, params.PaginationParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.UserDeptQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaUserDepts(),
+	}
+
+	return qr, nil
+}
+
+func (a *UserDeptRepo) Get(ctx context.Context, id uint64, opts ...schema.UserDeptQueryOptions) (*schema.UserDept, error) {
+	db := GetUserDeptDB(ctx, a.DB).Where("id=?", id)
+	var item UserDept
+	ok, err := util.FindOne(ctx, db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaUserDept(), nil
+}
+
+func (a *UserDeptRepo) Create(ctx context.Context, item schema.UserDept) error {
+	eitem := SchemaUserDept(item).ToUserDept()
+	result := GetUserDeptDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserDeptRepo) Update(ctx context.Context, id uint64, item schema.UserDept) error {
+	eitem := SchemaUserDept(item).ToUserDept()
+	result := GetUserDeptDB(ctx, a.DB).Where("id=?", id).Updates(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserDeptRepo) Delete(ctx context.Context, id uint64) error {
+	result := GetUserDeptDB(ctx, a.DB).Where("id=?", id).Delete(UserDept{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserDeptRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetUserDeptDB(ctx, a.DB).Where("user_id=?", userID).Delete(UserDept{})
+	return errors.WithStack(result.Error)
+}
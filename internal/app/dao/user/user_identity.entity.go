@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserIdentityDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserIdentity))
+}
+
+type SchemaUserIdentity schema.UserIdentity
+
+func (a SchemaUserIdentity) ToUserIdentity() *UserIdentity {
+	item := new(UserIdentity)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserIdentity struct {
+	util.Model
+	UserID   uint64 `gorm:"index;default:0;"`                                    // 用户内码
+	Provider string `gorm:"size:255;uniqueIndex:idx_provider_subject;not null;"` // 身份提供方
+	Subject  string `gorm:"size:255;uniqueIndex:idx_provider_subject;not null;"` // 用户在身份提供方的唯一标识
+	Email    string `gorm:"size:255;default:'';"`                                // 邮箱
+}
+
+func (a UserIdentity) ToSchemaUserIdentity() *schema.UserIdentity {
+	item := new(schema.UserIdentity)
+	structure.Copy(a, item)
+	return item
+}
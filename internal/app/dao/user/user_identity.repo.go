@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserIdentitySet = wire.NewSet(wire.Struct(new(UserIdentityRepo), "*"))
+
+type UserIdentityRepo struct {
+	DB *gorm.DB
+}
+
+func (a *UserIdentityRepo) GetBySubject(ctx context.Context, provider, subject string) (*schema.UserIdentity, error) {
+	var item UserIdentity
+	ok, err := util.FindOne(ctx, GetUserIdentityDB(ctx, a.DB).Where("provider=? AND subject=?", provider, subject), &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item.ToSchemaUserIdentity(), nil
+}
+
+func (a *UserIdentityRepo) Create(ctx context.Context, item schema.UserIdentity) error {
+	eitem := SchemaUserIdentity(item).ToUserIdentity()
+	result := GetUserIdentityDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserIdentityRepo) UpdateEmail(ctx context.Context, id uint64, email string) error {
+	result := GetUserIdentityDB(ctx, a.DB).Where("id=?", id).Update("email", email)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserIdentityRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetUserIdentityDB(ctx, a.DB).Where("user_id=?", userID).Delete(UserIdentity{})
+	return errors.WithStack(result.Error)
+}
@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserMFADB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserMFA))
+}
+
+type SchemaUserMFA schema.UserMFA
+
+func (a SchemaUserMFA) ToUserMFA() *UserMFA {
+	item := new(UserMFA)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserMFA struct {
+	util.Model
+	UserID   uint64 `gorm:"uniqueIndex;default:0;"` // 用户内码
+	Secret   string `gorm:"size:64;default:'';"`    // TOTP密钥
+	Enabled  bool   `gorm:"default:false;"`         // 是否已完成绑定
+	LastStep int64  `gorm:"default:0;"`             // 最近一次验证通过的时间步
+}
+
+func (a UserMFA) ToSchemaUserMFA() *schema.UserMFA {
+	item := new(schema.UserMFA)
+	structure.Copy(a, item)
+	return item
+}
@@ -0,0 +1,57 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserMFASet = wire.NewSet(wire.Struct(new(UserMFARepo), "*"))
+
+type UserMFARepo struct {
+	DB *gorm.DB
+}
+
+func (a *UserMFARepo) GetByUserID(ctx context.Context, userID uint64) (*schema.UserMFA, error) {
+	var item UserMFA
+	ok, err := util.FindOne(ctx, GetUserMFADB(ctx, a.DB).Where("user_id=?", userID), &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+	return item.ToSchemaUserMFA(), nil
+}
+
+func (a *UserMFARepo) Create(ctx context.Context, item schema.UserMFA) error {
+	eitem := SchemaUserMFA(item).ToUserMFA()
+	result := GetUserMFADB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserMFARepo) Enable(ctx context.Context, id uint64, lastStep int64) error {
+	result := GetUserMFADB(ctx, a.DB).Where("id=?", id).Updates(map[string]interface{}{
+		"enabled":   true,
+		"last_step": lastStep,
+	})
+	return errors.WithStack(result.Error)
+}
+
+// UpdateLastStep 更新最近一次验证通过的时间步，仅当新的时间步更大时生效(并发请求时只有一个能成功)
+func (a *UserMFARepo) UpdateLastStep(ctx context.Context, id uint64, lastStep int64) (bool, error) {
+	result := GetUserMFADB(ctx, a.DB).Where("id=? AND last_step<?", id, lastStep).Update("last_step", lastStep)
+	if err := result.Error; err != nil {
+		return false, errors.WithStack(err)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (a *UserMFARepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetUserMFADB(ctx, a.DB).Where("user_id=?", userID).Delete(UserMFA{})
+	return errors.WithStack(result.Error)
+}
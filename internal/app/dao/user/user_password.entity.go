@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserPasswordDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserPassword))
+}
+
+type SchemaUserPassword schema.UserPassword
+
+func (a SchemaUserPassword) ToUserPassword() *UserPassword {
+	item := new(UserPassword)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserPassword struct {
+	util.Model
+	UserID   uint64 `gorm:"index;default:0;"`     // 用户内码
+	Password string `gorm:"size:255;default:'';"` // 密码哈希
+}
+
+func (a UserPassword) ToSchemaUserPassword() *schema.UserPassword {
+	item := new(schema.UserPassword)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserPasswords []*UserPassword
+
+func (a UserPasswords) ToSchemaUserPasswords() []*schema.UserPassword {
+	list := make([]*schema.UserPassword, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaUserPassword()
+	}
+	return list
+}
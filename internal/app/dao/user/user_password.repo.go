@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserPasswordSet = wire.NewSet(wire.Struct(new(UserPasswordRepo), "*"))
+
+type UserPasswordRepo struct {
+	DB *gorm.DB
+}
+
+// QueryRecent 查询用户最近使用过的密码(按时间倒序)
+func (a *UserPasswordRepo) QueryRecent(ctx context.Context, userID uint64, limit int) (schema.UserPasswords, error) {
+	db := GetUserPasswordDB(ctx, a.DB).Where("user_id=?", userID).Order("id DESC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var list UserPasswords
+	err := db.Find(&list).Error
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list.ToSchemaUserPasswords(), nil
+}
+
+func (a *UserPasswordRepo) Create(ctx context.Context, item schema.UserPassword) error {
+	eitem := SchemaUserPassword(item).ToUserPassword()
+	result := GetUserPasswordDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+// DeleteBefore 删除用户指定记录之前的历史密码
+func (a *UserPasswordRepo) DeleteBefore(ctx context.Context, userID, id uint64) error {
+	result := GetUserPasswordDB(ctx, a.DB).Where("user_id=? AND id<?", userID, id).Delete(UserPassword{})
+	return errors.WithStack(result.Error)
+}
+
+func (a *UserPasswordRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetUserPasswordDB(ctx, a.DB).Where("user_id=?", userID).Delete(UserPassword{})
+	return errors.WithStack(result.Error)
+}
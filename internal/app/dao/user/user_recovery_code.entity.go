@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
+)
+
+func GetUserRecoveryCodeDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	return util.GetDBWithModel(ctx, defDB, new(UserRecoveryCode))
+}
+
+type SchemaUserRecoveryCode schema.UserRecoveryCode
+
+func (a SchemaUserRecoveryCode) ToUserRecoveryCode() *UserRecoveryCode {
+	item := new(UserRecoveryCode)
+	structure.Copy(a, item)
+	return item
+}
+
+type UserRecoveryCode struct {
+	util.Model
+	UserID uint64     `gorm:"index;default:0;"`    // 用户内码
+	Code   string     `gorm:"size:64;default:'';"` // 恢复码哈希
+	UsedAt *time.Time `gorm:""`                    // 使用时间
+}
+
+func (a UserRecoveryCode) ToSchemaUserRecoveryCode() *schema.UserRecoveryCode {
+	item := new(schema.UserRecoveryCode)
+	structure.Copy(a, item)
+	return item
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var UserRecoveryCodeSet = wire.NewSet(wire.Struct(new(UserRecoveryCodeRepo), "*"))
+
+type UserRecoveryCodeRepo struct {
+	DB *gorm.DB
+}
+
+func (a *UserRecoveryCodeRepo) Create(ctx context.Context, item schema.UserRecoveryCode) error {
+	eitem := SchemaUserRecoveryCode(item).ToUserRecoveryCode()
+	result := GetUserRecoveryCodeDB(ctx, a.DB).Create(eitem)
+	return errors.WithStack(result.Error)
+}
+
+// Use 使用恢复码(每个恢复码只能使用一次)，返回是否使用成功
+func (a *UserRecoveryCodeRepo) Use(ctx context.Context, userID uint64, code string) (bool, error) {
+	result := GetUserRecoveryCodeDB(ctx, a.DB).
+		Where("user_id=? AND code=? AND used_at IS NULL", userID, code).
+		Update("used_at", time.Now())
+	if err := result.Error; err != nil {
+		return false, errors.WithStack(err)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (a *UserRecoveryCodeRepo) DeleteByUserID(ctx context.Context, userID uint64) error {
+	result := GetUserRecoveryCodeDB(ctx, a.DB).Where("user_id=?", userID).Delete(UserRecoveryCode{})
+	return errors.WithStack(result.Error)
+}
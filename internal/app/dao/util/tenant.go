@@ -0,0 +1,82 @@
+package util
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tenantSettingKey = "gin-admin:tenant_id"
+
+// TenantModel 按租户隔离的模型(查询、修改及删除限定为当前租户，创建时写入当前租户)
+type TenantModel struct {
+	TenantID uint64 `gorm:"index;default:0;"` // 租户ID(0为平台)
+}
+
+// SharedTenantModel 平台数据共享给所有租户的模型(查询时同时包含平台数据，修改及删除仍限定为当前租户)
+type SharedTenantModel struct {
+	TenantID uint64 `gorm:"index;default:0;"` // 租户ID(0为平台)
+}
+
+func (SharedTenantModel) sharedTenant() {}
+
+type sharedTenant interface {
+	sharedTenant()
+}
+
+// RegisterTenantCallbacks 注册租户隔离的回调(仅对包含TenantID字段且通过GetDB设置了租户的操作生效)
+func RegisterTenantCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", tenantCreateCallback); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", tenantQueryCallback(true)); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", tenantQueryCallback(true)); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", tenantQueryCallback(false)); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("tenant:delete", tenantQueryCallback(false))
+}
+
+func lookupTenant(db *gorm.DB) (string, uint64, bool) {
+	v, ok := db.Get(tenantSettingKey)
+	if !ok || db.Statement.Schema == nil {
+		return "", 0, false
+	}
+	field := db.Statement.Schema.LookUpField("TenantID")
+	if field == nil {
+		return "", 0, false
+	}
+	return field.DBName, v.(uint64), true
+}
+
+func tenantCreateCallback(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if column, tenantID, ok := lookupTenant(db); ok {
+		db.Statement.SetColumn(column, tenantID)
+	}
+}
+
+func tenantQueryCallback(withShared bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		column, tenantID, ok := lookupTenant(db)
+		if !ok {
+			return
+		}
+
+		col := clause.Column{Table: clause.CurrentTable, Name: column}
+		var expr clause.Expression = clause.Eq{Column: col, Value: tenantID}
+		if _, shared := db.Statement.Model.(sharedTenant); shared && withShared && tenantID != 0 {
+			expr = clause.IN{Column: col, Values: []interface{}{uint64(0), tenantID}}
+		}
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{expr}})
+	}
+}
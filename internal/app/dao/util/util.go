@@ -20,20 +20,23 @@ type Model struct {
 	UpdatedAt time.Time
 }
 
-// Get gorm.DB from context
+// Get gorm.DB from context (scoped by the tenant from context)
 func GetDB(ctx context.Context, defDB *gorm.DB) *gorm.DB {
+	db := defDB
 	trans, ok := contextx.FromTrans(ctx)
 	if ok && !contextx.FromNoTrans(ctx) {
-		db, ok := trans.(*gorm.DB)
-		if ok {
+		if tdb, ok := trans.(*gorm.DB); ok {
+			db = tdb
 			if contextx.FromTransLock(ctx) {
 				db = db.Clauses(clause.Locking{Strength: "UPDATE"})
 			}
-			return db
 		}
 	}
 
-	return defDB
+	if tenantID, ok := contextx.FromTenantID(ctx); ok {
+		db = db.Set(tenantSettingKey, tenantID)
+	}
+	return db
 }
 
 // Get gorm.DB.Model from context
@@ -41,6 +44,17 @@ func GetDBWithModel(ctx context.Context, defDB *gorm.DB, m interface{}) *gorm.DB
 	return GetDB(ctx, defDB).Model(m)
 }
 
+// Filter by the data scope (creators) from context
+func WrapDataScope(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if contextx.FromNoDataScope(ctx) {
+		return db
+	}
+	if creators, ok := contextx.FromDataScope(ctx); ok {
+		return db.Where("creator IN (?)", creators)
+	}
+	return db
+}
+
 // Define transaction execute function
 type TransFunc func(context.Context) error
 
@@ -147,6 +147,7 @@ func ResError(c *gin.Context, err error, status ...int) {
 	eitem := schema.ErrorItem{
 		Code:    res.Code,
 		Message: res.Message,
+		Details: res.Details,
 	}
 	ResJSON(c, res.Status, schema.ErrorResult{Error: eitem})
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
 	"github.com/LyricTian/gin-admin/v8/pkg/gormx"
 )
 
@@ -21,6 +22,11 @@ func InitGormDB() (*gorm.DB, func(), error) {
 
 	cleanFunc := func() {}
 
+	err = util.RegisterTenantCallbacks(db)
+	if err != nil {
+		return nil, cleanFunc, err
+	}
+
 	if cfg.EnableAutoMigrate {
 		err = dao.AutoMigrate(db)
 		if err != nil {
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 
+	"github.com/LyricTian/gin-admin/v8/internal/app/router"
 	"github.com/LyricTian/gin-admin/v8/internal/app/service"
 	"github.com/LyricTian/gin-admin/v8/pkg/auth"
 )
@@ -13,7 +14,10 @@ var InjectorSet = wire.NewSet(wire.Struct(new(Injector), "*"))
 
 type Injector struct {
 	Engine         *gin.Engine
+	Router         router.IRouter
 	Auth           auth.Auther
 	CasbinEnforcer *casbin.SyncedEnforcer
 	MenuSrv        *service.MenuSrv
+	SuperAdminSrv  *service.SuperAdminSrv
+	PermissionSrv  *service.PermissionSrv
 }
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/ldapauth"
+)
+
+func InitLDAP() *ldapauth.Client {
+	cfg := config.C.LDAP
+	if cfg.URL == "" {
+		return nil
+	}
+
+	return ldapauth.New(ldapauth.Config{
+		URL:                cfg.URL,
+		StartTLS:           cfg.StartTLS,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		Timeout:            time.Duration(cfg.Timeout) * time.Second,
+		BindDN:             cfg.BindDN,
+		BindPassword:       cfg.BindPassword,
+		BaseDN:             cfg.BaseDN,
+		UserFilter:         cfg.UserFilter,
+		IDAttr:             cfg.IDAttr,
+		UserNameAttr:       cfg.UserNameAttr,
+		RealNameAttr:       cfg.RealNameAttr,
+		EmailAttr:          cfg.EmailAttr,
+		PhoneAttr:          cfg.PhoneAttr,
+		GroupAttr:          cfg.GroupAttr,
+		GroupBaseDN:        cfg.GroupBaseDN,
+		GroupFilter:        cfg.GroupFilter,
+		GroupNameAttr:      cfg.GroupNameAttr,
+	})
+}
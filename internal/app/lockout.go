@@ -0,0 +1,34 @@
+package app
+
+import (
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/lockout"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/lockout/store/memory"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/lockout/store/redis"
+)
+
+func InitLoginLockout() (*lockout.Lockout, func(), error) {
+	cfg := config.C.LoginLockout
+
+	var store lockout.Store
+	switch cfg.Store {
+	case "redis":
+		rcfg := config.C.Redis
+		store = redis.NewStore(&redis.Config{
+			Addr:      rcfg.Addr,
+			Password:  rcfg.Password,
+			DB:        cfg.RedisDB,
+			KeyPrefix: cfg.RedisPrefix,
+		})
+	default:
+		store = memory.NewStore(time.Minute)
+	}
+
+	l := lockout.New(store)
+	cleanFunc := func() {
+		l.Release()
+	}
+	return l, cleanFunc, nil
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -9,39 +10,101 @@ import (
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
 	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
 	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
 	"github.com/LyricTian/gin-admin/v8/pkg/auth"
 	"github.com/LyricTian/gin-admin/v8/pkg/errors"
 	"github.com/LyricTian/gin-admin/v8/pkg/logger"
 )
 
-func wrapUserAuthContext(c *gin.Context, userID uint64, userName string) {
+func wrapUserAuthContext(c *gin.Context, userID uint64, userName string, scope ...string) {
 	ctx := contextx.NewUserID(c.Request.Context(), userID)
+	if len(scope) > 0 {
+		ctx = contextx.NewTokenScope(ctx, scope[0])
+	}
 	ctx = contextx.NewUserName(ctx, userName)
 	ctx = logger.NewUserIDContext(ctx, userID)
 	ctx = logger.NewUserNameContext(ctx, userName)
 	c.Request = c.Request.WithContext(ctx)
 }
 
-// Valid user token (jwt)
-func UserAuthMiddleware(a auth.Auther, skippers ...SkipperFunc) gin.HandlerFunc {
+// APIKeyVerifier API密钥校验
+type APIKeyVerifier interface {
+	// 检查令牌是否为API密钥
+	IsAPIKey(token string) bool
+	// 校验API密钥，返回所属用户及限定的角色
+	VerifyAPIKey(ctx context.Context, key string) (*schema.APIKeyIdentity, error)
+}
+
+// DefaultUserResolver 未开启认证(或调试模式下令牌无效)时使用的默认用户
+type DefaultUserResolver interface {
+	GetDefaultUser(ctx context.Context) (*schema.User, error)
+}
+
+// 跳过的接口(如登录)不要求存在默认用户，且不限定租户
+func wrapDefaultUserContext(c *gin.Context, d DefaultUserResolver, skippers ...SkipperFunc) error {
+	skipped := SkipHandler(c, skippers...)
+	user, err := d.GetDefaultUser(c.Request.Context())
+	if err != nil {
+		if skipped {
+			return nil
+		}
+		return err
+	}
+	wrapUserAuthContext(c, user.ID, user.UserName)
+	if !skipped {
+		wrapTokenTenantContext(c, user.TenantID)
+	}
+	return nil
+}
+
+// Valid user token (jwt or api key)
+func UserAuthMiddleware(a auth.Auther, k APIKeyVerifier, d DefaultUserResolver, skippers ...SkipperFunc) gin.HandlerFunc {
 	if !config.C.JWTAuth.Enable {
 		return func(c *gin.Context) {
-			wrapUserAuthContext(c, config.C.Root.UserID, config.C.Root.UserName)
+			ctx := auth.NewClientContext(c.Request.Context(), c.ClientIP(), c.Request.UserAgent())
+			c.Request = c.Request.WithContext(ctx)
+			if err := wrapDefaultUserContext(c, d, skippers...); err != nil {
+				ginx.ResError(c, err)
+				return
+			}
 			c.Next()
 		}
 	}
 
 	return func(c *gin.Context) {
+		// 记录客户端信息，用于登录会话
+		ctx := auth.NewClientContext(c.Request.Context(), c.ClientIP(), c.Request.UserAgent())
+		c.Request = c.Request.WithContext(ctx)
+
 		if SkipHandler(c, skippers...) {
 			c.Next()
 			return
 		}
 
-		tokenUserID, err := a.ParseUserID(c.Request.Context(), ginx.GetToken(c))
+		token := ginx.GetToken(c)
+		if k.IsAPIKey(token) {
+			identity, err := k.VerifyAPIKey(c.Request.Context(), token)
+			if err != nil {
+				ginx.ResError(c, err)
+				return
+			}
+
+			ctx := contextx.NewAPIKey(c.Request.Context(), identity.APIKeyID, identity.RoleIDs, identity.Restricted)
+			c.Request = c.Request.WithContext(ctx)
+			wrapUserAuthContext(c, identity.UserID, identity.UserName)
+			wrapTokenTenantContext(c, identity.TenantID)
+			c.Next()
+			return
+		}
+
+		claims, err := a.ParseToken(c.Request.Context(), token)
 		if err != nil {
 			if err == auth.ErrInvalidToken {
 				if config.C.IsDebugMode() {
-					wrapUserAuthContext(c, config.C.Root.UserID, config.C.Root.UserName)
+					if err := wrapDefaultUserContext(c, d); err != nil {
+						ginx.ResError(c, err)
+						return
+					}
 					c.Next()
 					return
 				}
@@ -52,14 +115,42 @@ func UserAuthMiddleware(a auth.Auther, skippers ...SkipperFunc) gin.HandlerFunc
 			return
 		}
 
+		tokenUserID := claims.Subject
 		idx := strings.Index(tokenUserID, "-")
 		if idx == -1 {
 			ginx.ResError(c, errors.ErrInvalidToken)
 			return
 		}
 
+		// 未记录租户的令牌属于平台
+		var tenantID uint64
+		if claims.Tenant != "" {
+			tenantID, err = strconv.ParseUint(claims.Tenant, 10, 64)
+			if err != nil {
+				ginx.ResError(c, errors.ErrInvalidToken)
+				return
+			}
+		}
+
 		userID, _ := strconv.ParseUint(tokenUserID[:idx], 10, 64)
-		wrapUserAuthContext(c, userID, tokenUserID[idx+1:])
+		wrapUserAuthContext(c, userID, tokenUserID[idx+1:], claims.Scope)
+		wrapTokenTenantContext(c, tenantID)
+		c.Next()
+	}
+}
+
+// PasswordChangeMiddleware 需要修改密码的令牌仅允许访问跳过的接口(修改密码、获取用户信息、退出登录)
+func PasswordChangeMiddleware(skippers ...SkipperFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if SkipHandler(c, skippers...) {
+			c.Next()
+			return
+		}
+
+		if contextx.FromTokenScope(c.Request.Context()) == auth.ScopePasswordChange {
+			ginx.ResError(c, errors.ErrPasswordChange)
+			return
+		}
 		c.Next()
 	}
 }
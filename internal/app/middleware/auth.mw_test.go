@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestPasswordChangeMiddleware(t *testing.T) {
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		if scope := c.GetHeader("X-Token-Scope"); scope != "" {
+			c.Request = c.Request.WithContext(contextx.NewTokenScope(c.Request.Context(), scope))
+		}
+	})
+	engine.Use(PasswordChangeMiddleware(AllowPathPrefixSkipper("/api/v1/pub/current/password")))
+	engine.Any("/*path", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(method, path, scope string) int {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("X-Token-Scope", scope)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 必须修改密码的令牌仅允许修改密码
+	assert.Equal(t, 403, serve("GET", "/api/v1/users", auth.ScopePasswordChange))
+	assert.Equal(t, 200, serve("PUT", "/api/v1/pub/current/password", auth.ScopePasswordChange))
+	assert.Equal(t, 200, serve("GET", "/api/v1/users", ""))
+}
+
+type testAPIKeyVerifier map[string]*schema.APIKeyIdentity
+
+func (v testAPIKeyVerifier) IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, "gak_")
+}
+
+func (v testAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*schema.APIKeyIdentity, error) {
+	if identity, ok := v[key]; ok {
+		return identity, nil
+	}
+	return nil, errors.ErrInvalidToken
+}
+
+func TestUserAuthMiddlewareAPIKey(t *testing.T) {
+	jwtConfig := config.C.JWTAuth
+	defer func() { config.C.JWTAuth = jwtConfig }()
+	config.C.JWTAuth.Enable = true
+
+	verifier := testAPIKeyVerifier{
+		"gak_valid": {APIKeyID: 1, UserID: 12, UserName: "u12", Restricted: true, RoleIDs: []string{"2"}},
+	}
+
+	engine := gin.New()
+	engine.Use(UserAuthMiddleware(nil, verifier, nil))
+	engine.GET("/api/v1/users", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		roleIDs, restricted := contextx.FromAPIKeyRoles(ctx)
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":    contextx.FromUserID(ctx),
+			"user_name":  contextx.FromUserName(ctx),
+			"api_key_id": contextx.FromAPIKeyID(ctx),
+			"role_ids":   roleIDs,
+			"restricted": restricted,
+		})
+	})
+
+	serve := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/api/v1/users", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// API密钥按所属用户及限定的角色认证
+	w := serve("gak_valid")
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `{"user_id":12,"user_name":"u12","api_key_id":1,"role_ids":["2"],"restricted":true}`, w.Body.String())
+
+	w = serve("gak_invalid")
+	assert.Equal(t, 401, w.Code)
+}
@@ -1,18 +1,23 @@
 package middleware
 
 import (
-	"strconv"
+	"context"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
 	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
 	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
 	"github.com/LyricTian/gin-admin/v8/pkg/errors"
-	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 )
 
+// PermissionEnforcer 接口权限校验
+type PermissionEnforcer interface {
+	// 校验用户访问接口的权限
+	Enforce(ctx context.Context, userID uint64, path, method string) (bool, error)
+}
+
 // Valid use interface permission
-func CasbinMiddleware(enforcer *casbin.SyncedEnforcer, skippers ...SkipperFunc) gin.HandlerFunc {
+func CasbinMiddleware(e PermissionEnforcer, skippers ...SkipperFunc) gin.HandlerFunc {
 	cfg := config.C.Casbin
 	if !cfg.Enable {
 		return EmptyMiddleware()
@@ -24,11 +29,9 @@ func CasbinMiddleware(enforcer *casbin.SyncedEnforcer, skippers ...SkipperFunc)
 			return
 		}
 
-		p := c.Request.URL.Path
-		m := c.Request.Method
-		userID := contextx.FromUserID(c.Request.Context())
-		if b, err := enforcer.Enforce(strconv.FormatUint(userID, 10), p, m); err != nil {
-			ginx.ResError(c, errors.WithStack(err))
+		ctx := c.Request.Context()
+		if b, err := e.Enforce(ctx, contextx.FromUserID(ctx), c.Request.URL.Path, c.Request.Method); err != nil {
+			ginx.ResError(c, err)
 			return
 		} else if !b {
 			ginx.ResError(c, errors.ErrNoPerm)
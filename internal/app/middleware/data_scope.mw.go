@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
+)
+
+// DataScopeResolver 数据范围计算
+type DataScopeResolver interface {
+	// 获取用户的数据范围，all为true表示不限定，否则返回可访问数据的创建者ID列表
+	Resolve(ctx context.Context, userID uint64) (creators []uint64, all bool, err error)
+}
+
+// 根据当前用户的角色限定可访问的数据(由dao层按创建者过滤)
+func DataScopeMiddleware(r DataScopeResolver, skippers ...SkipperFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if SkipHandler(c, skippers...) {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		creators, all, err := r.Resolve(ctx, contextx.FromUserID(ctx))
+		if err != nil {
+			ginx.ResError(c, err)
+			return
+		} else if !all {
+			c.Request = c.Request.WithContext(contextx.NewDataScope(ctx, creators))
+		}
+		c.Next()
+	}
+}
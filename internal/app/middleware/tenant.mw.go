@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/ginx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+// TenantResolver 租户识别
+type TenantResolver interface {
+	// 按租户编号获取启用的租户ID
+	GetEnabledID(ctx context.Context, code string) (uint64, error)
+	// 检查租户是否存在且启用
+	CheckEnabled(ctx context.Context, id uint64) error
+}
+
+// 记录令牌(或API密钥所属用户)的租户，由租户中间件与请求指定的租户比对
+func wrapTokenTenantContext(c *gin.Context, tenantID uint64) {
+	if !config.C.Tenant.Enable {
+		return
+	}
+	c.Request = c.Request.WithContext(contextx.NewTenantID(c.Request.Context(), tenantID))
+}
+
+// 从请求头或子域名获取租户编号(请求头优先)
+func getTenantCode(c *gin.Context) string {
+	cfg := config.C.Tenant
+	if cfg.Header != "" {
+		if code := c.GetHeader(cfg.Header); code != "" {
+			return code
+		}
+	}
+
+	if cfg.Domain == "" {
+		return ""
+	}
+	host := c.Request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	suffix := "." + strings.TrimPrefix(cfg.Domain, ".")
+	if !strings.HasSuffix(host, suffix) {
+		return ""
+	}
+	code := strings.TrimSuffix(host, suffix)
+	if strings.Contains(code, ".") {
+		return ""
+	}
+	return code
+}
+
+// TenantMiddleware 识别请求的租户(请求头、子域名或令牌)，令牌所属租户与请求指定的租户不一致时拒绝访问，均未指定时为平台
+func TenantMiddleware(r TenantResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.C.Tenant.Enable {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		tenantID := schema.PlatformTenantID
+		code := getTenantCode(c)
+		if code != "" {
+			id, err := r.GetEnabledID(ctx, code)
+			if err != nil {
+				ginx.ResError(c, err)
+				return
+			}
+			tenantID = id
+		}
+
+		if tokenTenantID, ok := contextx.FromTenantID(ctx); ok {
+			if code != "" && tokenTenantID != tenantID {
+				ginx.ResError(c, errors.ErrInvalidToken)
+				return
+			} else if code == "" && tokenTenantID != schema.PlatformTenantID {
+				if err := r.CheckEnabled(ctx, tokenTenantID); err != nil {
+					ginx.ResError(c, err)
+					return
+				}
+			}
+			tenantID = tokenTenantID
+		}
+
+		c.Request = c.Request.WithContext(contextx.NewTenantID(ctx, tenantID))
+		c.Next()
+	}
+}
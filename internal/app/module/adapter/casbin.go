@@ -2,27 +2,66 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
 
+	"github.com/casbin/casbin/v2"
 	casbinModel "github.com/casbin/casbin/v2/model"
 	"github.com/casbin/casbin/v2/persist"
 	"github.com/google/wire"
 
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
 	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
 	"github.com/LyricTian/gin-admin/v8/pkg/logger"
 )
 
-var _ persist.Adapter = (*CasbinAdapter)(nil)
+var (
+	_ persist.FilteredAdapter = (*CasbinAdapter)(nil)
+	_ persist.BatchAdapter    = (*CasbinAdapter)(nil)
+)
+
+// 按用户ID批量查询时每批的数量
+const userBatchSize = 500
 
 var CasbinAdapterSet = wire.NewSet(wire.Struct(new(CasbinAdapter), "*"), wire.Bind(new(persist.Adapter), new(*CasbinAdapter)))
 
+// CasbinAdapter 从角色、角色继承、菜单资源及用户角色表加载策略(以租户ID作为域)；
+// 策略随业务数据在各服务中持久化，增量变更通过策略变更监听(CasbinWatcher)通知其他实例重新加载
 type CasbinAdapter struct {
 	RoleRepo         *dao.RoleRepo
 	RoleMenuRepo     *dao.RoleMenuRepo
+	RoleParentRepo   *dao.RoleParentRepo
 	MenuResourceRepo *dao.MenuActionResourceRepo
 	UserRepo         *dao.UserRepo
 	UserRoleRepo     *dao.UserRoleRepo
+
+	once   sync.Once  `wire:"-"`
+	users  *userCache `wire:"-"`
+	loadMu sync.Mutex `wire:"-"` // 按需加载时串行更新用户的角色
+}
+
+// Filter 按条件加载策略
+type Filter struct {
+	UserIDs []uint64 // 加载指定用户的角色(g)
+}
+
+// 是否按需加载用户的角色(g)，角色策略(p)及角色继承(g)始终全部加载
+func (a *CasbinAdapter) lazyLoadUser() bool {
+	return config.C.Casbin.LazyLoadUser
+}
+
+func (a *CasbinAdapter) getUserCache() *userCache {
+	a.once.Do(func() {
+		size := config.C.Casbin.UserCacheSize
+		if size <= 0 {
+			size = 10000
+		}
+		a.users = newUserCache(size)
+	})
+	return a.users
 }
 
 // Loads all policy rules from the storage.
@@ -34,7 +73,12 @@ func (a *CasbinAdapter) LoadPolicy(model casbinModel.Model) error {
 		return err
 	}
 
-	err = a.loadUserPolicy(ctx, model)
+	if a.lazyLoadUser() {
+		// 仅重新加载已缓存的用户
+		err = a.loadUserPolicyByIDs(ctx, model, a.getUserCache().Keys())
+	} else {
+		err = a.loadUserPolicy(ctx, model)
+	}
 	if err != nil {
 		logger.WithContext(ctx).Errorf("Load casbin user policy error: %s", err.Error())
 		return err
@@ -43,7 +87,7 @@ func (a *CasbinAdapter) LoadPolicy(model casbinModel.Model) error {
 	return nil
 }
 
-// Load role policy (p,role_id,path,method)
+// Load role policy (p,role_id,tenant_id,path,method) and role inheritance (g,role_id,parent_id,tenant_id)
 func (a *CasbinAdapter) loadRolePolicy(ctx context.Context, m casbinModel.Model) error {
 	roleResult, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{
 		Status: 1,
@@ -66,6 +110,23 @@ func (a *CasbinAdapter) loadRolePolicy(ctx context.Context, m casbinModel.Model)
 	}
 	mMenuResources := menuResourceResult.Data.ToActionIDMap()
 
+	roleParentResult, err := a.RoleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+		RoleIDs: roleResult.Data.ToIDs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	mRoles := roleResult.Data.ToMap()
+	for _, item := range roleParentResult.Data {
+		ritem, ok := mRoles[item.RoleID]
+		if !ok {
+			continue
+		}
+		line := fmt.Sprintf("g,%d,%d,%d", item.RoleID, item.ParentID, ritem.TenantID)
+		persist.LoadPolicyLine(line, m)
+	}
+
 	for _, item := range roleResult.Data {
 		mcache := make(map[string]struct{})
 		if rms, ok := mRoleMenus[item.ID]; ok {
@@ -78,7 +139,7 @@ func (a *CasbinAdapter) loadRolePolicy(ctx context.Context, m casbinModel.Model)
 							continue
 						}
 						mcache[mr.Path+mr.Method] = struct{}{}
-						line := fmt.Sprintf("p,%d,%s,%s", item.ID, mr.Path, mr.Method)
+						line := fmt.Sprintf("p,%d,%d,%s,%s", item.ID, item.TenantID, mr.Path, mr.Method)
 						persist.LoadPolicyLine(line, m)
 					}
 				}
@@ -89,7 +150,7 @@ func (a *CasbinAdapter) loadRolePolicy(ctx context.Context, m casbinModel.Model)
 	return nil
 }
 
-// Load user policy (g,user_id,role_id)
+// Load user policy (g,user_id,role_id,tenant_id) and super admin (g,user_id,super_admin,tenant_id)
 func (a *CasbinAdapter) loadUserPolicy(ctx context.Context, m casbinModel.Model) error {
 	userResult, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
 		Status: 1,
@@ -104,9 +165,13 @@ func (a *CasbinAdapter) loadUserPolicy(ctx context.Context, m casbinModel.Model)
 
 		mUserRoles := userRoleResult.Data.ToUserIDMap()
 		for _, uitem := range userResult.Data {
+			if uitem.IsSuperAdmin {
+				line := fmt.Sprintf("g,%d,%s,%d", uitem.ID, schema.SuperAdminRole, uitem.TenantID)
+				persist.LoadPolicyLine(line, m)
+			}
 			if urs, ok := mUserRoles[uitem.ID]; ok {
 				for _, ur := range urs {
-					line := fmt.Sprintf("g,%d,%d", ur.UserID, ur.RoleID)
+					line := fmt.Sprintf("g,%d,%d,%d", ur.UserID, ur.RoleID, uitem.TenantID)
 					persist.LoadPolicyLine(line, m)
 				}
 			}
@@ -116,6 +181,154 @@ func (a *CasbinAdapter) loadUserPolicy(ctx context.Context, m casbinModel.Model)
 	return nil
 }
 
+// Load user policy by user ids (g,user_id,role_id,tenant_id and g,user_id,super_admin,tenant_id), skip the loaded lines
+func (a *CasbinAdapter) loadUserPolicyByIDs(ctx context.Context, m casbinModel.Model, userIDs []uint64) error {
+	rules, err := a.queryUserRules(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if !m.HasPolicy("g", "g", rule) {
+			m.AddPolicy("g", "g", rule)
+		}
+	}
+	return nil
+}
+
+// 查询指定用户的角色规则(user_id,role_id,tenant_id及user_id,super_admin,tenant_id)
+func (a *CasbinAdapter) queryUserRules(ctx context.Context, userIDs []uint64) ([][]string, error) {
+	var rules [][]string
+	for start := 0; start < len(userIDs); start += userBatchSize {
+		end := start + userBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		userResult, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+			IDs:    userIDs[start:end],
+			Status: 1,
+		}, schema.UserQueryOptions{
+			SelectFields: []string{"id", "is_super_admin", "tenant_id"},
+		})
+		if err != nil {
+			return nil, err
+		} else if len(userResult.Data) == 0 {
+			continue
+		}
+
+		mUsers := userResult.Data.ToMap()
+		for _, uitem := range userResult.Data {
+			if uitem.IsSuperAdmin {
+				rules = append(rules, []string{strconv.FormatUint(uitem.ID, 10), schema.SuperAdminRole, schema.TenantDomain(uitem.TenantID)})
+			}
+		}
+
+		userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+			UserIDs: userResult.Data.ToIDs(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ur := range userRoleResult.Data {
+			tenantID := mUsers[ur.UserID].TenantID
+			rules = append(rules, []string{strconv.FormatUint(ur.UserID, 10), strconv.FormatUint(ur.RoleID, 10), schema.TenantDomain(tenantID)})
+		}
+	}
+
+	return rules, nil
+}
+
+// LoadFilteredPolicy loads only policy rules that match the filter.
+func (a *CasbinAdapter) LoadFilteredPolicy(model casbinModel.Model, filter interface{}) error {
+	var f Filter
+	switch v := filter.(type) {
+	case Filter:
+		f = v
+	case *Filter:
+		f = *v
+	default:
+		return errors.New("invalid casbin filter type")
+	}
+
+	ctx := context.Background()
+	err := a.loadUserPolicyByIDs(ctx, model, f.UserIDs)
+	if err != nil {
+		logger.WithContext(ctx).Errorf("Load casbin filtered user policy error: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// IsFiltered returns true if the loaded policy has been filtered.
+// 角色策略始终全部加载(返回true时casbin初始化时不会加载策略)
+func (a *CasbinAdapter) IsFiltered() bool {
+	return false
+}
+
+// LoadUser 按需加载用户的角色(首次校验权限时从数据库加载并缓存，未开启按需加载时不做处理)；
+// 仅增量添加该用户及移除被淘汰用户的角色关系，不重新构建全部角色关系，也不通知其他实例
+func (a *CasbinAdapter) LoadUser(e *casbin.SyncedEnforcer, userID uint64) error {
+	if !a.lazyLoadUser() || userID == 0 {
+		return nil
+	}
+
+	cache := a.getUserCache()
+	entry, added, evicted := cache.GetOrAdd(userID)
+	if !added {
+		// 等待其他请求加载完成
+		<-entry.ready
+		return nil
+	}
+	defer close(entry.ready)
+
+	err := a.loadUser(e, userID, evicted)
+	if err != nil {
+		cache.Remove(entry)
+		return err
+	}
+	return nil
+}
+
+func (a *CasbinAdapter) loadUser(e *casbin.SyncedEnforcer, userID uint64, evicted []uint64) error {
+	rules, err := a.queryUserRules(context.Background(), []uint64{userID})
+	if err != nil {
+		return err
+	}
+
+	a.loadMu.Lock()
+	defer a.loadMu.Unlock()
+
+	for _, id := range evicted {
+		// 淘汰后又重新加入缓存的用户保留角色
+		if a.getUserCache().Contains(id) {
+			continue
+		}
+		_, err := e.RemoveFilteredGroupingPolicy(0, strconv.FormatUint(id, 10))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil
+	} else if ok, err := e.AddGroupingPolicies(rules); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	// 部分规则已存在(如刚为该用户分配的角色)时批量添加不生效，逐条添加
+	for _, rule := range rules {
+		_, err := e.AddGroupingPolicy(rule)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SavePolicy saves all policy rules to the storage.
 func (a *CasbinAdapter) SavePolicy(model casbinModel.Model) error {
 	return nil
@@ -127,12 +340,24 @@ func (a *CasbinAdapter) AddPolicy(sec string, ptype string, rule []string) error
 	return nil
 }
 
+// AddPolicies adds policy rules to the storage.
+// This is part of the Auto-Save feature.
+func (a *CasbinAdapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	return nil
+}
+
 // RemovePolicy removes a policy rule from the storage.
 // This is part of the Auto-Save feature.
 func (a *CasbinAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
 	return nil
 }
 
+// RemovePolicies removes policy rules from the storage.
+// This is part of the Auto-Save feature.
+func (a *CasbinAdapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	return nil
+}
+
 // RemoveFilteredPolicy removes policy rules that match the filter from the storage.
 // This is part of the Auto-Save feature.
 func (a *CasbinAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
@@ -0,0 +1,260 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+)
+
+const modelFile = "../../../../configs/model.conf"
+
+func newTestAdapter(t *testing.T) *CasbinAdapter {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &CasbinAdapter{
+		RoleRepo:         &dao.RoleRepo{DB: db},
+		RoleMenuRepo:     &dao.RoleMenuRepo{DB: db},
+		RoleParentRepo:   &dao.RoleParentRepo{DB: db},
+		MenuResourceRepo: &dao.MenuActionResourceRepo{DB: db},
+		UserRepo:         &dao.UserRepo{DB: db},
+		UserRoleRepo:     &dao.UserRoleRepo{DB: db},
+	}
+
+	ctx := context.Background()
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 1, Name: "role", Status: 1}))
+	assert.Nil(t, a.RoleMenuRepo.Create(ctx, schema.RoleMenu{ID: 1, RoleID: 1, MenuID: 1, ActionID: 1}))
+	assert.Nil(t, a.MenuResourceRepo.Create(ctx, schema.MenuActionResource{ID: 1, ActionID: 1, Method: "GET", Path: "/api/v1/users"}))
+	for i := uint64(11); i <= 14; i++ {
+		assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: i, UserName: string(rune('a' + i)), Status: 1}))
+		assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: i, UserID: i, RoleID: 1}))
+	}
+	return a
+}
+
+func TestLazyLoadUser(t *testing.T) {
+	casbinConfig := config.C.Casbin
+	defer func() { config.C.Casbin = casbinConfig }()
+	config.C.Casbin.LazyLoadUser = true
+	config.C.Casbin.UserCacheSize = 2
+
+	a := newTestAdapter(t)
+	e, err := casbin.NewSyncedEnforcer(modelFile, a)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// 启动时只加载角色策略
+	assert.Equal(t, 1, len(e.GetPolicy()))
+	assert.Equal(t, 0, len(e.GetGroupingPolicy()))
+
+	ok, _ := e.Enforce("11", "0", "/api/v1/users", "GET")
+	assert.False(t, ok)
+
+	assert.Nil(t, a.LoadUser(e, 11))
+	ok, _ = e.Enforce("11", "0", "/api/v1/users", "GET")
+	assert.True(t, ok)
+
+	// 重新加载时保留已缓存的用户
+	assert.Nil(t, e.LoadPolicy())
+	ok, _ = e.Enforce("11", "0", "/api/v1/users", "GET")
+	assert.True(t, ok)
+
+	// 直接添加到角色管理器(不在策略中)的关系仅在重新构建全部角色关系时丢失，用于检查按需加载不会重新构建
+	assert.Nil(t, e.GetRoleManager().AddLink("99", "1", "0"))
+
+	assert.Nil(t, a.LoadUser(e, 12))
+	assert.Nil(t, a.LoadUser(e, 11))
+	assert.Equal(t, 2, len(e.GetGroupingPolicy()))
+
+	// 新增的角色策略仅在重新加载全部策略时生效
+	ctx := context.Background()
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 2, Name: "role2", Status: 1}))
+	assert.Nil(t, a.RoleMenuRepo.Create(ctx, schema.RoleMenu{ID: 2, RoleID: 2, MenuID: 2, ActionID: 2}))
+	assert.Nil(t, a.MenuResourceRepo.Create(ctx, schema.MenuActionResource{ID: 2, ActionID: 2, Method: "GET", Path: "/api/v1/roles"}))
+
+	// 超出容量时淘汰最久未使用的用户，仅移除被淘汰用户的角色，不重新加载全部策略
+	assert.Nil(t, a.LoadUser(e, 13))
+	ok, _ = e.Enforce("11", "0", "/api/v1/users", "GET")
+	assert.False(t, ok)
+	ok, _ = e.Enforce("12", "0", "/api/v1/users", "GET")
+	assert.False(t, ok)
+	ok, _ = e.Enforce("13", "0", "/api/v1/users", "GET")
+	assert.True(t, ok)
+	assert.ElementsMatch(t, [][]string{{"13", "1", "0"}}, e.GetGroupingPolicy())
+	assert.Equal(t, 1, len(e.GetPolicy()))
+
+	assert.Nil(t, a.LoadUser(e, 14))
+	assert.Nil(t, a.LoadUser(e, 11))
+	ok, _ = e.Enforce("11", "0", "/api/v1/users", "GET")
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(e.GetPolicy()))
+
+	// 加载及淘汰用户时未重新构建其他用户的角色关系
+	ok, _ = e.Enforce("99", "0", "/api/v1/users", "GET")
+	assert.True(t, ok)
+}
+
+func TestRoleInheritance(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:inherit?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &CasbinAdapter{
+		RoleRepo:         &dao.RoleRepo{DB: db},
+		RoleMenuRepo:     &dao.RoleMenuRepo{DB: db},
+		RoleParentRepo:   &dao.RoleParentRepo{DB: db},
+		MenuResourceRepo: &dao.MenuActionResourceRepo{DB: db},
+		UserRepo:         &dao.UserRepo{DB: db},
+		UserRoleRepo:     &dao.UserRoleRepo{DB: db},
+	}
+
+	// 角色2继承角色1，禁用的角色3继承角色1
+	ctx := context.Background()
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 1, Name: "viewer", Status: 1}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 2, Name: "auditor", Status: 1}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 3, Name: "disabled", Status: 2}))
+	assert.Nil(t, a.RoleParentRepo.Create(ctx, schema.RoleParent{ID: 1, RoleID: 2, ParentID: 1}))
+	assert.Nil(t, a.RoleParentRepo.Create(ctx, schema.RoleParent{ID: 2, RoleID: 3, ParentID: 1}))
+	assert.Nil(t, a.RoleMenuRepo.Create(ctx, schema.RoleMenu{ID: 1, RoleID: 1, MenuID: 1, ActionID: 1}))
+	assert.Nil(t, a.MenuResourceRepo.Create(ctx, schema.MenuActionResource{ID: 1, ActionID: 1, Method: "GET", Path: "/api/v1/users"}))
+	for i := uint64(2); i <= 3; i++ {
+		assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 10 + i, UserName: string(rune('a' + i)), Status: 1}))
+		assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: i, UserID: 10 + i, RoleID: i}))
+	}
+
+	e, err := casbin.NewSyncedEnforcer(modelFile, a)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	ok, _ := e.Enforce("12", "0", "/api/v1/users", "GET")
+	assert.True(t, ok)
+	ok, _ = e.Enforce("13", "0", "/api/v1/users", "GET")
+	assert.False(t, ok)
+}
+
+func TestSuperAdmin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:superadmin?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &CasbinAdapter{
+		RoleRepo:         &dao.RoleRepo{DB: db},
+		RoleMenuRepo:     &dao.RoleMenuRepo{DB: db},
+		RoleParentRepo:   &dao.RoleParentRepo{DB: db},
+		MenuResourceRepo: &dao.MenuActionResourceRepo{DB: db},
+		UserRepo:         &dao.UserRepo{DB: db},
+		UserRoleRepo:     &dao.UserRoleRepo{DB: db},
+	}
+
+	// 用户21为超级管理员，用户22为停用的超级管理员，用户23为普通用户
+	ctx := context.Background()
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 21, UserName: "admin1", Status: 1, IsSuperAdmin: true}))
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 22, UserName: "admin2", Status: 2, IsSuperAdmin: true}))
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 23, UserName: "user", Status: 1}))
+
+	check := func(e *casbin.SyncedEnforcer) {
+		ok, _ := e.Enforce("21", "0", "/api/v1/users", "DELETE")
+		assert.True(t, ok)
+		ok, _ = e.Enforce("22", "0", "/api/v1/users", "DELETE")
+		assert.False(t, ok)
+		ok, _ = e.Enforce("23", "0", "/api/v1/users", "DELETE")
+		assert.False(t, ok)
+	}
+
+	e, err := casbin.NewSyncedEnforcer(modelFile, a)
+	if !assert.Nil(t, err) {
+		return
+	}
+	check(e)
+
+	casbinConfig := config.C.Casbin
+	defer func() { config.C.Casbin = casbinConfig }()
+	config.C.Casbin.LazyLoadUser = true
+
+	a = &CasbinAdapter{
+		RoleRepo:         a.RoleRepo,
+		RoleMenuRepo:     a.RoleMenuRepo,
+		RoleParentRepo:   a.RoleParentRepo,
+		MenuResourceRepo: a.MenuResourceRepo,
+		UserRepo:         a.UserRepo,
+		UserRoleRepo:     a.UserRoleRepo,
+	}
+	e, err = casbin.NewSyncedEnforcer(modelFile, a)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for i := uint64(21); i <= 23; i++ {
+		assert.Nil(t, a.LoadUser(e, i))
+	}
+	check(e)
+}
+
+func TestTenantDomain(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:tenant?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, util.RegisterTenantCallbacks(db))
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &CasbinAdapter{
+		RoleRepo:         &dao.RoleRepo{DB: db},
+		RoleMenuRepo:     &dao.RoleMenuRepo{DB: db},
+		RoleParentRepo:   &dao.RoleParentRepo{DB: db},
+		MenuResourceRepo: &dao.MenuActionResourceRepo{DB: db},
+		UserRepo:         &dao.UserRepo{DB: db},
+		UserRoleRepo:     &dao.UserRoleRepo{DB: db},
+	}
+
+	// 租户5的用户31拥有角色1，用户32为租户5的超级管理员，平台用户33与用户31同名
+	ctx := contextx.NewTenantID(context.Background(), 5)
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 1, Name: "viewer", Status: 1}))
+	assert.Nil(t, a.RoleMenuRepo.Create(ctx, schema.RoleMenu{ID: 1, RoleID: 1, MenuID: 1, ActionID: 1}))
+	assert.Nil(t, a.MenuResourceRepo.Create(ctx, schema.MenuActionResource{ID: 1, ActionID: 1, Method: "GET", Path: "/api/v1/users"}))
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 31, UserName: "user", Status: 1}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 1, UserID: 31, RoleID: 1}))
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 32, UserName: "admin", Status: 1, IsSuperAdmin: true}))
+	assert.Nil(t, a.UserRepo.Create(contextx.NewTenantID(context.Background(), 0), schema.User{ID: 33, UserName: "user", Status: 1}))
+
+	// 按租户隔离查询
+	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{UserName: "user"})
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(result.Data)) {
+		assert.Equal(t, uint64(31), result.Data[0].ID)
+	}
+
+	e, err := casbin.NewSyncedEnforcer(modelFile, a)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.ElementsMatch(t, [][]string{{"1", "5", "/api/v1/users", "GET"}}, e.GetPolicy())
+	ok, _ := e.Enforce("31", "5", "/api/v1/users", "GET")
+	assert.True(t, ok)
+	ok, _ = e.Enforce("31", "0", "/api/v1/users", "GET")
+	assert.False(t, ok)
+	ok, _ = e.Enforce("32", "5", "/api/v1/users", "DELETE")
+	assert.True(t, ok)
+	ok, _ = e.Enforce("32", "0", "/api/v1/users", "DELETE")
+	assert.False(t, ok)
+	ok, _ = e.Enforce("33", "0", "/api/v1/users", "GET")
+	assert.False(t, ok)
+}
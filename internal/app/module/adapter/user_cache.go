@@ -0,0 +1,83 @@
+package adapter
+
+import (
+	"container/list"
+	"sync"
+)
+
+type userCacheEntry struct {
+	id    uint64
+	ready chan struct{} // 角色策略加载完成后关闭
+}
+
+// 已加载角色策略的用户(LRU)
+type userCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[uint64]*list.Element
+}
+
+func newUserCache(size int) *userCache {
+	return &userCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[uint64]*list.Element),
+	}
+}
+
+// 获取用户(标记为最近使用)，不存在时添加；
+// 超出容量时淘汰最久未使用的用户至容量的3/4(批量淘汰以减少移除角色策略的次数)，返回被淘汰的用户
+func (c *userCache) GetOrAdd(id uint64) (entry *userCacheEntry, added bool, evicted []uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[id]; ok {
+		c.ll.MoveToFront(e)
+		return e.Value.(*userCacheEntry), false, nil
+	}
+
+	entry = &userCacheEntry{id: id, ready: make(chan struct{})}
+	c.items[id] = c.ll.PushFront(entry)
+	if c.ll.Len() <= c.size {
+		return entry, true, nil
+	}
+
+	for c.ll.Len() > c.size*3/4 {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		eid := e.Value.(*userCacheEntry).id
+		delete(c.items, eid)
+		evicted = append(evicted, eid)
+	}
+	return entry, true, evicted
+}
+
+func (c *userCache) Remove(entry *userCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[entry.id]; ok && e.Value == entry {
+		c.ll.Remove(e)
+		delete(c.items, entry.id)
+	}
+}
+
+func (c *userCache) Contains(id uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.items[id]
+	return ok
+}
+
+func (c *userCache) Keys() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]uint64, 0, len(c.items))
+	for id := range c.items {
+		keys = append(keys, id)
+	}
+	return keys
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc/store/memory"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc/store/redis"
+)
+
+func InitOIDC() (*oidc.Provider, func(), error) {
+	cfg := config.C.OIDC
+	if !cfg.Enable {
+		return nil, func() {}, nil
+	}
+
+	var store oidc.StateStore
+	switch cfg.StateStore {
+	case "redis":
+		rcfg := config.C.Redis
+		store = redis.NewStore(&redis.Config{
+			Addr:      rcfg.Addr,
+			Password:  rcfg.Password,
+			DB:        cfg.RedisDB,
+			KeyPrefix: cfg.RedisPrefix,
+		})
+	default:
+		store = memory.NewStore(time.Minute)
+	}
+
+	p := oidc.New(oidc.Config{
+		Issuer:        cfg.Issuer,
+		ClientID:      cfg.ClientID,
+		ClientSecret:  cfg.ClientSecret,
+		RedirectURL:   cfg.RedirectURL,
+		Scopes:        cfg.Scopes,
+		UsernameClaim: cfg.UsernameClaim,
+		GroupsClaim:   cfg.GroupsClaim,
+	}, store)
+	cleanFunc := func() {
+		p.Release()
+	}
+	return p, cleanFunc, nil
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
+)
+
+func InitPasswordHasher() (hash.PasswordHasher, error) {
+	cfg := config.C.PasswordHash
+
+	switch cfg.Algorithm {
+	case hash.AlgorithmBcrypt:
+		if cfg.BcryptCost > 0 {
+			return hash.NewBcryptHasher(cfg.BcryptCost), nil
+		}
+	case hash.AlgorithmArgon2id:
+		params := hash.DefaultArgon2idParams
+		if cfg.Argon2Time > 0 {
+			params.Time = uint32(cfg.Argon2Time)
+		}
+		if cfg.Argon2Memory > 0 {
+			params.Memory = uint32(cfg.Argon2Memory)
+		}
+		if cfg.Argon2Threads > 0 {
+			params.Threads = uint8(cfg.Argon2Threads)
+		}
+		return hash.NewArgon2idHasher(params), nil
+	}
+
+	return hash.NewPasswordHasher(cfg.Algorithm)
+}
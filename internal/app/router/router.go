@@ -1,12 +1,15 @@
 package router
 
 import (
-	"github.com/casbin/casbin/v2"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/api"
 	"github.com/LyricTian/gin-admin/v8/internal/app/middleware"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/internal/app/service"
 	"github.com/LyricTian/gin-admin/v8/pkg/auth"
 )
 
@@ -17,19 +20,33 @@ var RouterSet = wire.NewSet(wire.Struct(new(Router), "*"), wire.Bind(new(IRouter
 type IRouter interface {
 	Register(app *gin.Engine) error
 	Prefixes() []string
+	PermissionRoutes(app *gin.Engine) schema.Routes
+}
+
+// 跳过权限校验的接口前缀
+var permissionSkipPrefixes = []string{
+	"/api/v1/pub",
 }
 
 type Router struct {
-	Auth           auth.Auther
-	CasbinEnforcer *casbin.SyncedEnforcer
-	LoginAPI       *api.LoginAPI
-	MenuAPI        *api.MenuAPI
-	RoleAPI        *api.RoleAPI
-	UserAPI        *api.UserAPI
+	Auth          auth.Auther
+	APIKeySrv     *service.APIKeySrv
+	SuperAdminSrv *service.SuperAdminSrv
+	TenantSrv     *service.TenantSrv
+	PermissionSrv *service.PermissionSrv
+	DataScopeSrv  *service.DataScopeSrv
+	LoginAPI      *api.LoginAPI
+	MenuAPI       *api.MenuAPI
+	DeptAPI       *api.DeptAPI
+	RoleAPI       *api.RoleAPI
+	UserAPI       *api.UserAPI
+	PermissionAPI *api.PermissionAPI
+	TenantAPI     *api.TenantAPI
 } // end
 
 func (a *Router) Register(app *gin.Engine) error {
 	a.RegisterAPI(app)
+	app.GET("/.well-known/jwks.json", a.LoginAPI.GetJWKS)
 	return nil
 }
 
@@ -39,15 +56,46 @@ func (a *Router) Prefixes() []string {
 	}
 }
 
+// PermissionRoutes 需要权限校验的接口路由
+func (a *Router) PermissionRoutes(app *gin.Engine) schema.Routes {
+	var list schema.Routes
+	for _, route := range app.Routes() {
+		if !hasAnyPrefix(route.Path, a.Prefixes()...) || hasAnyPrefix(route.Path, permissionSkipPrefixes...) {
+			continue
+		}
+		list = append(list, &schema.Route{Method: route.Method, Path: route.Path})
+	}
+	return list
+}
+
+func hasAnyPrefix(path string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // RegisterAPI register api group router
 func (a *Router) RegisterAPI(app *gin.Engine) {
 	g := app.Group("/api")
 
-	g.Use(middleware.UserAuthMiddleware(a.Auth,
-		middleware.AllowPathPrefixSkipper("/api/v1/pub/login"),
+	g.Use(middleware.UserAuthMiddleware(a.Auth, a.APIKeySrv, a.SuperAdminSrv,
+		middleware.AllowPathPrefixSkipper("/api/v1/pub/login", "/api/v1/pub/refresh-token"),
 	))
 
-	g.Use(middleware.CasbinMiddleware(a.CasbinEnforcer,
+	g.Use(middleware.TenantMiddleware(a.TenantSrv))
+
+	g.Use(middleware.PasswordChangeMiddleware(
+		middleware.AllowPathPrefixSkipper("/api/v1/pub/current/password", "/api/v1/pub/current/user", "/api/v1/pub/login/exit"),
+	))
+
+	g.Use(middleware.CasbinMiddleware(a.PermissionSrv,
+		middleware.AllowPathPrefixSkipper(permissionSkipPrefixes...),
+	))
+
+	g.Use(middleware.DataScopeMiddleware(a.DataScopeSrv,
 		middleware.AllowPathPrefixSkipper("/api/v1/pub"),
 	))
 
@@ -62,6 +110,9 @@ func (a *Router) RegisterAPI(app *gin.Engine) {
 				gLogin.GET("captchaid", a.LoginAPI.GetCaptcha)
 				gLogin.GET("captcha", a.LoginAPI.ResCaptcha)
 				gLogin.POST("", a.LoginAPI.Login)
+				gLogin.POST("mfa", a.LoginAPI.LoginMFA)
+				gLogin.GET("oidc", a.LoginAPI.GetOIDCURL)
+				gLogin.POST("oidc", a.LoginAPI.LoginOIDC)
 				gLogin.POST("exit", a.LoginAPI.Logout)
 			}
 
@@ -70,6 +121,16 @@ func (a *Router) RegisterAPI(app *gin.Engine) {
 				gCurrent.PUT("password", a.LoginAPI.UpdatePassword)
 				gCurrent.GET("user", a.LoginAPI.GetUserInfo)
 				gCurrent.GET("menutree", a.LoginAPI.QueryUserMenuTree)
+				gCurrent.POST("permissions", a.LoginAPI.CheckPermissions)
+				gCurrent.GET("sessions", a.LoginAPI.QuerySessions)
+				gCurrent.DELETE("sessions/:sid", a.LoginAPI.RevokeSession)
+				gCurrent.DELETE("sessions", a.LoginAPI.RevokeAllSessions)
+				gCurrent.POST("mfa", a.LoginAPI.EnrollMFA)
+				gCurrent.GET("mfa/qrcode", a.LoginAPI.ResMFAQRCode)
+				gCurrent.PUT("mfa", a.LoginAPI.ActivateMFA)
+				gCurrent.GET("apikeys", a.LoginAPI.QueryAPIKeys)
+				gCurrent.POST("apikeys", a.LoginAPI.CreateAPIKey)
+				gCurrent.DELETE("apikeys/:kid", a.LoginAPI.DeleteAPIKey)
 			}
 			pub.POST("/refresh-token", a.LoginAPI.RefreshToken)
 		}
@@ -83,8 +144,25 @@ func (a *Router) RegisterAPI(app *gin.Engine) {
 			gMenu.DELETE(":id", a.MenuAPI.Delete)
 			gMenu.PATCH(":id/enable", a.MenuAPI.Enable)
 			gMenu.PATCH(":id/disable", a.MenuAPI.Disable)
+			gMenu.PUT(":id/move", a.MenuAPI.Move)
+			gMenu.GET(":id/impact", a.MenuAPI.DeleteImpact)
 		}
 		v1.GET("/menus.tree", a.MenuAPI.QueryTree)
+		v1.PUT("/menus.sort", a.MenuAPI.Sort)
+		v1.GET("/menus.export", a.MenuAPI.Export)
+
+		gDept := v1.Group("depts")
+		{
+			gDept.GET("", a.DeptAPI.Query)
+			gDept.GET(":id", a.DeptAPI.Get)
+			gDept.POST("", a.DeptAPI.Create)
+			gDept.PUT(":id", a.DeptAPI.Update)
+			gDept.DELETE(":id", a.DeptAPI.Delete)
+			gDept.PATCH(":id/enable", a.DeptAPI.Enable)
+			gDept.PATCH(":id/disable", a.DeptAPI.Disable)
+			gDept.PUT(":id/move", a.DeptAPI.Move)
+		}
+		v1.GET("/depts.tree", a.DeptAPI.QueryTree)
 
 		gRole := v1.Group("roles")
 		{
@@ -107,6 +185,26 @@ func (a *Router) RegisterAPI(app *gin.Engine) {
 			gUser.DELETE(":id", a.UserAPI.Delete)
 			gUser.PATCH(":id/enable", a.UserAPI.Enable)
 			gUser.PATCH(":id/disable", a.UserAPI.Disable)
+			gUser.PATCH(":id/unlock", a.UserAPI.Unlock)
+			gUser.DELETE(":id/mfa", a.UserAPI.ResetMFA)
+			gUser.GET(":id/apikeys", a.UserAPI.QueryAPIKeys)
+			gUser.DELETE(":id/apikeys/:kid", a.UserAPI.DeleteAPIKey)
+			gUser.GET(":id/sessions", a.UserAPI.QuerySessions)
+			gUser.DELETE(":id/sessions/:sid", a.UserAPI.RevokeSession)
+			gUser.DELETE(":id/sessions", a.UserAPI.RevokeAllSessions)
+		}
+
+		v1.GET("/permissions.explain", a.PermissionAPI.Explain)
+
+		gTenant := v1.Group("tenants")
+		{
+			gTenant.GET("", a.TenantAPI.Query)
+			gTenant.GET(":id", a.TenantAPI.Get)
+			gTenant.POST("", a.TenantAPI.Create)
+			gTenant.PUT(":id", a.TenantAPI.Update)
+			gTenant.DELETE(":id", a.TenantAPI.Delete)
+			gTenant.PATCH(":id/enable", a.TenantAPI.Enable)
+			gTenant.PATCH(":id/disable", a.TenantAPI.Disable)
 		}
 	} // v1 end
 }
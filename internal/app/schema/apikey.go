@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"time"
+)
+
+// UserAPIKey 用户API密钥
+type UserAPIKey struct {
+	ID         uint64          `json:"id,string"`      // 唯一标识
+	UserID     uint64          `json:"user_id,string"` // 所属用户ID
+	Name       string          `json:"name"`           // 名称
+	Prefix     string          `json:"prefix"`         // 密钥前缀(用于识别)
+	KeyHash    string          `json:"-"`              // 密钥哈希
+	ExpiresAt  *time.Time      `json:"expires_at"`     // 过期时间(为空表示不过期)
+	LastUsedAt *time.Time      `json:"last_used_at"`   // 最近使用时间
+	CreatedAt  time.Time       `json:"created_at"`     // 创建时间
+	KeyRoles   UserAPIKeyRoles `json:"key_roles"`      // 限定的角色(为空表示与所属用户的角色一致)
+}
+
+// IsExpired 检查是否已过期
+func (a *UserAPIKey) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(now)
+}
+
+// UserAPIKeyQueryParam 查询条件
+type UserAPIKeyQueryParam struct {
+	PaginationParam
+	UserID uint64 // 用户ID
+}
+
+// UserAPIKeyQueryOptions 查询可选参数项
+type UserAPIKeyQueryOptions struct {
+	OrderFields []*OrderField // 排序字段
+}
+
+// UserAPIKeyQueryResult 查询结果
+type UserAPIKeyQueryResult struct {
+	Data       UserAPIKeys
+	PageResult *PaginationResult
+}
+
+// UserAPIKeys API密钥列表
+type UserAPIKeys []*UserAPIKey
+
+// ToIDs 转换为唯一标识列表
+func (a UserAPIKeys) ToIDs() []uint64 {
+	idList := make([]uint64, len(a))
+	for i, item := range a {
+		idList[i] = item.ID
+	}
+	return idList
+}
+
+// FillKeyRoles 填充限定的角色
+func (a UserAPIKeys) FillKeyRoles(mKeyRoles map[uint64]UserAPIKeyRoles) UserAPIKeys {
+	for _, item := range a {
+		item.KeyRoles = mKeyRoles[item.ID]
+	}
+	return a
+}
+
+// UserAPIKeyCreateParam 创建API密钥参数
+type UserAPIKeyCreateParam struct {
+	Name      string          `json:"name" binding:"required,max=64"` // 名称
+	ExpiresAt *time.Time      `json:"expires_at"`                     // 过期时间(为空表示不过期)
+	KeyRoles  UserAPIKeyRoles `json:"key_roles"`                      // 限定的角色(必须是用户已授权的角色)
+}
+
+// UserAPIKeyCreateResult 创建API密钥结果(密钥仅在创建时返回一次)
+type UserAPIKeyCreateResult struct {
+	ID  uint64 `json:"id,string"` // 唯一标识
+	Key string `json:"key"`       // 密钥
+}
+
+// ----------------------------------------UserAPIKeyRole--------------------------------------
+
+// UserAPIKeyRole API密钥限定的角色
+type UserAPIKeyRole struct {
+	ID       uint64 `json:"id,string"`         // 唯一标识
+	APIKeyID uint64 `json:"api_key_id,string"` // API密钥ID
+	RoleID   uint64 `json:"role_id,string"`    // 角色ID
+}
+
+// UserAPIKeyRoleQueryParam 查询条件
+type UserAPIKeyRoleQueryParam struct {
+	PaginationParam
+	APIKeyID  uint64   // API密钥ID
+	APIKeyIDs []uint64 // API密钥ID列表
+}
+
+// UserAPIKeyRoleQueryOptions 查询可选参数项
+type UserAPIKeyRoleQueryOptions struct {
+	OrderFields []*OrderField // 排序字段
+}
+
+// UserAPIKeyRoleQueryResult 查询结果
+type UserAPIKeyRoleQueryResult struct {
+	Data       UserAPIKeyRoles
+	PageResult *PaginationResult
+}
+
+// UserAPIKeyRoles API密钥限定的角色列表
+type UserAPIKeyRoles []*UserAPIKeyRole
+
+// ToRoleIDs 转换为角色ID列表
+func (a UserAPIKeyRoles) ToRoleIDs() []uint64 {
+	list := make([]uint64, len(a))
+	for i, item := range a {
+		list[i] = item.RoleID
+	}
+	return list
+}
+
+// ToAPIKeyIDMap 转换为API密钥ID映射
+func (a UserAPIKeyRoles) ToAPIKeyIDMap() map[uint64]UserAPIKeyRoles {
+	m := make(map[uint64]UserAPIKeyRoles)
+	for _, item := range a {
+		m[item.APIKeyID] = append(m[item.APIKeyID], item)
+	}
+	return m
+}
+
+// APIKeyIdentity API密钥认证通过后的身份信息
+type APIKeyIdentity struct {
+	APIKeyID   uint64   // API密钥ID
+	UserID     uint64   // 所属用户ID
+	UserName   string   // 所属用户名
+	TenantID   uint64   // 所属用户的租户
+	Restricted bool     // 是否限定了角色
+	RoleIDs    []string // 限定且所属用户仍拥有的角色ID
+}
@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/util/json"
+)
+
+// Dept 部门对象
+type Dept struct {
+	ID         uint64    `json:"id,string"`                             // 唯一标识
+	Name       string    `json:"name" binding:"required"`               // 部门名称
+	Sequence   int       `json:"sequence"`                              // 排序值
+	ParentID   uint64    `json:"parent_id,string"`                      // 父级ID
+	ParentPath string    `json:"parent_path"`                           // 父级路径
+	Leader     string    `json:"leader"`                                // 负责人
+	Phone      string    `json:"phone"`                                 // 联系电话
+	Email      string    `json:"email"`                                 // 邮箱
+	Status     int       `json:"status" binding:"required,max=2,min=1"` // 状态(1:启用 2:禁用)
+	Memo       string    `json:"memo"`                                  // 备注
+	Creator    uint64    `json:"creator"`                               // 创建者
+	CreatedAt  time.Time `json:"created_at"`                            // 创建时间
+	UpdatedAt  time.Time `json:"updated_at"`                            // 更新时间
+}
+
+func (a *Dept) String() string {
+	return json.MarshalToString(a)
+}
+
+// DeptQueryParam 查询条件
+type DeptQueryParam struct {
+	PaginationParam
+	IDs              []uint64 `form:"-"`          // 唯一标识列表
+	Name             string   `form:"-"`          // 部门名称
+	PrefixParentPath string   `form:"-"`          // 父级路径(前缀模糊查询)
+	QueryValue       string   `form:"queryValue"` // 模糊查询
+	ParentID         *uint64  `form:"parentID"`   // 父级内码
+	Status           int      `form:"status"`     // 状态(1:启用 2:禁用)
+}
+
+// DeptQueryOptions 查询可选参数项
+type DeptQueryOptions struct {
+	OrderFields  []*OrderField
+	SelectFields []string
+}
+
+// DeptQueryResult 查询结果
+type DeptQueryResult struct {
+	Data       Depts
+	PageResult *PaginationResult
+}
+
+// DeptMoveParam 移动部门参数
+type DeptMoveParam struct {
+	ParentID uint64 `json:"parent_id,string"` // 新的父级ID(为0时移动到顶级)
+	Sequence *int   `json:"sequence"`         // 排序值(为空时不修改)
+}
+
+// Depts 部门列表
+type Depts []*Dept
+
+// ToMap 转换为键值映射
+func (a Depts) ToMap() map[uint64]*Dept {
+	m := make(map[uint64]*Dept)
+	for _, item := range a {
+		m[item.ID] = item
+	}
+	return m
+}
+
+// ToIDs 转换为唯一标识列表
+func (a Depts) ToIDs() []uint64 {
+	idList := make([]uint64, len(a))
+	for i, item := range a {
+		idList[i] = item.ID
+	}
+	return idList
+}
+
+// ToTree 转换为部门树
+func (a Depts) ToTree() DeptTrees {
+	list := make(DeptTrees, len(a))
+	for i, item := range a {
+		list[i] = &DeptTree{
+			ID:         item.ID,
+			Name:       item.Name,
+			ParentID:   item.ParentID,
+			ParentPath: item.ParentPath,
+			Sequence:   item.Sequence,
+			Leader:     item.Leader,
+			Status:     item.Status,
+		}
+	}
+	return list.ToTree()
+}
+
+// ----------------------------------------DeptTree--------------------------------------
+
+// DeptTree 部门树
+type DeptTree struct {
+	ID         uint64     `json:"id,string"`          // 唯一标识
+	Name       string     `json:"name"`               // 部门名称
+	ParentID   uint64     `json:"parent_id,string"`   // 父级ID
+	ParentPath string     `json:"parent_path"`        // 父级路径
+	Sequence   int        `json:"sequence"`           // 排序值
+	Leader     string     `json:"leader"`             // 负责人
+	Status     int        `json:"status"`             // 状态(1:启用 2:禁用)
+	Children   *DeptTrees `json:"children,omitempty"` // 子级树
+}
+
+// DeptTrees 部门树列表
+type DeptTrees []*DeptTree
+
+// ToTree 转换为树形结构
+func (a DeptTrees) ToTree() DeptTrees {
+	mi := make(map[uint64]*DeptTree)
+	for _, item := range a {
+		mi[item.ID] = item
+	}
+
+	var list DeptTrees
+	for _, item := range a {
+		if item.ParentID == 0 {
+			list = append(list, item)
+			continue
+		}
+		if pitem, ok := mi[item.ParentID]; ok {
+			if pitem.Children == nil {
+				children := DeptTrees{item}
+				pitem.Children = &children
+				continue
+			}
+			*pitem.Children = append(*pitem.Children, item)
+		}
+	}
+	return list
+}
@@ -2,21 +2,22 @@ package schema
 
 type LoginParam struct {
 	UserName    string `json:"user_name" binding:"required"`    // 用户名
-	Password    string `json:"password" binding:"required"`     // 密码(md5加密)
+	Password    string `json:"password" binding:"required"`     // 密码(明文)
 	CaptchaID   string `json:"captcha_id" binding:"required"`   // 验证码ID
 	CaptchaCode string `json:"captcha_code" binding:"required"` // 验证码
 }
 
 type UserLoginInfo struct {
-	UserID   uint64 `json:"user_id,string"` // 用户ID
-	UserName string `json:"user_name"`      // 用户名
-	RealName string `json:"real_name"`      // 真实姓名
-	Roles    Roles  `json:"roles"`          // 角色列表
+	UserID       uint64 `json:"user_id,string"` // 用户ID
+	UserName     string `json:"user_name"`      // 用户名
+	RealName     string `json:"real_name"`      // 真实姓名
+	IsSuperAdmin bool   `json:"is_super_admin"` // 是否超级管理员
+	Roles        Roles  `json:"roles"`          // 角色列表
 }
 
 type UpdatePasswordParam struct {
-	OldPassword string `json:"old_password" binding:"required"` // 旧密码(md5加密)
-	NewPassword string `json:"new_password" binding:"required"` // 新密码(md5加密)
+	OldPassword string `json:"old_password" binding:"required"` // 旧密码(明文)
+	NewPassword string `json:"new_password" binding:"required"` // 新密码(明文，需符合密码策略)
 }
 
 type LoginCaptcha struct {
@@ -24,7 +25,36 @@ type LoginCaptcha struct {
 }
 
 type LoginTokenInfo struct {
-	AccessToken string `json:"access_token"` // 访问令牌
-	TokenType   string `json:"token_type"`   // 令牌类型
-	ExpiresAt   int64  `json:"expires_at"`   // 过期时间戳
+	AccessToken        string `json:"access_token"`                   // 访问令牌
+	TokenType          string `json:"token_type"`                     // 令牌类型
+	ExpiresAt          int64  `json:"expires_at"`                     // 过期时间戳
+	RefreshToken       string `json:"refresh_token,omitempty"`        // 刷新令牌
+	RefreshExpiresAt   int64  `json:"refresh_expires_at,omitempty"`   // 刷新令牌过期时间戳
+	MustChangePassword bool   `json:"must_change_password,omitempty"` // 必须修改密码(令牌仅允许修改密码)
+
+	MFAChallenge *LoginMFAChallenge `json:"mfa_challenge,omitempty"` // 两步验证挑战(存在时不签发令牌，需继续验证动态口令)
+}
+
+type LoginMFAChallenge struct {
+	ChallengeToken string `json:"challenge_token"` // 挑战令牌
+	ExpiresAt      int64  `json:"expires_at"`      // 过期时间戳
+}
+
+type LoginMFAParam struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"` // 挑战令牌
+	Code           string `json:"code" binding:"required"`            // 动态口令或恢复码
+}
+
+type LoginOIDCURL struct {
+	URL   string `json:"url"`   // 跳转到身份提供方的授权地址
+	State string `json:"state"` // 状态(回调时原样返回)
+}
+
+type LoginOIDCParam struct {
+	Code  string `json:"code" binding:"required"`  // 授权码
+	State string `json:"state" binding:"required"` // 状态
+}
+
+type RefreshTokenParam struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // 刷新令牌
 }
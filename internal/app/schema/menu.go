@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 // Menu 菜单对象
 type Menu struct {
 	ID         uint64      `json:"id,string"`                              // 唯一标识
+	Code       string      `json:"code"`                                   // 菜单编号(唯一，用于同步菜单数据)
 	Name       string      `json:"name" binding:"required"`                // 菜单名称
 	Sequence   int         `json:"sequence"`                               // 排序值
 	Icon       string      `json:"icon"`                                   // 菜单图标
@@ -34,8 +36,10 @@ func (a *Menu) String() string {
 type MenuQueryParam struct {
 	PaginationParam
 	IDs              []uint64 `form:"-"`          // 唯一标识列表
+	Code             string   `form:"-"`          // 菜单编号
 	Name             string   `form:"-"`          // 菜单名称
 	PrefixParentPath string   `form:"-"`          // 父级路径(前缀模糊查询)
+	Routers          []string `form:"-"`          // 访问路由列表
 	QueryValue       string   `form:"queryValue"` // 模糊查询
 	ParentID         *uint64  `form:"parentID"`   // 父级内码
 	IsShow           int      `form:"isShow"`     // 是否显示(1:显示 2:隐藏)
@@ -54,6 +58,33 @@ type MenuQueryResult struct {
 	PageResult *PaginationResult
 }
 
+// MenuMoveParam 移动菜单参数
+type MenuMoveParam struct {
+	ParentID uint64 `json:"parent_id,string"` // 新的父级ID(为0时移动到顶级)
+	Sequence *int   `json:"sequence"`         // 排序值(为空时不修改)
+}
+
+// MenuDeleteImpactParam 删除菜单影响范围的查询参数(分页查询失去访问权限的用户)
+type MenuDeleteImpactParam struct {
+	PaginationParam
+}
+
+// MenuDeleteImpact 删除菜单(包含下级菜单)的影响范围
+type MenuDeleteImpact struct {
+	Menus          Menus             `json:"menus"`           // 删除的菜单(包含下级菜单)
+	Actions        MenuActions       `json:"actions"`         // 删除的动作
+	Roles          Roles             `json:"roles"`           // 移除授权的角色(包含所有租户)
+	Users          Users             `json:"users"`           // 失去访问权限的用户(直接或通过继承拥有以上角色，分页返回)
+	UserPagination *PaginationResult `json:"user_pagination"` // 用户的分页信息(total为失去访问权限的用户总数)
+}
+
+// MenuSortItem 菜单排序项(拖拽排序后的父级及排序值)
+type MenuSortItem struct {
+	ID       uint64 `json:"id,string"`        // 唯一标识
+	ParentID uint64 `json:"parent_id,string"` // 父级ID(为0时为顶级)
+	Sequence int    `json:"sequence"`         // 排序值
+}
+
 // Menus 菜单列表
 type Menus []*Menu
 
@@ -107,6 +138,7 @@ func (a Menus) ToTree() MenuTrees {
 	for i, item := range a {
 		list[i] = &MenuTree{
 			ID:         item.ID,
+			Code:       item.Code,
 			Name:       item.Name,
 			Icon:       item.Icon,
 			Router:     item.Router,
@@ -136,14 +168,15 @@ func (a Menus) FillMenuAction(mActions map[uint64]MenuActions) Menus {
 // MenuTree 菜单树
 type MenuTree struct {
 	ID         uint64      `yaml:"-" json:"id,string"`                           // 唯一标识
+	Code       string      `yaml:"code,omitempty" json:"code"`                   // 菜单编号
 	Name       string      `yaml:"name" json:"name"`                             // 菜单名称
 	Icon       string      `yaml:"icon" json:"icon"`                             // 菜单图标
 	Router     string      `yaml:"router,omitempty" json:"router"`               // 访问路由
 	ParentID   uint64      `yaml:"-" json:"parent_id,string"`                    // 父级ID
 	ParentPath string      `yaml:"-" json:"parent_path"`                         // 父级路径
 	Sequence   int         `yaml:"sequence" json:"sequence"`                     // 排序值
-	IsShow     int         `yaml:"-" json:"is_show"`                             // 是否显示(1:显示 2:隐藏)
-	Status     int         `yaml:"-" json:"status"`                              // 状态(1:启用 2:禁用)
+	IsShow     int         `yaml:"is_show,omitempty" json:"is_show"`             // 是否显示(1:显示 2:隐藏)
+	Status     int         `yaml:"status,omitempty" json:"status"`               // 状态(1:启用 2:禁用)
 	Actions    MenuActions `yaml:"actions,omitempty" json:"actions"`             // 动作列表
 	Children   *MenuTrees  `yaml:"children,omitempty" json:"children,omitempty"` // 子级树
 }
@@ -176,6 +209,48 @@ func (a MenuTrees) ToTree() MenuTrees {
 	return list
 }
 
+// ----------------------------------------MenuSync--------------------------------------
+
+// MenuSyncOptions 菜单数据同步选项
+type MenuSyncOptions struct {
+	Prune  bool // 删除数据文件中不存在的菜单、动作及资源
+	DryRun bool // 仅比较差异，不修改数据
+}
+
+// 菜单数据同步的变更操作
+const (
+	MenuSyncAdd    = "add"
+	MenuSyncUpdate = "update"
+	MenuSyncDelete = "delete"
+)
+
+// MenuSyncChange 菜单数据同步的变更项
+type MenuSyncChange struct {
+	Op     string `json:"op"`               // 变更操作(add/update/delete)
+	Kind   string `json:"kind"`             // 变更对象(menu/action/resource)
+	Menu   string `json:"menu"`             // 菜单(编号、路由或名称)
+	Action string `json:"action,omitempty"` // 动作编号
+	Detail string `json:"detail,omitempty"` // 变更内容
+}
+
+func (a *MenuSyncChange) String() string {
+	op := map[string]string{MenuSyncAdd: "+", MenuSyncUpdate: "~", MenuSyncDelete: "-"}[a.Op]
+	s := fmt.Sprintf("%s %s [%s]", op, a.Kind, a.Menu)
+	if a.Action != "" {
+		s += " " + a.Action
+	}
+	if a.Detail != "" {
+		s += ": " + a.Detail
+	}
+	return s
+}
+
+// MenuSyncResult 菜单数据同步结果
+type MenuSyncResult struct {
+	DryRun  bool              `json:"dry_run"` // 是否仅比较差异
+	Changes []*MenuSyncChange `json:"changes"` // 变更列表
+}
+
 // ----------------------------------------MenuAction--------------------------------------
 
 // MenuAction 菜单动作对象
@@ -190,8 +265,9 @@ type MenuAction struct {
 // MenuActionQueryParam 查询条件
 type MenuActionQueryParam struct {
 	PaginationParam
-	MenuID uint64   // 菜单ID
-	IDs    []uint64 // 唯一标识列表
+	MenuID  uint64   // 菜单ID
+	MenuIDs []uint64 // 菜单ID列表
+	IDs     []uint64 // 唯一标识列表
 }
 
 // MenuActionQueryOptions 查询可选参数项
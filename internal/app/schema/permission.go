@@ -0,0 +1,106 @@
+package schema
+
+// PermissionExplainParam 权限诊断参数(用户ID与角色ID二选一)
+type PermissionExplainParam struct {
+	UserID uint64 `form:"userID"`                    // 用户ID
+	RoleID uint64 `form:"roleID"`                    // 角色ID
+	Method string `form:"method" binding:"required"` // 请求方式
+	Path   string `form:"path" binding:"required"`   // 请求路径
+}
+
+// PermissionExplain 权限诊断结果
+type PermissionExplain struct {
+	Allowed       bool                       `json:"allowed"`        // 是否允许访问
+	Reason        string                     `json:"reason"`         // 判定说明
+	Subject       string                     `json:"subject"`        // 校验的主体(用户ID或角色ID)
+	MatchedPolicy []string                   `json:"matched_policy"` // 命中的策略(p,sub,dom,obj,act)
+	Roles         []*PermissionExplainRole   `json:"roles"`          // 参与校验的角色(包含继承的上级角色)
+	Policies      []*PermissionExplainPolicy `json:"policies"`       // 命中或部分命中(仅路径或请求方式匹配)的策略
+}
+
+// PermissionExplainRole 参与校验的角色
+type PermissionExplainRole struct {
+	ID            uint64 `json:"id,string"`             // 角色ID
+	Name          string `json:"name"`                  // 角色名称
+	Status        int    `json:"status"`                // 状态(1:启用 2:禁用)
+	InheritedFrom uint64 `json:"inherited_from,string"` // 继承自的下级角色ID(为0时表示直接分配的角色)
+	Effective     bool   `json:"effective"`             // 是否已生效(禁用的角色及仅通过禁用角色继承的上级角色不生效)
+}
+
+// PermissionExplainPolicy 角色的策略
+type PermissionExplainPolicy struct {
+	RoleID        uint64                     `json:"role_id,string"` // 角色ID
+	Path          string                     `json:"path"`           // 策略路径
+	Method        string                     `json:"method"`         // 策略请求方式
+	PathMatched   bool                       `json:"path_matched"`   // 路径是否匹配(keyMatch2)
+	MethodMatched bool                       `json:"method_matched"` // 请求方式是否匹配(regexMatch)
+	Sources       []*PermissionExplainSource `json:"sources"`        // 产生该策略的菜单动作及资源
+}
+
+// PermissionExplainSource 策略来源(菜单动作关联资源)
+type PermissionExplainSource struct {
+	MenuID     uint64 `json:"menu_id,string"`     // 菜单ID
+	MenuName   string `json:"menu_name"`          // 菜单名称
+	ActionID   uint64 `json:"action_id,string"`   // 动作ID
+	ActionCode string `json:"action_code"`        // 动作编号
+	ActionName string `json:"action_name"`        // 动作名称
+	ResourceID uint64 `json:"resource_id,string"` // 资源ID
+}
+
+// PermissionCheckItem 权限校验项(菜单路由及动作编号，或请求方式及请求路径)
+type PermissionCheckItem struct {
+	Router string `json:"router"` // 菜单路由
+	Code   string `json:"code"`   // 动作编号
+	Method string `json:"method"` // 请求方式
+	Path   string `json:"path"`   // 请求路径
+}
+
+// PermissionCheckParam 批量权限校验参数
+type PermissionCheckParam struct {
+	Items []*PermissionCheckItem `json:"items" binding:"required,max=100"` // 校验项列表(最多100项)
+}
+
+// PermissionCheckResult 权限校验结果
+type PermissionCheckResult struct {
+	PermissionCheckItem
+	Allowed bool `json:"allowed"` // 是否允许访问
+}
+
+// Route 接口路由
+type Route struct {
+	Method string `json:"method"` // 请求方式
+	Path   string `json:"path"`   // 请求路径
+}
+
+// Routes 接口路由列表
+type Routes []*Route
+
+// RouteCheckResult 接口路由与菜单动作资源的检查结果
+type RouteCheckResult struct {
+	UnprotectedRoutes Routes                `json:"unprotected_routes"` // 没有资源匹配的路由(不能授权给任何角色，仅超级管理员可以访问)
+	StaleResources    []*RouteCheckResource `json:"stale_resources"`    // 不匹配任何路由的资源
+	OverlappingRoutes []*RouteCheckOverlap  `json:"overlapping_routes"` // 被多个不同的资源匹配的路由
+}
+
+// HasProblems 是否存在问题
+func (a *RouteCheckResult) HasProblems() bool {
+	return len(a.UnprotectedRoutes) > 0 || len(a.StaleResources) > 0 || len(a.OverlappingRoutes) > 0
+}
+
+// RouteCheckResource 菜单动作资源
+type RouteCheckResource struct {
+	MenuID     uint64 `json:"menu_id,string"`     // 菜单ID
+	MenuName   string `json:"menu_name"`          // 菜单名称
+	ActionID   uint64 `json:"action_id,string"`   // 动作ID
+	ActionCode string `json:"action_code"`        // 动作编号
+	ResourceID uint64 `json:"resource_id,string"` // 资源ID
+	Method     string `json:"method"`             // 资源请求方式
+	Path       string `json:"path"`               // 资源请求路径
+}
+
+// RouteCheckOverlap 被多个资源匹配的路由
+type RouteCheckOverlap struct {
+	Method   string `json:"method"`   // 路由请求方式
+	Path     string `json:"path"`     // 路由请求路径
+	Patterns Routes `json:"patterns"` // 匹配的资源(请求方式及路径)
+}
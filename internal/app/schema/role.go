@@ -5,17 +5,29 @@ import (
 	"time"
 )
 
+// 角色的数据范围
+const (
+	DataScopeAll    = 1 // 全部数据
+	DataScopeSelf   = 2 // 仅本人创建的数据
+	DataScopeCustom = 3 // 本人及指定用户创建的数据
+	DataScopeDept   = 4 // 本部门及下级部门用户创建的数据
+)
+
 // Role 角色对象
 type Role struct {
-	ID        uint64    `json:"id,string"`                             // 唯一标识
-	Name      string    `json:"name" binding:"required"`               // 角色名称
-	Sequence  int       `json:"sequence"`                              // 排序值
-	Memo      string    `json:"memo"`                                  // 备注
-	Status    int       `json:"status" binding:"required,max=2,min=1"` // 状态(1:启用 2:禁用)
-	Creator   uint64    `json:"creator"`                               // 创建者
-	CreatedAt time.Time `json:"created_at"`                            // 创建时间
-	UpdatedAt time.Time `json:"updated_at"`                            // 更新时间
-	RoleMenus RoleMenus `json:"role_menus" binding:"required,gt=0"`    // 角色菜单列表
+	ID            uint64        `json:"id,string"`                                  // 唯一标识
+	Name          string        `json:"name" binding:"required"`                    // 角色名称
+	Sequence      int           `json:"sequence"`                                   // 排序值
+	Memo          string        `json:"memo"`                                       // 备注
+	Status        int           `json:"status" binding:"required,max=2,min=1"`      // 状态(1:启用 2:禁用)
+	DataScope     int           `json:"data_scope" binding:"omitempty,max=4,min=1"` // 数据范围(1:全部 2:仅本人 3:自定义 4:本部门及以下)，默认全部
+	Creator       uint64        `json:"creator"`                                    // 创建者
+	CreatedAt     time.Time     `json:"created_at"`                                 // 创建时间
+	UpdatedAt     time.Time     `json:"updated_at"`                                 // 更新时间
+	RoleMenus     RoleMenus     `json:"role_menus" binding:"required,gt=0"`         // 角色菜单列表
+	RoleDataUsers RoleDataUsers `json:"role_data_users"`                            // 自定义数据范围的用户列表
+	RoleParents   RoleParents   `json:"role_parents"`                               // 继承的上级角色列表
+	TenantID      uint64        `json:"-"`                                          // 所属租户(由请求的租户决定)
 }
 
 // RoleQueryParam 查询条件
@@ -51,6 +63,15 @@ func (a Roles) ToNames() []string {
 	return names
 }
 
+// ToIDs 获取角色ID列表
+func (a Roles) ToIDs() []uint64 {
+	idList := make([]uint64, len(a))
+	for i, item := range a {
+		idList[i] = item.ID
+	}
+	return idList
+}
+
 // ToMap 转换为键值存储
 func (a Roles) ToMap() map[uint64]*Role {
 	m := make(map[uint64]*Role)
@@ -75,6 +96,7 @@ type RoleMenuQueryParam struct {
 	PaginationParam
 	RoleID  uint64   // 角色ID
 	RoleIDs []uint64 // 角色ID列表
+	MenuIDs []uint64 // 菜单ID列表
 }
 
 // RoleMenuQueryOptions 查询可选参数项
@@ -139,3 +161,102 @@ func (a RoleMenus) ToActionIDs() []uint64 {
 	}
 	return idList
 }
+
+// ----------------------------------------RoleDataUser--------------------------------------
+
+// RoleDataUser 角色自定义数据范围的用户对象
+type RoleDataUser struct {
+	ID     uint64 `json:"id,string"`                         // 唯一标识
+	RoleID uint64 `json:"role_id,string"`                    // 角色ID
+	UserID uint64 `json:"user_id,string" binding:"required"` // 用户ID
+}
+
+// RoleDataUserQueryParam 查询条件
+type RoleDataUserQueryParam struct {
+	PaginationParam
+	RoleID  uint64   // 角色ID
+	RoleIDs []uint64 // 角色ID列表
+}
+
+// RoleDataUserQueryOptions 查询可选参数项
+type RoleDataUserQueryOptions struct {
+	OrderFields  []*OrderField
+	SelectFields []string
+}
+
+// RoleDataUserQueryResult 查询结果
+type RoleDataUserQueryResult struct {
+	Data       RoleDataUsers
+	PageResult *PaginationResult
+}
+
+// RoleDataUsers 角色自定义数据范围的用户列表
+type RoleDataUsers []*RoleDataUser
+
+// ToMap 转换为map(键为用户ID)
+func (a RoleDataUsers) ToMap() map[uint64]*RoleDataUser {
+	m := make(map[uint64]*RoleDataUser)
+	for _, item := range a {
+		m[item.UserID] = item
+	}
+	return m
+}
+
+// ToUserIDs 转换为用户ID列表
+func (a RoleDataUsers) ToUserIDs() []uint64 {
+	idList := make([]uint64, len(a))
+	for i, item := range a {
+		idList[i] = item.UserID
+	}
+	return idList
+}
+
+// ----------------------------------------RoleParent--------------------------------------
+
+// RoleParent 角色继承的上级角色(继承上级角色的菜单权限)
+type RoleParent struct {
+	ID       uint64 `json:"id,string"`                           // 唯一标识
+	RoleID   uint64 `json:"role_id,string"`                      // 角色ID
+	ParentID uint64 `json:"parent_id,string" binding:"required"` // 上级角色ID
+}
+
+// RoleParentQueryParam 查询条件
+type RoleParentQueryParam struct {
+	PaginationParam
+	RoleID    uint64   // 角色ID
+	RoleIDs   []uint64 // 角色ID列表
+	ParentIDs []uint64 // 上级角色ID列表
+}
+
+// RoleParentQueryOptions 查询可选参数项
+type RoleParentQueryOptions struct {
+	OrderFields  []*OrderField
+	SelectFields []string
+}
+
+// RoleParentQueryResult 查询结果
+type RoleParentQueryResult struct {
+	Data       RoleParents
+	PageResult *PaginationResult
+}
+
+// RoleParents 角色继承的上级角色列表
+type RoleParents []*RoleParent
+
+// ToMap 转换为map(键为上级角色ID)
+func (a RoleParents) ToMap() map[uint64]*RoleParent {
+	m := make(map[uint64]*RoleParent)
+	for _, item := range a {
+		m[item.ParentID] = item
+	}
+	return m
+}
+
+// ToParentIDs 转换为上级角色ID列表
+func (a RoleParents) ToParentIDs() []uint64 {
+	idList := make([]uint64, len(a))
+	for i, item := range a {
+		idList[i] = item.ParentID
+	}
+	return idList
+}
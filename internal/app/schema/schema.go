@@ -21,8 +21,9 @@ type ErrorResult struct {
 }
 
 type ErrorItem struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 type ListResult struct {
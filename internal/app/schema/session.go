@@ -0,0 +1,16 @@
+package schema
+
+import "time"
+
+// UserSession 用户登录会话
+type UserSession struct {
+	ID         string    `json:"id"`           // 会话ID
+	IP         string    `json:"ip"`           // 客户端IP
+	UserAgent  string    `json:"user_agent"`   // 客户端标识
+	CreatedAt  time.Time `json:"created_at"`   // 登录时间
+	LastSeenAt time.Time `json:"last_seen_at"` // 最近活动时间
+	ExpiresAt  time.Time `json:"expires_at"`   // 过期时间
+}
+
+// UserSessions 用户登录会话列表
+type UserSessions []*UserSession
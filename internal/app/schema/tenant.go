@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/util/json"
+)
+
+// PlatformTenantID 平台的租户ID(平台用户管理所有租户)
+const PlatformTenantID uint64 = 0
+
+// TenantDomain 租户对应的casbin域
+func TenantDomain(tenantID uint64) string {
+	return strconv.FormatUint(tenantID, 10)
+}
+
+// Tenant 租户对象
+type Tenant struct {
+	ID            uint64    `json:"id,string"`                             // 唯一标识
+	Code          string    `json:"code" binding:"required"`               // 租户编号(用于子域名及请求头)
+	Name          string    `json:"name" binding:"required"`               // 租户名称
+	Status        int       `json:"status" binding:"required,max=2,min=1"` // 状态(1:启用 2:停用)
+	Memo          string    `json:"memo"`                                  // 备注
+	Creator       uint64    `json:"creator"`                               // 创建者
+	CreatedAt     time.Time `json:"created_at"`                            // 创建时间
+	UpdatedAt     time.Time `json:"updated_at"`                            // 更新时间
+	AdminUserName string    `json:"admin_user_name,omitempty"`             // 租户超级管理员用户名(仅创建时有效，为空时不创建)
+	AdminPassword string    `json:"admin_password,omitempty"`              // 租户超级管理员密码(明文，仅创建时有效)
+}
+
+func (a *Tenant) String() string {
+	return json.MarshalToString(a)
+}
+
+// CleanSecure 清理安全数据
+func (a *Tenant) CleanSecure() *Tenant {
+	a.AdminPassword = ""
+	return a
+}
+
+// TenantQueryParam 查询条件
+type TenantQueryParam struct {
+	PaginationParam
+	Code       string `form:"-"`          // 租户编号
+	QueryValue string `form:"queryValue"` // 模糊查询
+	Status     int    `form:"status"`     // 状态(1:启用 2:停用)
+}
+
+// TenantQueryOptions 查询可选参数项
+type TenantQueryOptions struct {
+	OrderFields []*OrderField
+}
+
+// TenantQueryResult 查询结果
+type TenantQueryResult struct {
+	Data       Tenants
+	PageResult *PaginationResult
+}
+
+// Tenants 租户列表
+type Tenants []*Tenant
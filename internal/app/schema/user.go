@@ -1,43 +1,37 @@
 package schema
 
 import (
-	"context"
 	"time"
 
-	"github.com/LyricTian/gin-admin/v8/internal/app/config"
-	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/json"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/structure"
 )
 
-// GetRootUser 获取root用户
-func GetRootUser() *User {
-	user := config.C.Root
-	return &User{
-		ID:       user.UserID,
-		UserName: user.UserName,
-		RealName: user.RealName,
-		Password: hash.MD5String(user.Password),
-	}
-}
-
-// CheckIsRootUser 检查是否是root用户
-func CheckIsRootUser(ctx context.Context, userID uint64) bool {
-	return GetRootUser().ID == userID
-}
+// SuperAdminRole 超级管理员在权限模型中的保留角色(g,user_id,super_admin)
+const SuperAdminRole = "super_admin"
 
 // User 用户对象
 type User struct {
-	ID        uint64    `json:"id,string"`                             // 唯一标识
-	UserName  string    `json:"user_name" binding:"required"`          // 用户名
-	RealName  string    `json:"real_name" binding:"required"`          // 真实姓名
-	Password  string    `json:"password"`                              // 密码
-	Phone     string    `json:"phone"`                                 // 手机号
-	Email     string    `json:"email"`                                 // 邮箱
-	Status    int       `json:"status" binding:"required,max=2,min=1"` // 用户状态(1:启用 2:停用)
-	Creator   uint64    `json:"creator"`                               // 创建者
-	CreatedAt time.Time `json:"created_at"`                            // 创建时间
-	UserRoles UserRoles `json:"user_roles" binding:"required,gt=0"`    // 角色授权
+	ID                 uint64     `json:"id,string"`                             // 唯一标识
+	UserName           string     `json:"user_name" binding:"required"`          // 用户名
+	RealName           string     `json:"real_name" binding:"required"`          // 真实姓名
+	Password           string     `json:"password"`                              // 密码(明文，仅创建及修改时有效)
+	Phone              string     `json:"phone"`                                 // 手机号
+	Email              string     `json:"email"`                                 // 邮箱
+	Status             int        `json:"status" binding:"required,max=2,min=1"` // 用户状态(1:启用 2:停用)
+	Creator            uint64     `json:"creator"`                               // 创建者
+	CreatedAt          time.Time  `json:"created_at"`                            // 创建时间
+	UserRoles          UserRoles  `json:"user_roles" binding:"required,gt=0"`    // 角色授权
+	DeptID             uint64     `json:"dept_id,string"`                        // 主部门ID
+	UserDepts          UserDepts  `json:"user_depts"`                            // 附属部门
+	MustChangePassword bool       `json:"must_change_password"`                  // 下次登录必须修改密码
+	PasswordChangedAt  *time.Time `json:"password_changed_at"`                   // 密码修改时间
+	LoginFailures      int        `json:"login_failures"`                        // 登录失败次数
+	LockedUntil        *time.Time `json:"locked_until"`                          // 登录锁定截止时间
+	MFAEnabled         bool       `json:"mfa_enabled"`                           // 是否启用两步验证
+	Source             string     `json:"source"`                                // 用户来源(为空时为本地用户，外部身份自动创建时为oidc/ldap)
+	IsSuperAdmin       bool       `json:"is_super_admin"`                        // 是否超级管理员(不受权限及数据范围限制)
+	TenantID           uint64     `json:"-"`                                     // 所属租户(由请求的租户决定)
 }
 
 func (a *User) String() string {
@@ -53,10 +47,15 @@ func (a *User) CleanSecure() *User {
 // UserQueryParam 查询条件
 type UserQueryParam struct {
 	PaginationParam
+	IDs        []uint64 `form:"-"`          // 用户ID列表
 	UserName   string   `form:"userName"`   // 用户名
+	Email      string   `form:"-"`          // 邮箱
 	QueryValue string   `form:"queryValue"` // 模糊查询
 	Status     int      `form:"status"`     // 用户状态(1:启用 2:停用)
 	RoleIDs    []uint64 `form:"-"`          // 角色ID列表
+	DeptID     uint64   `form:"deptID"`     // 部门ID(包含下级部门)
+	DeptIDs    []uint64 `form:"-"`          // 部门ID列表(主部门或附属部门)
+	SuperAdmin bool     `form:"-"`          // 仅查询超级管理员
 }
 
 // UserQueryOptions 查询可选参数项
@@ -82,6 +81,15 @@ func (a UserQueryResult) ToShowResult(mUserRoles map[uint64]UserRoles, mRoles ma
 // Users 用户对象列表
 type Users []*User
 
+// ToMap 转换为键值映射
+func (a Users) ToMap() map[uint64]*User {
+	m := make(map[uint64]*User)
+	for _, item := range a {
+		m[item.ID] = item
+	}
+	return m
+}
+
 // ToIDs 转换为唯一标识列表
 func (a Users) ToIDs() []uint64 {
 	idList := make([]uint64, len(a))
@@ -165,18 +173,71 @@ func (a UserRoles) ToUserIDMap() map[uint64]UserRoles {
 	return m
 }
 
+// ----------------------------------------UserDept--------------------------------------
+
+// UserDept 用户附属部门
+type UserDept struct {
+	ID     uint64 `json:"id,string"`                         // 唯一标识
+	UserID uint64 `json:"user_id,string"`                    // 用户ID
+	DeptID uint64 `json:"dept_id,string" binding:"required"` // 部门ID
+}
+
+// UserDeptQueryParam 查询条件
+type UserDeptQueryParam struct {
+	PaginationParam
+	UserID  uint64   // 用户ID
+	UserIDs []uint64 // 用户ID列表
+	DeptIDs []uint64 // 部门ID列表
+}
+
+// UserDeptQueryOptions 查询可选参数项
+type UserDeptQueryOptions struct {
+	OrderFields []*OrderField // 排序字段
+}
+
+// UserDeptQueryResult 查询结果
+type UserDeptQueryResult struct {
+	Data       UserDepts
+	PageResult *PaginationResult
+}
+
+// UserDepts 用户附属部门列表
+type UserDepts []*UserDept
+
+// ToMap 转换为map(键为部门ID)
+func (a UserDepts) ToMap() map[uint64]*UserDept {
+	m := make(map[uint64]*UserDept)
+	for _, item := range a {
+		m[item.DeptID] = item
+	}
+	return m
+}
+
+// ToDeptIDs 转换为部门ID列表
+func (a UserDepts) ToDeptIDs() []uint64 {
+	list := make([]uint64, len(a))
+	for i, item := range a {
+		list[i] = item.DeptID
+	}
+	return list
+}
+
 // ----------------------------------------UserShow--------------------------------------
 
 // UserShow 用户显示项
 type UserShow struct {
-	ID        uint64    `json:"id,string"`  // 唯一标识
-	UserName  string    `json:"user_name"`  // 用户名
-	RealName  string    `json:"real_name"`  // 真实姓名
-	Phone     string    `json:"phone"`      // 手机号
-	Email     string    `json:"email"`      // 邮箱
-	Status    int       `json:"status"`     // 用户状态(1:启用 2:停用)
-	CreatedAt time.Time `json:"created_at"` // 创建时间
-	Roles     []*Role   `json:"roles"`      // 授权角色列表
+	ID                 uint64    `json:"id,string"`            // 唯一标识
+	UserName           string    `json:"user_name"`            // 用户名
+	RealName           string    `json:"real_name"`            // 真实姓名
+	Phone              string    `json:"phone"`                // 手机号
+	Email              string    `json:"email"`                // 邮箱
+	Status             int       `json:"status"`               // 用户状态(1:启用 2:停用)
+	CreatedAt          time.Time `json:"created_at"`           // 创建时间
+	Roles              []*Role   `json:"roles"`                // 授权角色列表
+	DeptID             uint64    `json:"dept_id,string"`       // 主部门ID
+	MustChangePassword bool      `json:"must_change_password"` // 下次登录必须修改密码
+	Source             string    `json:"source"`               // 用户来源
+	IsSuperAdmin       bool      `json:"is_super_admin"`       // 是否超级管理员
 }
 
 // UserShows 用户显示项列表
@@ -187,3 +248,114 @@ type UserShowQueryResult struct {
 	Data       UserShows
 	PageResult *PaginationResult
 }
+
+// ----------------------------------------SuperAdmin--------------------------------------
+
+// SuperAdminParam 创建超级管理员参数
+type SuperAdminParam struct {
+	UserName string // 用户名(已存在时将该用户设为超级管理员)
+	Password string // 登录密码(明文，为空时不修改已存在用户的密码)
+	RealName string // 真实姓名
+}
+
+// ----------------------------------------UserPassword--------------------------------------
+
+// UserPassword 用户历史密码
+type UserPassword struct {
+	ID        uint64    // 唯一标识
+	UserID    uint64    // 用户ID
+	Password  string    // 密码哈希
+	CreatedAt time.Time // 创建时间
+}
+
+// UserPasswords 用户历史密码列表
+type UserPasswords []*UserPassword
+
+// ----------------------------------------Lockout--------------------------------------
+
+// UserLockout 用户登录锁定状态
+type UserLockout struct {
+	LoginFailures int        // 统计窗口内的登录失败次数
+	LockedUntil   *time.Time // 锁定截止时间(未锁定时为空)
+}
+
+// LoginLocked 登录被锁定时返回的详细信息
+type LoginLocked struct {
+	RetryAfter int `json:"retry_after"` // 允许再次尝试的等待秒数
+}
+
+// ----------------------------------------UserMFA--------------------------------------
+
+// UserMFA 用户两步验证(TOTP)
+type UserMFA struct {
+	ID        uint64    // 唯一标识
+	UserID    uint64    // 用户ID
+	Secret    string    // TOTP密钥
+	Enabled   bool      // 是否已完成绑定
+	LastStep  int64     // 最近一次验证通过的时间步(防止重放)
+	CreatedAt time.Time // 创建时间
+}
+
+// UserRecoveryCode 两步验证恢复码
+type UserRecoveryCode struct {
+	ID        uint64     // 唯一标识
+	UserID    uint64     // 用户ID
+	Code      string     // 恢复码哈希
+	UsedAt    *time.Time // 使用时间
+	CreatedAt time.Time  // 创建时间
+}
+
+// UserRecoveryCodes 恢复码列表
+type UserRecoveryCodes []*UserRecoveryCode
+
+// MFAEnrollment 两步验证绑定信息
+type MFAEnrollment struct {
+	Secret string `json:"secret"` // TOTP密钥(Base32)
+	URI    string `json:"uri"`    // otpauth URI
+}
+
+// MFACodeParam 两步验证码参数
+type MFACodeParam struct {
+	Code string `json:"code" binding:"required"` // 动态口令
+}
+
+// MFARecoveryCodes 两步验证恢复码(仅在生成时返回一次)
+type MFARecoveryCodes struct {
+	Codes []string `json:"codes"` // 恢复码列表
+}
+
+// ----------------------------------------PasswordPolicy--------------------------------------
+
+// PasswordViolation 密码策略校验失败项
+type PasswordViolation struct {
+	Rule    string `json:"rule"`    // 规则(min_length/upper/lower/digit/symbol/history)
+	Message string `json:"message"` // 说明
+}
+
+// PasswordViolations 密码策略校验失败项列表
+type PasswordViolations []*PasswordViolation
+
+// ----------------------------------------UserIdentity--------------------------------------
+
+// UserIdentity 用户的外部身份(OIDC)
+type UserIdentity struct {
+	ID        uint64    // 唯一标识
+	UserID    uint64    // 用户ID
+	Provider  string    // 身份提供方(签发者地址)
+	Subject   string    // 用户在身份提供方的唯一标识
+	Email     string    // 最近一次登录时的邮箱
+	CreatedAt time.Time // 创建时间
+}
+
+// ExternalUser 外部身份提供方(OIDC/LDAP)认证通过的用户信息
+type ExternalUser struct {
+	Source        string   // 用户来源(oidc/ldap)
+	Provider      string   // 身份提供方
+	Subject       string   // 用户在身份提供方的唯一标识
+	UserName      string   // 用户名
+	RealName      string   // 姓名
+	Email         string   // 邮箱
+	EmailVerified bool     // 邮箱是否已验证
+	Phone         string   // 手机号
+	Groups        []string // 用户组
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/logger"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var APIKeySet = wire.NewSet(wire.Struct(new(APIKeySrv), "*"))
+
+// API密钥前缀(用于与JWT令牌区分)
+const apiKeyPrefix = "gak_"
+
+// 最近使用时间的更新间隔(避免每次请求都写入数据库)
+const apiKeyTouchInterval = time.Minute
+
+// APIKeySrv 用户API密钥(供脚本、CI等机器客户端调用接口)
+type APIKeySrv struct {
+	TransRepo          *dao.TransRepo
+	UserRepo           *dao.UserRepo
+	UserRoleRepo       *dao.UserRoleRepo
+	UserAPIKeyRepo     *dao.UserAPIKeyRepo
+	UserAPIKeyRoleRepo *dao.UserAPIKeyRoleRepo
+}
+
+func hashAPIKey(key string) string {
+	return hash.SHA256String(key)
+}
+
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Query 查询用户的API密钥列表
+func (a *APIKeySrv) Query(ctx context.Context, userID uint64) (schema.UserAPIKeys, error) {
+	result, err := a.UserAPIKeyRepo.Query(ctx, schema.UserAPIKeyQueryParam{
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(result.Data) == 0 {
+		return schema.UserAPIKeys{}, nil
+	}
+
+	keyRoleResult, err := a.UserAPIKeyRoleRepo.Query(ctx, schema.UserAPIKeyRoleQueryParam{
+		APIKeyIDs: result.Data.ToIDs(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data.FillKeyRoles(keyRoleResult.Data.ToAPIKeyIDMap()), nil
+}
+
+// Create 创建API密钥，密钥明文仅在创建时返回
+func (a *APIKeySrv) Create(ctx context.Context, userID uint64, params schema.UserAPIKeyCreateParam) (*schema.UserAPIKeyCreateResult, error) {
+	if contextx.FromAPIKeyID(ctx) != 0 {
+		return nil, errors.NewResponse(0, 403, "API密钥不能用于创建新的API密钥")
+	} else if params.ExpiresAt != nil && !params.ExpiresAt.After(time.Now()) {
+		return nil, errors.New400Response("过期时间必须晚于当前时间")
+	}
+
+	if len(params.KeyRoles) > 0 {
+		userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+			UserID: userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		mUserRoles := userRoleResult.Data.ToMap()
+		for _, item := range params.KeyRoles {
+			if _, ok := mUserRoles[item.RoleID]; !ok {
+				return nil, errors.New400Response("只能限定为用户已授权的角色")
+			}
+		}
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	item := schema.UserAPIKey{
+		ID:        snowflake.MustID(),
+		UserID:    userID,
+		Name:      params.Name,
+		Prefix:    key[:len(apiKeyPrefix)+8],
+		KeyHash:   hashAPIKey(key),
+		ExpiresAt: params.ExpiresAt,
+	}
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		for _, krItem := range params.KeyRoles {
+			krItem.ID = snowflake.MustID()
+			krItem.APIKeyID = item.ID
+			err := a.UserAPIKeyRoleRepo.Create(ctx, *krItem)
+			if err != nil {
+				return err
+			}
+		}
+
+		return a.UserAPIKeyRepo.Create(ctx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.UserAPIKeyCreateResult{ID: item.ID, Key: key}, nil
+}
+
+// Delete 吊销用户的API密钥
+func (a *APIKeySrv) Delete(ctx context.Context, userID, id uint64) error {
+	oldItem, err := a.UserAPIKeyRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil || oldItem.UserID != userID {
+		return errors.ErrNotFound
+	}
+
+	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.UserAPIKeyRoleRepo.DeleteByAPIKeyID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.UserAPIKeyRepo.Delete(ctx, id)
+	})
+}
+
+// DeleteByUserID 删除用户的全部API密钥
+func (a *APIKeySrv) DeleteByUserID(ctx context.Context, userID uint64) error {
+	err := a.UserAPIKeyRoleRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return a.UserAPIKeyRepo.DeleteByUserID(ctx, userID)
+}
+
+// IsAPIKey 检查令牌是否为API密钥
+func (a *APIKeySrv) IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// VerifyAPIKey 校验API密钥，返回所属用户及限定的角色
+func (a *APIKeySrv) VerifyAPIKey(ctx context.Context, key string) (*schema.APIKeyIdentity, error) {
+	item, err := a.UserAPIKeyRepo.GetByKeyHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	} else if item == nil || item.IsExpired(time.Now()) {
+		return nil, errors.ErrInvalidToken
+	}
+
+	user, err := a.UserRepo.Get(ctx, item.UserID)
+	if err != nil {
+		return nil, err
+	} else if user == nil || user.Status != 1 {
+		return nil, errors.ErrInvalidToken
+	}
+
+	identity := &schema.APIKeyIdentity{
+		APIKeyID: item.ID,
+		UserID:   user.ID,
+		UserName: user.UserName,
+		TenantID: user.TenantID,
+	}
+
+	keyRoleResult, err := a.UserAPIKeyRoleRepo.Query(ctx, schema.UserAPIKeyRoleQueryParam{
+		APIKeyID: item.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 限定的角色与用户当前拥有的角色取交集，用户失去的角色不再生效
+	if len(keyRoleResult.Data) > 0 {
+		userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+			UserID: user.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		mUserRoles := userRoleResult.Data.ToMap()
+		identity.Restricted = true
+		for _, roleID := range keyRoleResult.Data.ToRoleIDs() {
+			if _, ok := mUserRoles[roleID]; ok {
+				identity.RoleIDs = append(identity.RoleIDs, strconv.FormatUint(roleID, 10))
+			}
+		}
+	}
+
+	if now := time.Now(); item.LastUsedAt == nil || now.Sub(*item.LastUsedAt) > apiKeyTouchInterval {
+		err := a.UserAPIKeyRepo.UpdateLastUsedAt(ctx, item.ID, now)
+		if err != nil {
+			logger.WithContext(ctx).Errorf("update api key last used error: %s", err.Error())
+		}
+	}
+
+	return identity, nil
+}
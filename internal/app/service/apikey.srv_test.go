@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+func TestAPIKey(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:apikey?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &APIKeySrv{
+		TransRepo:          &dao.TransRepo{DB: db},
+		UserRepo:           &dao.UserRepo{DB: db},
+		UserRoleRepo:       &dao.UserRoleRepo{DB: db},
+		UserAPIKeyRepo:     &dao.UserAPIKeyRepo{DB: db},
+		UserAPIKeyRoleRepo: &dao.UserAPIKeyRoleRepo{DB: db},
+	}
+
+	// 用户12拥有角色1和角色2
+	ctx := context.Background()
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 12, UserName: "u12", Status: 1}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 1, UserID: 12, RoleID: 1}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 2, UserID: 12, RoleID: 2}))
+
+	// 仅保存密钥哈希，密钥明文只在创建时返回
+	result, err := a.Create(ctx, 12, schema.UserAPIKeyCreateParam{Name: "ci"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, a.IsAPIKey(result.Key))
+	item, err := a.UserAPIKeyRepo.Get(ctx, result.ID)
+	if assert.Nil(t, err) && assert.NotNil(t, item) {
+		assert.Equal(t, hashAPIKey(result.Key), item.KeyHash)
+		assert.NotEqual(t, result.Key, item.KeyHash)
+		assert.True(t, strings.HasPrefix(result.Key, item.Prefix))
+	}
+	keys, err := a.Query(ctx, 12)
+	assert.Nil(t, err)
+	buf, err := json.Marshal(keys)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(buf), result.Key)
+	assert.NotContains(t, string(buf), hashAPIKey(result.Key))
+
+	identity, err := a.VerifyAPIKey(ctx, result.Key)
+	if assert.Nil(t, err) {
+		assert.Equal(t, uint64(12), identity.UserID)
+		assert.Equal(t, result.ID, identity.APIKeyID)
+		assert.False(t, identity.Restricted)
+	}
+	_, err = a.VerifyAPIKey(ctx, result.Key+"x")
+	assert.Equal(t, errors.ErrInvalidToken, err)
+
+	// 限定为用户的部分角色
+	_, err = a.Create(ctx, 12, schema.UserAPIKeyCreateParam{
+		Name:     "invalid",
+		KeyRoles: schema.UserAPIKeyRoles{{RoleID: 3}},
+	})
+	assert.NotNil(t, err)
+	result, err = a.Create(ctx, 12, schema.UserAPIKeyCreateParam{
+		Name:     "restricted",
+		KeyRoles: schema.UserAPIKeyRoles{{RoleID: 1}},
+	})
+	if assert.Nil(t, err) {
+		identity, err = a.VerifyAPIKey(ctx, result.Key)
+		if assert.Nil(t, err) {
+			assert.True(t, identity.Restricted)
+			assert.Equal(t, []string{"1"}, identity.RoleIDs)
+		}
+	}
+
+	// 过期的密钥
+	past := time.Now().Add(-time.Minute)
+	_, err = a.Create(ctx, 12, schema.UserAPIKeyCreateParam{Name: "expired", ExpiresAt: &past})
+	assert.NotNil(t, err)
+	assert.Nil(t, a.UserAPIKeyRepo.Create(ctx, schema.UserAPIKey{
+		ID:        100,
+		UserID:    12,
+		Name:      "expired",
+		Prefix:    apiKeyPrefix,
+		KeyHash:   hashAPIKey(apiKeyPrefix + "expired"),
+		ExpiresAt: &past,
+	}))
+	_, err = a.VerifyAPIKey(ctx, apiKeyPrefix+"expired")
+	assert.Equal(t, errors.ErrInvalidToken, err)
+
+	// 不能使用API密钥创建新的API密钥
+	_, err = a.Create(contextx.NewAPIKey(ctx, result.ID, nil, false), 12, schema.UserAPIKeyCreateParam{Name: "nested"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, 403, errors.UnWrapResponse(err).Status)
+	}
+}
+
+func TestUserAPIKeyScope(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:apikeyscope?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, util.RegisterTenantCallbacks(db))
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &UserSrv{
+		UserRepo: &dao.UserRepo{DB: db},
+		APIKeySrv: &APIKeySrv{
+			TransRepo:          &dao.TransRepo{DB: db},
+			UserRepo:           &dao.UserRepo{DB: db},
+			UserRoleRepo:       &dao.UserRoleRepo{DB: db},
+			UserAPIKeyRepo:     &dao.UserAPIKeyRepo{DB: db},
+			UserAPIKeyRoleRepo: &dao.UserAPIKeyRoleRepo{DB: db},
+		},
+	}
+
+	// 租户1的用户12(由用户11创建)
+	ctx := contextx.NewTenantID(context.Background(), 1)
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 12, UserName: "u12", Status: 1, Creator: 11}))
+	result, err := a.APIKeySrv.Create(ctx, 12, schema.UserAPIKeyCreateParam{Name: "ci"})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// 数据权限范围外(仅本人)及其他租户的管理员无法查看或吊销
+	for _, octx := range []context.Context{
+		contextx.NewDataScope(ctx, []uint64{13}),
+		contextx.NewTenantID(ctx, 2),
+	} {
+		_, err = a.QueryAPIKeys(octx, 12)
+		assert.Equal(t, errors.ErrNotFound, err)
+		assert.Equal(t, errors.ErrNotFound, a.DeleteAPIKey(octx, 12, result.ID))
+	}
+
+	keys, err := a.QueryAPIKeys(contextx.NewDataScope(ctx, []uint64{11}), 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(keys))
+	assert.Nil(t, a.DeleteAPIKey(ctx, 12, result.ID))
+	keys, err = a.QueryAPIKeys(ctx, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(keys))
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/ldapauth"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/logger"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
+)
+
+var AuthenticatorSet = wire.NewSet(
+	wire.Struct(new(LocalAuthenticator), "*"),
+	wire.Struct(new(LDAPAuthenticator), "*"),
+	NewAuthenticators,
+)
+
+// 认证器未找到用户或密码不正确时返回，认证器链会继续尝试下一个认证器
+var (
+	ErrAuthUserNotFound      = errors.New400Response("not found user_name")
+	ErrAuthPasswordIncorrect = errors.New400Response("password incorrect")
+)
+
+// Authenticator 登录认证器
+type Authenticator interface {
+	// 校验用户名与密码，返回对应的本地用户
+	Authenticate(ctx context.Context, userName, password string) (*schema.User, error)
+}
+
+// Authenticators 认证器链(按配置顺序依次尝试)
+type Authenticators []Authenticator
+
+// NewAuthenticators 按配置创建认证器链
+func NewAuthenticators(local *LocalAuthenticator, ldap *LDAPAuthenticator) (Authenticators, error) {
+	names := config.C.Login.Authenticators
+	if len(names) == 0 {
+		names = []string{"local"}
+	}
+
+	list := make(Authenticators, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case "local":
+			list = append(list, local)
+		case "ldap":
+			if ldap.Client == nil {
+				return nil, fmt.Errorf("ldap authenticator is not configured")
+			}
+			list = append(list, ldap)
+		default:
+			return nil, fmt.Errorf("unknown authenticator: %s", name)
+		}
+	}
+	return list, nil
+}
+
+// Authenticate 依次尝试各认证器，返回第一个认证通过的用户；
+// 所有认证器均未找到用户或密码不正确时返回对应的错误，其他错误直接返回
+func (a Authenticators) Authenticate(ctx context.Context, userName, password string) (*schema.User, error) {
+	lastErr := ErrAuthUserNotFound
+	for _, item := range a {
+		user, err := item.Authenticate(ctx, userName, password)
+		if err == nil {
+			return user, nil
+		} else if err == ErrAuthPasswordIncorrect {
+			lastErr = err
+		} else if err != ErrAuthUserNotFound {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// LocalAuthenticator 本地用户认证
+type LocalAuthenticator struct {
+	UserRepo       *dao.UserRepo
+	PasswordHasher hash.PasswordHasher
+}
+
+// Authenticate ...
+func (a *LocalAuthenticator) Authenticate(ctx context.Context, userName, password string) (*schema.User, error) {
+	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+		UserName: userName,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(result.Data) == 0 {
+		return nil, ErrAuthUserNotFound
+	}
+
+	item := result.Data[0]
+	password = passwordDigest(password)
+	if ok, err := a.PasswordHasher.Verify(item.Password, password); err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, ErrAuthPasswordIncorrect
+	}
+
+	// 旧版哈希或哈希参数变更时，使用当前算法重新生成
+	if a.PasswordHasher.NeedsRehash(item.Password) {
+		err := a.rehashPassword(ctx, item, password)
+		if err != nil {
+			logger.WithContext(ctx).Errorf("rehash password error: %s", err.Error())
+		}
+	}
+
+	return item, nil
+}
+
+func (a *LocalAuthenticator) rehashPassword(ctx context.Context, item *schema.User, password string) error {
+	encoded, err := a.PasswordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	err = a.UserRepo.UpdatePassword(ctx, item.ID, encoded)
+	if err != nil {
+		return err
+	}
+	item.Password = encoded
+	return nil
+}
+
+// LDAPAuthenticator LDAP/Active Directory认证，首次登录时关联或创建本地用户，并按用户组同步角色
+type LDAPAuthenticator struct {
+	Client      *ldapauth.Client
+	IdentitySrv *IdentitySrv
+}
+
+// Authenticate ...
+func (a *LDAPAuthenticator) Authenticate(ctx context.Context, userName, password string) (*schema.User, error) {
+	entry, err := a.Client.Authenticate(ctx, userName, password)
+	if err == ldapauth.ErrUserNotFound {
+		return nil, ErrAuthUserNotFound
+	} else if err == ldapauth.ErrInvalidCredentials {
+		return nil, ErrAuthPasswordIncorrect
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cfg := config.C.LDAP
+	user, err := a.IdentitySrv.GetUser(ctx, schema.ExternalUser{
+		Source:   "ldap",
+		Provider: "ldap",
+		Subject:  entry.ID,
+		UserName: entry.UserName,
+		RealName: entry.RealName,
+		Email:    entry.Email,
+		Phone:    entry.Phone,
+		Groups:   entry.Groups,
+	}, IdentityOptions{
+		AutoCreate:     cfg.AutoCreate,
+		LinkByUserName: cfg.LinkByUserName,
+	})
+	if err != nil {
+		return nil, err
+	} else if user.Status != 1 {
+		return user, nil
+	}
+
+	err = a.IdentitySrv.SyncRoles(ctx, user.ID, entry.Groups, cfg.GroupRoles)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+)
+
+var DataScopeSet = wire.NewSet(wire.Struct(new(DataScopeSrv), "*"))
+
+// DataScopeSrv 根据用户角色计算可访问的数据范围
+type DataScopeSrv struct {
+	UserRepo         *dao.UserRepo
+	UserRoleRepo     *dao.UserRoleRepo
+	UserDeptRepo     *dao.UserDeptRepo
+	RoleRepo         *dao.RoleRepo
+	RoleDataUserRepo *dao.RoleDataUserRepo
+	DeptSrv          *DeptSrv
+	SuperAdminSrv    *SuperAdminSrv
+}
+
+// Resolve 获取用户的数据范围，all为true表示不限定，否则返回可访问数据的创建者ID列表
+// 多个角色取并集，未分配角色的用户仅能访问本人创建的数据
+func (a *DataScopeSrv) Resolve(ctx context.Context, userID uint64) (creators []uint64, all bool, err error) {
+	if ok, err := a.SuperAdminSrv.IsSuperAdmin(ctx, userID); err != nil {
+		return nil, false, err
+	} else if ok {
+		return nil, true, nil
+	}
+
+	roleIDs, err := a.getRoleIDs(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	creators = []uint64{userID}
+	if len(roleIDs) == 0 {
+		return creators, false, nil
+	}
+
+	roleResult, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{
+		IDs: roleIDs,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var customRoleIDs []uint64
+	var deptScope bool
+	for _, item := range roleResult.Data {
+		if item.Status != 1 {
+			continue
+		}
+
+		switch item.DataScope {
+		case schema.DataScopeAll:
+			return nil, true, nil
+		case schema.DataScopeCustom:
+			customRoleIDs = append(customRoleIDs, item.ID)
+		case schema.DataScopeDept:
+			deptScope = true
+		}
+	}
+
+	m := map[uint64]struct{}{userID: {}}
+	appendCreators := func(ids []uint64) {
+		for _, id := range ids {
+			if _, ok := m[id]; ok {
+				continue
+			}
+			m[id] = struct{}{}
+			creators = append(creators, id)
+		}
+	}
+
+	if len(customRoleIDs) > 0 {
+		result, err := a.RoleDataUserRepo.Query(ctx, schema.RoleDataUserQueryParam{
+			RoleIDs: customRoleIDs,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		appendCreators(result.Data.ToUserIDs())
+	}
+
+	if deptScope {
+		userIDs, err := a.queryDeptUserIDs(ctx, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		appendCreators(userIDs)
+	}
+
+	return creators, false, nil
+}
+
+// 获取用户所在部门(主部门及附属部门)及下级部门的全部用户
+func (a *DataScopeSrv) queryDeptUserIDs(ctx context.Context, userID uint64) ([]uint64, error) {
+	user, err := a.UserRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, nil
+	}
+
+	userDeptResult, err := a.UserDeptRepo.Query(ctx, schema.UserDeptQueryParam{
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deptIDs := userDeptResult.Data.ToDeptIDs()
+	if user.DeptID != 0 {
+		deptIDs = append(deptIDs, user.DeptID)
+	}
+	if len(deptIDs) == 0 {
+		return nil, nil
+	}
+
+	deptIDs, err = a.DeptSrv.QuerySubIDs(ctx, deptIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+		DeptIDs: deptIDs,
+	}, schema.UserQueryOptions{
+		SelectFields: []string{"id"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data.ToIDs(), nil
+}
+
+// 限定了角色的API密钥仅按限定的角色计算
+func (a *DataScopeSrv) getRoleIDs(ctx context.Context, userID uint64) ([]uint64, error) {
+	if roleIDs, ok := contextx.FromAPIKeyRoles(ctx); ok {
+		ids := make([]uint64, 0, len(roleIDs))
+		for _, s := range roleIDs {
+			id, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	result, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data.ToRoleIDs(), nil
+}
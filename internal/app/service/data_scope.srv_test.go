@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+)
+
+func TestDataScope(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:datascope?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	userRepo := &dao.UserRepo{DB: db}
+	deptRepo := &dao.DeptRepo{DB: db}
+	a := &DataScopeSrv{
+		UserRepo:         userRepo,
+		UserRoleRepo:     &dao.UserRoleRepo{DB: db},
+		UserDeptRepo:     &dao.UserDeptRepo{DB: db},
+		RoleRepo:         &dao.RoleRepo{DB: db},
+		RoleDataUserRepo: &dao.RoleDataUserRepo{DB: db},
+		DeptSrv:          &DeptSrv{DeptRepo: deptRepo, UserRepo: userRepo},
+		SuperAdminSrv:    &SuperAdminSrv{UserRepo: userRepo},
+	}
+
+	ctx := context.Background()
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 1, Name: "all", Status: 1, DataScope: schema.DataScopeAll}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 2, Name: "self", Status: 1, DataScope: schema.DataScopeSelf}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 3, Name: "custom", Status: 1, DataScope: schema.DataScopeCustom}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 4, Name: "dept", Status: 1, DataScope: schema.DataScopeDept}))
+	assert.Nil(t, a.RoleDataUserRepo.Create(ctx, schema.RoleDataUser{ID: 1, RoleID: 3, UserID: 12}))
+
+	// 用户11创建了用户12，用户12创建了用户13
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 11, UserName: "u11", Status: 1}))
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 12, UserName: "u12", Status: 1, Creator: 11}))
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 13, UserName: "u13", Status: 1, Creator: 12}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 1, UserID: 11, RoleID: 2}))
+
+	scopeCtx := func(userID uint64) context.Context {
+		creators, all, err := a.Resolve(ctx, userID)
+		assert.Nil(t, err)
+		if all {
+			return ctx
+		}
+		return contextx.NewDataScope(ctx, creators)
+	}
+
+	// 仅本人
+	sctx := scopeCtx(11)
+	result, err := userRepo.Query(sctx, schema.UserQueryParam{})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{12}, result.Data.ToIDs())
+
+	item, err := userRepo.Get(sctx, 13)
+	assert.Nil(t, err)
+	assert.Nil(t, item)
+
+	assert.Nil(t, userRepo.UpdateStatus(sctx, 13, 2))
+	item, err = userRepo.Get(ctx, 13)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, item.Status)
+
+	result, err = userRepo.Query(contextx.NewNoDataScope(sctx), schema.UserQueryParam{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result.Data))
+
+	// 自定义范围包含本人及指定的用户
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 2, UserID: 11, RoleID: 3}))
+	result, err = userRepo.Query(scopeCtx(11), schema.UserQueryParam{})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []uint64{12, 13}, result.Data.ToIDs())
+
+	// 任一角色为全部数据时不限定
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 3, UserID: 11, RoleID: 1}))
+	_, all, err := a.Resolve(ctx, 11)
+	assert.Nil(t, err)
+	assert.True(t, all)
+
+	// 限定角色的API密钥仅按限定的角色计算
+	creators, all, err := a.Resolve(contextx.NewAPIKey(ctx, 1, []string{"2"}, true), 11)
+	assert.Nil(t, err)
+	assert.False(t, all)
+	assert.Equal(t, []uint64{11}, creators)
+
+	// 未分配角色的用户仅能访问本人创建的数据
+	creators, all, err = a.Resolve(ctx, 13)
+	assert.Nil(t, err)
+	assert.False(t, all)
+	assert.Equal(t, []uint64{13}, creators)
+
+	// 本部门及以下：部门1(用户21) -> 部门2(用户22)，部门3(附属部门，用户23)
+	assert.Nil(t, deptRepo.Create(ctx, schema.Dept{ID: 1, Name: "d1", Status: 1}))
+	assert.Nil(t, deptRepo.Create(ctx, schema.Dept{ID: 2, Name: "d2", Status: 1, ParentID: 1, ParentPath: "1"}))
+	assert.Nil(t, deptRepo.Create(ctx, schema.Dept{ID: 3, Name: "d3", Status: 1}))
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 21, UserName: "u21", Status: 1, DeptID: 1}))
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 22, UserName: "u22", Status: 1, DeptID: 2}))
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 23, UserName: "u23", Status: 1, DeptID: 3}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 4, UserID: 21, RoleID: 4}))
+
+	creators, all, err = a.Resolve(ctx, 21)
+	assert.Nil(t, err)
+	assert.False(t, all)
+	assert.ElementsMatch(t, []uint64{21, 22}, creators)
+
+	assert.Nil(t, a.UserDeptRepo.Create(ctx, schema.UserDept{ID: 1, UserID: 21, DeptID: 3}))
+	creators, _, err = a.Resolve(ctx, 21)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []uint64{21, 22, 23}, creators)
+
+	// 启用的超级管理员不限定(限定角色的API密钥除外)
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 31, UserName: "u31", Status: 1, IsSuperAdmin: true}))
+	assert.Nil(t, userRepo.Create(ctx, schema.User{ID: 32, UserName: "u32", Status: 2, IsSuperAdmin: true}))
+	_, all, err = a.Resolve(ctx, 31)
+	assert.Nil(t, err)
+	assert.True(t, all)
+
+	creators, all, err = a.Resolve(contextx.NewAPIKey(ctx, 2, []string{"2"}, true), 31)
+	assert.Nil(t, err)
+	assert.False(t, all)
+	assert.Equal(t, []uint64{31}, creators)
+
+	_, all, err = a.Resolve(ctx, 32)
+	assert.Nil(t, err)
+	assert.False(t, all)
+}
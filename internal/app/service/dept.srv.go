@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var DeptSet = wire.NewSet(wire.Struct(new(DeptSrv), "*"))
+
+type DeptSrv struct {
+	TransRepo *dao.TransRepo
+	DeptRepo  *dao.DeptRepo
+	UserRepo  *dao.UserRepo
+}
+
+// 部门树节点的读写
+type deptTreeStore struct {
+	repo *dao.DeptRepo
+}
+
+func (s deptTreeStore) getNode(ctx context.Context, id uint64) (*treeNode, error) {
+	item, err := s.repo.Get(ctx, id)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return &treeNode{ID: item.ID, ParentPath: item.ParentPath}, nil
+}
+
+func (s deptTreeStore) queryByPrefixParentPath(ctx context.Context, prefix string) ([]*treeNode, error) {
+	result, err := s.repo.Query(ctx, schema.DeptQueryParam{
+		PrefixParentPath: prefix,
+	}, schema.DeptQueryOptions{
+		SelectFields: []string{"id", "parent_path"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*treeNode, len(result.Data))
+	for i, item := range result.Data {
+		list[i] = &treeNode{ID: item.ID, ParentPath: item.ParentPath}
+	}
+	return list, nil
+}
+
+func (s deptTreeStore) updateParentPath(ctx context.Context, id uint64, parentPath string) error {
+	return s.repo.UpdateParentPath(ctx, id, parentPath)
+}
+
+func (a *DeptSrv) treeStore() treeStore {
+	return deptTreeStore{repo: a.DeptRepo}
+}
+
+func (a *DeptSrv) Query(ctx context.Context, params schema.DeptQueryParam, opts ...schema.DeptQueryOptions) (*schema.DeptQueryResult, error) {
+	return a.DeptRepo.Query(ctx, params, opts...)
+}
+
+func (a *DeptSrv) Get(ctx context.Context, id uint64, opts ...schema.DeptQueryOptions) (*schema.Dept, error) {
+	item, err := a.DeptRepo.Get(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, errors.ErrNotFound
+	}
+	return item, nil
+}
+
+// QuerySubIDs 获取部门及其所有下级部门的ID列表
+func (a *DeptSrv) QuerySubIDs(ctx context.Context, ids ...uint64) ([]uint64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	result, err := a.DeptRepo.Query(ctx, schema.DeptQueryParam{
+		IDs: ids,
+	}, schema.DeptQueryOptions{
+		SelectFields: []string{"id", "parent_path"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idList := make([]uint64, 0, len(ids))
+	mIDList := make(map[uint64]struct{})
+	for _, id := range ids {
+		idList = append(idList, id)
+		mIDList[id] = struct{}{}
+	}
+
+	for _, item := range result.Data {
+		path := joinParentPath(item.ParentPath, item.ID)
+		nodes, err := a.treeStore().queryByPrefixParentPath(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodes {
+			if _, ok := mIDList[node.ID]; ok || !isSubParentPath(node.ParentPath, path) {
+				continue
+			}
+			idList = append(idList, node.ID)
+			mIDList[node.ID] = struct{}{}
+		}
+	}
+	return idList, nil
+}
+
+func (a *DeptSrv) checkName(ctx context.Context, item schema.Dept) error {
+	result, err := a.DeptRepo.Query(ctx, schema.DeptQueryParam{
+		PaginationParam: schema.PaginationParam{
+			OnlyCount: true,
+		},
+		ParentID: &item.ParentID,
+		Name:     item.Name,
+	})
+	if err != nil {
+		return err
+	} else if result.PageResult.Total > 0 {
+		return errors.New400Response("名称不能重复")
+	}
+	return nil
+}
+
+func (a *DeptSrv) Create(ctx context.Context, item schema.Dept) (*schema.IDResult, error) {
+	if err := a.checkName(ctx, item); err != nil {
+		return nil, err
+	}
+
+	parentPath, err := getTreeParentPath(ctx, a.treeStore(), 0, item.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	item.ParentPath = parentPath
+	item.ID = snowflake.MustID()
+
+	err = a.DeptRepo.Create(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.NewIDResult(item.ID), nil
+}
+
+func (a *DeptSrv) Update(ctx context.Context, id uint64, item schema.Dept) error {
+	oldItem, err := a.DeptRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	} else if oldItem.Name != item.Name || oldItem.ParentID != item.ParentID {
+		if err := a.checkName(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	item.ID = oldItem.ID
+	item.Creator = oldItem.Creator
+	item.CreatedAt = oldItem.CreatedAt
+
+	if oldItem.ParentID != item.ParentID {
+		parentPath, err := getTreeParentPath(ctx, a.treeStore(), id, item.ParentID)
+		if err != nil {
+			return err
+		}
+		item.ParentPath = parentPath
+	} else {
+		item.ParentPath = oldItem.ParentPath
+	}
+
+	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := updateTreeChildParentPath(ctx, a.treeStore(), id, oldItem.ParentPath, item.ParentPath)
+		if err != nil {
+			return err
+		}
+
+		return a.DeptRepo.Update(ctx, id, item)
+	})
+}
+
+// Move 移动部门到新的父级下(包含所有下级部门)
+func (a *DeptSrv) Move(ctx context.Context, id uint64, params schema.DeptMoveParam) error {
+	oldItem, err := a.DeptRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	parentPath := oldItem.ParentPath
+	if oldItem.ParentID != params.ParentID {
+		parentPath, err = getTreeParentPath(ctx, a.treeStore(), id, params.ParentID)
+		if err != nil {
+			return err
+		}
+
+		err = a.checkName(ctx, schema.Dept{ParentID: params.ParentID, Name: oldItem.Name})
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		if oldItem.ParentID != params.ParentID {
+			err := updateTreeChildParentPath(ctx, a.treeStore(), id, oldItem.ParentPath, parentPath)
+			if err != nil {
+				return err
+			}
+
+			err = a.DeptRepo.Move(ctx, id, params.ParentID, parentPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		if v := params.Sequence; v != nil && *v != oldItem.Sequence {
+			return a.DeptRepo.UpdateSequence(ctx, id, *v)
+		}
+		return nil
+	})
+}
+
+func (a *DeptSrv) Delete(ctx context.Context, id uint64) error {
+	oldItem, err := a.DeptRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	result, err := a.DeptRepo.Query(ctx, schema.DeptQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		ParentID:        &id,
+	})
+	if err != nil {
+		return err
+	} else if result.PageResult.Total > 0 {
+		return errors.New400Response("不允许删除存在下级部门的部门")
+	}
+
+	// 数据范围外的用户同样属于该部门
+	userResult, err := a.UserRepo.Query(contextx.NewNoDataScope(ctx), schema.UserQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		DeptIDs:         []uint64{id},
+	})
+	if err != nil {
+		return err
+	} else if userResult.PageResult.Total > 0 {
+		return errors.New400Response("不允许删除存在用户的部门")
+	}
+
+	return a.DeptRepo.Delete(ctx, id)
+}
+
+func (a *DeptSrv) UpdateStatus(ctx context.Context, id uint64, status int) error {
+	oldItem, err := a.DeptRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	} else if oldItem.Status == status {
+		return nil
+	}
+
+	return a.DeptRepo.UpdateStatus(ctx, id, status)
+}
+
+// checkDepts 检查部门是否存在
+func (a *DeptSrv) checkDepts(ctx context.Context, ids ...uint64) error {
+	var idList []uint64
+	mIDList := make(map[uint64]struct{})
+	for _, id := range ids {
+		if _, ok := mIDList[id]; ok || id == 0 {
+			continue
+		}
+		idList = append(idList, id)
+		mIDList[id] = struct{}{}
+	}
+	if len(idList) == 0 {
+		return nil
+	}
+
+	result, err := a.DeptRepo.Query(ctx, schema.DeptQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		IDs:             idList,
+	})
+	if err != nil {
+		return err
+	} else if int(result.PageResult.Total) != len(idList) {
+		return errors.New400Response("部门不存在")
+	}
+	return nil
+}
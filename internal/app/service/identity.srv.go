@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/logger"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var IdentitySet = wire.NewSet(wire.Struct(new(IdentitySrv), "*"))
+
+// IdentityOptions 外部身份关联本地用户的可选项
+type IdentityOptions struct {
+	AutoCreate     bool // 未关联时自动创建用户
+	LinkByEmail    bool // 按已验证的邮箱关联已有用户
+	LinkByUserName bool // 按用户名关联已有用户
+}
+
+// IdentitySrv 外部身份(OIDC/LDAP)与本地用户的关联及角色同步
+type IdentitySrv struct {
+	Enforcer         *casbin.SyncedEnforcer
+	CasbinWatcher    *watcher.Watcher
+	TransRepo        *dao.TransRepo
+	UserRepo         *dao.UserRepo
+	UserRoleRepo     *dao.UserRoleRepo
+	RoleRepo         *dao.RoleRepo
+	UserIdentityRepo *dao.UserIdentityRepo
+	PasswordSrv      *PasswordSrv
+}
+
+// GetUser 查找外部身份关联的用户，未关联时按可选项关联已有用户或创建新用户
+func (a *IdentitySrv) GetUser(ctx context.Context, ext schema.ExternalUser, opts IdentityOptions) (*schema.User, error) {
+	identity, err := a.UserIdentityRepo.GetBySubject(ctx, ext.Provider, ext.Subject)
+	if err != nil {
+		return nil, err
+	} else if identity != nil {
+		user, err := a.UserRepo.Get(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		} else if user == nil {
+			return nil, errors.ErrNoPerm
+		}
+
+		if identity.Email != ext.Email {
+			err := a.UserIdentityRepo.UpdateEmail(ctx, identity.ID, ext.Email)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+
+	var user *schema.User
+	if opts.LinkByEmail && ext.Email != "" && ext.EmailVerified {
+		user, err = a.findUser(ctx, schema.UserQueryParam{Email: ext.Email})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if user == nil && opts.LinkByUserName && ext.UserName != "" {
+		user, err = a.findUser(ctx, schema.UserQueryParam{UserName: ext.UserName})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		if !opts.AutoCreate {
+			return nil, errors.NewResponse(0, 403, "用户未开通，请联系管理员")
+		}
+
+		user, err = a.newUser(ctx, ext)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		if user.ID == 0 {
+			user.ID = snowflake.MustID()
+			err := a.UserRepo.Create(ctx, *user)
+			if err != nil {
+				return err
+			}
+
+			err = a.PasswordSrv.SaveHistory(ctx, user.ID, user.Password)
+			if err != nil {
+				return err
+			}
+		}
+
+		return a.UserIdentityRepo.Create(ctx, schema.UserIdentity{
+			ID:       snowflake.MustID(),
+			UserID:   user.ID,
+			Provider: ext.Provider,
+			Subject:  ext.Subject,
+			Email:    ext.Email,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 查找唯一匹配的用户(有多个匹配时不关联)
+func (a *IdentitySrv) findUser(ctx context.Context, params schema.UserQueryParam) (*schema.User, error) {
+	result, err := a.UserRepo.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	} else if len(result.Data) != 1 {
+		return nil, nil
+	}
+	return result.Data[0], nil
+}
+
+// 根据外部身份构造新用户(使用随机密码，只能通过外部身份登录，除非管理员重置密码)
+func (a *IdentitySrv) newUser(ctx context.Context, ext schema.ExternalUser) (*schema.User, error) {
+	userName := ext.UserName
+	if userName == "" {
+		userName = ext.Email
+	}
+	if userName == "" {
+		return nil, errors.New400Response("身份提供方未返回用户名")
+	}
+
+	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		UserName:        userName,
+	})
+	if err != nil {
+		return nil, err
+	} else if result.PageResult.Total > 0 {
+		// 未开启按用户名关联时不关联已有用户，避免外部身份冒用本地账号
+		return nil, errors.New400Response("user_name has been exists")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	password, err := a.PasswordSrv.Hash(base64.RawURLEncoding.EncodeToString(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	realName := ext.RealName
+	if realName == "" {
+		realName = userName
+	}
+
+	now := time.Now()
+	return &schema.User{
+		UserName:          userName,
+		RealName:          realName,
+		Password:          password,
+		Email:             ext.Email,
+		Phone:             ext.Phone,
+		Status:            1,
+		PasswordChangedAt: &now,
+		Source:            ext.Source,
+	}, nil
+}
+
+// SyncRoles 按用户组与角色名称的映射同步用户角色，只增删映射中出现的角色
+func (a *IdentitySrv) SyncRoles(ctx context.Context, userID uint64, groups []string, groupRoles map[string][]string) error {
+	if len(groupRoles) == 0 {
+		return nil
+	}
+
+	roleResult, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{}, schema.RoleQueryOptions{
+		SelectFields: []string{"id", "name"},
+	})
+	if err != nil {
+		return err
+	}
+
+	mRoleIDs := make(map[string]uint64)
+	for _, item := range roleResult.Data {
+		mRoleIDs[item.Name] = item.ID
+	}
+
+	managed := make(map[uint64]bool)
+	for group, names := range groupRoles {
+		for _, name := range names {
+			roleID, ok := mRoleIDs[name]
+			if !ok {
+				logger.WithContext(ctx).Warnf("identity group %s: not found role %s", group, name)
+				continue
+			}
+			managed[roleID] = false
+		}
+	}
+
+	for _, group := range groups {
+		for _, name := range groupRoles[strings.TrimSpace(group)] {
+			if roleID, ok := mRoleIDs[name]; ok {
+				managed[roleID] = true
+			}
+		}
+	}
+
+	userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var addRoleIDs []uint64
+	var delUserRoles schema.UserRoles
+	mUserRoles := userRoleResult.Data.ToMap()
+	for roleID, granted := range managed {
+		item, exists := mUserRoles[roleID]
+		if granted && !exists {
+			addRoleIDs = append(addRoleIDs, roleID)
+		} else if !granted && exists {
+			delUserRoles = append(delUserRoles, item)
+		}
+	}
+
+	if len(addRoleIDs) == 0 && len(delUserRoles) == 0 {
+		return nil
+	}
+
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		for _, roleID := range addRoleIDs {
+			err := a.UserRoleRepo.Create(ctx, schema.UserRole{
+				ID:     snowflake.MustID(),
+				UserID: userID,
+				RoleID: roleID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, item := range delUserRoles {
+			err := a.UserRoleRepo.Delete(ctx, item.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, roleID := range addRoleIDs {
+		a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(userID, 10), strconv.FormatUint(roleID, 10), tenantDomain(ctx))
+	}
+
+	for _, item := range delUserRoles {
+		a.Enforcer.DeleteRoleForUserInDomain(strconv.FormatUint(userID, 10), strconv.FormatUint(item.RoleID, 10), tenantDomain(ctx))
+	}
+
+	if len(addRoleIDs) > 0 || len(delUserRoles) > 0 {
+		return a.CasbinWatcher.Update()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/lockout"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/lockout/store/memory"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+func TestLockoutTenant(t *testing.T) {
+	cfg := config.C.LoginLockout
+	defer func() { config.C.LoginLockout = cfg }()
+	config.C.LoginLockout.Enable = true
+	config.C.LoginLockout.MaxFailures = 2
+	config.C.LoginLockout.Window = 60
+	config.C.LoginLockout.LockDuration = 60
+	config.C.LoginLockout.BaseDelay = 0
+
+	a := &LockoutSrv{Lockout: lockout.New(memory.NewStore(0))}
+	defer a.Lockout.Release()
+
+	// 两个租户下的同名用户
+	ctx1 := contextx.NewTenantID(context.Background(), 1)
+	ctx2 := contextx.NewTenantID(context.Background(), 2)
+
+	for i := 0; i < config.C.LoginLockout.MaxFailures; i++ {
+		a.Fail(ctx1, "admin", "")
+	}
+	err := a.Check(ctx1, "admin", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, 429, errors.UnWrapResponse(err).Status)
+	}
+
+	// 其他租户的同名用户不受影响
+	assert.Nil(t, a.Check(ctx2, "admin", ""))
+	assert.Nil(t, a.Check(context.Background(), "admin", ""))
+	status, err := a.Status(ctx2, "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, status.LoginFailures)
+	assert.Nil(t, status.LockedUntil)
+
+	// 其他租户的解锁不会清除锁定
+	assert.Nil(t, a.Reset(ctx2, "admin"))
+	status, err = a.Status(ctx1, "admin")
+	assert.Nil(t, err)
+	assert.NotNil(t, status.LockedUntil)
+	assert.NotNil(t, a.Check(ctx1, "admin", ""))
+
+	// 失败计数按租户分别统计
+	a.Fail(ctx2, "admin", "")
+	status, err = a.Status(ctx2, "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, status.LoginFailures)
+	assert.Nil(t, status.LockedUntil)
+
+	assert.Nil(t, a.Reset(ctx1, "admin"))
+	assert.Nil(t, a.Check(ctx1, "admin", ""))
+}
@@ -4,27 +4,38 @@ import (
 	"context"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/LyricTian/captcha"
 	"github.com/google/wire"
 
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
 	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
 	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/jwtauth"
 	"github.com/LyricTian/gin-admin/v8/pkg/errors"
-	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
 )
 
 var LoginSet = wire.NewSet(wire.Struct(new(LoginSrv), "*"))
 
 type LoginSrv struct {
 	Auth           auth.Auther
+	TransRepo      *dao.TransRepo
+	KeySet         *jwtauth.KeySet
+	PasswordSrv    *PasswordSrv
+	LockoutSrv     *LockoutSrv
+	MFASrv         *MFASrv
+	Authenticators Authenticators
 	UserRepo       *dao.UserRepo
 	UserRoleRepo   *dao.UserRoleRepo
 	RoleRepo       *dao.RoleRepo
 	RoleMenuRepo   *dao.RoleMenuRepo
+	RoleParentRepo *dao.RoleParentRepo
 	MenuRepo       *dao.MenuRepo
 	MenuActionRepo *dao.MenuActionRepo
+	SuperAdminSrv  *SuperAdminSrv
 }
 
 func (a *LoginSrv) GetCaptcha(ctx context.Context, length int) (*schema.LoginCaptcha, error) {
@@ -52,44 +63,155 @@ func (a *LoginSrv) ResCaptcha(ctx context.Context, w http.ResponseWriter, captch
 }
 
 func (a *LoginSrv) Verify(ctx context.Context, userName, password string) (*schema.User, error) {
-	root := schema.GetRootUser()
-	if userName == root.UserName && root.Password == password {
-		return root, nil
-	}
-
-	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
-		UserName: userName,
-	})
+	ip, _ := auth.FromClientContext(ctx)
+	err := a.LockoutSrv.Check(ctx, userName, ip)
 	if err != nil {
 		return nil, err
-	} else if len(result.Data) == 0 {
-		return nil, errors.New400Response("not found user_name")
 	}
 
-	item := result.Data[0]
-	if item.Password != hash.SHA1String(password) {
-		return nil, errors.New400Response("password incorrect")
+	item, err := a.Authenticators.Authenticate(ctx, userName, password)
+	if err != nil {
+		if err == ErrAuthUserNotFound || err == ErrAuthPasswordIncorrect {
+			a.LockoutSrv.Fail(ctx, userName, ip)
+		}
+		return nil, err
 	} else if item.Status != 1 {
 		return nil, errors.ErrUserDisable
 	}
 
+	// 启用两步验证的用户在动态口令校验通过后(VerifyMFA)再清除失败计数
+	enabled, err := a.MFASrv.IsEnabled(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	} else if !enabled {
+		err = a.LockoutSrv.Reset(ctx, userName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return item, nil
 }
 
+// GenerateMFAChallenge 用户启用了两步验证时生成挑战令牌(未启用时返回nil)
+func (a *LoginSrv) GenerateMFAChallenge(ctx context.Context, user *schema.User) (*schema.LoginMFAChallenge, error) {
+	enabled, err := a.MFASrv.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	} else if !enabled {
+		return nil, nil
+	}
+
+	expiration := time.Duration(config.C.MFA.ChallengeExpired) * time.Second
+	challenge, err := a.Auth.GenerateChallenge(ctx, tokenSubject(user.ID, user.UserName), expiration)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &schema.LoginMFAChallenge{
+		ChallengeToken: challenge,
+		ExpiresAt:      time.Now().Add(expiration).Unix(),
+	}, nil
+}
+
+// VerifyMFA 校验挑战令牌与动态口令(或恢复码)，通过后挑战令牌失效
+func (a *LoginSrv) VerifyMFA(ctx context.Context, challenge, code string) (*schema.User, error) {
+	subject, err := a.Auth.ParseChallenge(ctx, challenge)
+	if err != nil {
+		if err == auth.ErrInvalidToken {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	userID, userName, ok := parseTokenSubject(subject)
+	if !ok {
+		return nil, errors.ErrInvalidToken
+	}
+
+	ip, _ := auth.FromClientContext(ctx)
+	err = a.LockoutSrv.Check(ctx, userName, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.checkAndGetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if user.UserName != userName {
+		return nil, errors.ErrInvalidToken
+	}
+
+	ok, err = a.MFASrv.Verify(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		a.LockoutSrv.Fail(ctx, userName, ip)
+		return nil, errors.New400Response("动态口令不正确")
+	}
+
+	err = a.LockoutSrv.Reset(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.Auth.DestroyChallenge(ctx, challenge)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return user, nil
+}
+
+// GenerateToken 签发令牌(记录请求所属的租户)
 func (a *LoginSrv) GenerateToken(ctx context.Context, userID string) (*schema.LoginTokenInfo, error) {
+	if tenantID, ok := contextx.FromTenantID(ctx); ok {
+		ctx = auth.NewTenantContext(ctx, schema.TenantDomain(tenantID))
+	}
+
 	tokenInfo, err := a.Auth.GenerateToken(ctx, userID)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	item := &schema.LoginTokenInfo{
-		AccessToken: tokenInfo.GetAccessToken(),
-		TokenType:   tokenInfo.GetTokenType(),
-		ExpiresAt:   tokenInfo.GetExpiresAt(),
-	}
+	item := a.toLoginTokenInfo(tokenInfo)
+	item.MustChangePassword = auth.FromScopeContext(ctx) == auth.ScopePasswordChange
 	return item, nil
 }
 
+// NewTokenScopeContext 设定签发令牌的权限范围，需要修改密码的用户仅允许访问修改密码相关的接口
+func (a *LoginSrv) NewTokenScopeContext(ctx context.Context, user *schema.User) context.Context {
+	if !a.PasswordSrv.NeedsChange(user) {
+		return ctx
+	}
+	return auth.NewScopeContext(ctx, auth.ScopePasswordChange)
+}
+
+func (a *LoginSrv) RefreshToken(ctx context.Context, refreshToken string) (*schema.LoginTokenInfo, error) {
+	tokenInfo, err := a.Auth.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		if err == auth.ErrInvalidToken {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return a.toLoginTokenInfo(tokenInfo), nil
+}
+
+func (a *LoginSrv) toLoginTokenInfo(tokenInfo auth.TokenInfo) *schema.LoginTokenInfo {
+	return &schema.LoginTokenInfo{
+		AccessToken:      tokenInfo.GetAccessToken(),
+		TokenType:        tokenInfo.GetTokenType(),
+		ExpiresAt:        tokenInfo.GetExpiresAt(),
+		RefreshToken:     tokenInfo.GetRefreshToken(),
+		RefreshExpiresAt: tokenInfo.GetRefreshExpiresAt(),
+	}
+}
+
+func (a *LoginSrv) GetJWKS(ctx context.Context) *jwtauth.JWKSet {
+	return a.KeySet.JWKS()
+}
+
 func (a *LoginSrv) DestroyToken(ctx context.Context, tokenString string) error {
 	err := a.Auth.DestroyToken(ctx, tokenString)
 	if err != nil {
@@ -111,24 +233,16 @@ func (a *LoginSrv) checkAndGetUser(ctx context.Context, userID uint64) (*schema.
 }
 
 func (a *LoginSrv) GetLoginInfo(ctx context.Context, userID uint64) (*schema.UserLoginInfo, error) {
-	if isRoot := schema.CheckIsRootUser(ctx, userID); isRoot {
-		root := schema.GetRootUser()
-		loginInfo := &schema.UserLoginInfo{
-			UserName: root.UserName,
-			RealName: root.RealName,
-		}
-		return loginInfo, nil
-	}
-
 	user, err := a.checkAndGetUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	info := &schema.UserLoginInfo{
-		UserID:   user.ID,
-		UserName: user.UserName,
-		RealName: user.RealName,
+		UserID:       user.ID,
+		UserName:     user.UserName,
+		RealName:     user.RealName,
+		IsSuperAdmin: user.IsSuperAdmin,
 	}
 
 	userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
@@ -153,8 +267,10 @@ func (a *LoginSrv) GetLoginInfo(ctx context.Context, userID uint64) (*schema.Use
 }
 
 func (a *LoginSrv) QueryUserMenuTree(ctx context.Context, userID uint64) (schema.MenuTrees, error) {
-	isRoot := schema.CheckIsRootUser(ctx, userID)
-	if isRoot {
+	isSuperAdmin, err := a.SuperAdminSrv.IsSuperAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if isSuperAdmin {
 		result, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{
 			Status: 1,
 		}, schema.MenuQueryOptions{
@@ -180,8 +296,16 @@ func (a *LoginSrv) QueryUserMenuTree(ctx context.Context, userID uint64) (schema
 		return nil, errors.ErrNoPerm
 	}
 
+	// 包含继承的上级角色的菜单
+	roleIDs, err := queryInheritedRoleIDs(ctx, a.RoleRepo, a.RoleParentRepo, userRoleResult.Data.ToRoleIDs())
+	if err != nil {
+		return nil, err
+	} else if len(roleIDs) == 0 {
+		return nil, errors.ErrNoPerm
+	}
+
 	roleMenuResult, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{
-		RoleIDs: userRoleResult.Data.ToRoleIDs(),
+		RoleIDs: roleIDs,
 	})
 	if err != nil {
 		return nil, err
@@ -229,17 +353,36 @@ func (a *LoginSrv) QueryUserMenuTree(ctx context.Context, userID uint64) (schema
 }
 
 func (a *LoginSrv) UpdatePassword(ctx context.Context, userID uint64, params schema.UpdatePasswordParam) error {
-	if schema.CheckIsRootUser(ctx, userID) {
-		return errors.New400Response("root用户不允许更新密码")
-	}
-
 	user, err := a.checkAndGetUser(ctx, userID)
 	if err != nil {
 		return err
-	} else if hash.SHA1String(params.OldPassword) != user.Password {
+	} else if ok, err := a.PasswordSrv.Verify(user.Password, params.OldPassword); err != nil {
+		return err
+	} else if !ok {
 		return errors.New400Response("旧密码不正确")
 	}
 
-	params.NewPassword = hash.SHA1String(params.NewPassword)
-	return a.UserRepo.UpdatePassword(ctx, userID, params.NewPassword)
+	err = a.PasswordSrv.Check(ctx, userID, user.Password, params.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	params.NewPassword, err = a.PasswordSrv.Hash(params.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.UserRepo.ChangePassword(ctx, userID, params.NewPassword, false)
+		if err != nil {
+			return err
+		}
+		return a.PasswordSrv.SaveHistory(ctx, userID, params.NewPassword)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 修改密码后，已签发的令牌全部失效
+	return errors.WithStack(a.Auth.RevokeUser(ctx, tokenSubject(user.ID, user.UserName)))
 }
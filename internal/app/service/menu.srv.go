@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/casbin/casbin/v2"
 	"github.com/google/wire"
 
 	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
 	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
 	"github.com/LyricTian/gin-admin/v8/pkg/errors"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/yaml"
@@ -18,10 +21,16 @@ import (
 var MenuSet = wire.NewSet(wire.Struct(new(MenuSrv), "*"))
 
 type MenuSrv struct {
+	Enforcer               *casbin.SyncedEnforcer
+	CasbinWatcher          *watcher.Watcher
 	TransRepo              *dao.TransRepo
 	MenuRepo               *dao.MenuRepo
 	MenuActionRepo         *dao.MenuActionRepo
 	MenuActionResourceRepo *dao.MenuActionResourceRepo
+	RoleRepo               *dao.RoleRepo
+	RoleMenuRepo           *dao.RoleMenuRepo
+	RoleParentRepo         *dao.RoleParentRepo
+	UserRepo               *dao.UserRepo
 }
 
 func (a *MenuSrv) InitData(ctx context.Context, dataFile string) error {
@@ -60,6 +69,7 @@ func (a *MenuSrv) createMenus(ctx context.Context, parentID uint64, list schema.
 	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
 		for _, item := range list {
 			sitem := schema.Menu{
+				Code:     item.Code,
 				Name:     item.Name,
 				Sequence: item.Sequence,
 				Icon:     item.Icon,
@@ -72,6 +82,9 @@ func (a *MenuSrv) createMenus(ctx context.Context, parentID uint64, list schema.
 			if v := item.IsShow; v > 0 {
 				sitem.IsShow = v
 			}
+			if v := item.Status; v > 0 {
+				sitem.Status = v
+			}
 
 			nsitem, err := a.Create(ctx, sitem)
 			if err != nil {
@@ -90,6 +103,443 @@ func (a *MenuSrv) createMenus(ctx context.Context, parentID uint64, list schema.
 	})
 }
 
+// Export 导出菜单树(菜单数据文件格式，可通过InitData或Sync导入)
+func (a *MenuSrv) Export(ctx context.Context) ([]byte, error) {
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+
+	// 同级菜单按排序值降序，排序值相同时按创建顺序(与导入时的顺序一致)
+	ctx = withPlatformTenant(ctx)
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{
+		OrderFields: schema.NewOrderFields(
+			schema.NewOrderField("sequence", schema.OrderByDESC),
+			schema.NewOrderField("id", schema.OrderByASC),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	actionResult, err := a.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{}, schema.MenuActionQueryOptions{
+		OrderFields: schema.NewOrderFields(schema.NewOrderField("id", schema.OrderByASC)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resourceResult, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{}, schema.MenuActionResourceQueryOptions{
+		OrderFields: schema.NewOrderFields(schema.NewOrderField("id", schema.OrderByASC)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	actionResult.Data.FillResources(resourceResult.Data.ToActionIDMap())
+	menuResult.Data.FillMenuAction(actionResult.Data.ToMenuIDMap())
+	for _, item := range menuResult.Data {
+		// 导入时默认显示并启用，仅导出隐藏或禁用的菜单
+		if item.IsShow == 1 {
+			item.IsShow = 0
+		}
+		if item.Status == 1 {
+			item.Status = 0
+		}
+	}
+
+	data, err := yaml.Marshal(menuResult.Data.ToTree())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append([]byte("---\n"), data...), nil
+}
+
+// 预览同步时回滚事务
+var errMenuSyncDryRun = errors.New("menu sync dry run")
+
+// Sync 按菜单数据文件同步菜单，所有变更在同一事务中执行(预览时回滚)
+// 菜单按编号或路由匹配(均未指定时按同一父级下的名称匹配)，动作按编号匹配，资源按请求方式及路径匹配
+func (a *MenuSrv) Sync(ctx context.Context, dataFile string, opts schema.MenuSyncOptions) (*schema.MenuSyncResult, error) {
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := a.readData(dataFile)
+	if err != nil {
+		return nil, err
+	} else if err := checkMenuTreeCodes(data, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+
+	// 仅同步平台的菜单动作
+	ctx = withPlatformTenant(ctx)
+	s := &menuSync{srv: a, opts: opts, matched: make(map[uint64]bool)}
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := s.load(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = s.syncMenus(ctx, 0, data)
+		if err != nil {
+			return err
+		}
+
+		if opts.Prune {
+			err = s.pruneMenus(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return errMenuSyncDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMenuSyncDryRun) {
+		return nil, err
+	}
+
+	if !opts.DryRun && len(s.changes) > 0 {
+		err := a.reloadPolicy()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema.MenuSyncResult{DryRun: opts.DryRun, Changes: s.changes}, nil
+}
+
+func checkMenuTreeCodes(list schema.MenuTrees, codes map[string]bool) error {
+	for _, item := range list {
+		if item.Code != "" {
+			if codes[item.Code] {
+				return errors.New400Response("菜单编号[%s]重复", item.Code)
+			}
+			codes[item.Code] = true
+		}
+
+		if item.Children != nil {
+			if err := checkMenuTreeCodes(*item.Children, codes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 菜单数据同步过程(与数据库中的菜单逐项比较并记录变更)
+type menuSync struct {
+	srv     *MenuSrv
+	opts    schema.MenuSyncOptions
+	menus   schema.Menus
+	actions map[uint64]schema.MenuActions
+	matched map[uint64]bool
+	changes []*schema.MenuSyncChange
+}
+
+func (s *menuSync) addChange(op, kind, menu, action, detail string) {
+	s.changes = append(s.changes, &schema.MenuSyncChange{
+		Op:     op,
+		Kind:   kind,
+		Menu:   menu,
+		Action: action,
+		Detail: detail,
+	})
+}
+
+// 变更记录中的菜单标识
+func menuSyncKey(code, router, name string) string {
+	if code != "" {
+		return code
+	} else if router != "" {
+		return router
+	}
+	return name
+}
+
+func (s *menuSync) load(ctx context.Context) error {
+	menuResult, err := s.srv.MenuRepo.Query(ctx, schema.MenuQueryParam{})
+	if err != nil {
+		return err
+	}
+
+	actionResult, err := s.srv.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{})
+	if err != nil {
+		return err
+	}
+
+	resourceResult, err := s.srv.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{})
+	if err != nil {
+		return err
+	}
+	actionResult.Data.FillResources(resourceResult.Data.ToActionIDMap())
+
+	s.menus = menuResult.Data
+	s.actions = actionResult.Data.ToMenuIDMap()
+	return nil
+}
+
+// 匹配数据库中的菜单：优先按编号，其次按路由，均未指定时按同一父级下的名称(已指定其他编号的菜单不参与匹配)
+func (s *menuSync) match(item *schema.MenuTree, parentID uint64) *schema.Menu {
+	if item.Code != "" {
+		for _, m := range s.menus {
+			if !s.matched[m.ID] && m.Code == item.Code {
+				return m
+			}
+		}
+	}
+
+	for _, m := range s.menus {
+		if s.matched[m.ID] || (m.Code != "" && m.Code != item.Code) {
+			continue
+		}
+
+		if item.Router != "" && m.Router == item.Router {
+			return m
+		} else if item.Router == "" && m.Router == "" && m.ParentID == parentID && m.Name == item.Name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *menuSync) syncMenus(ctx context.Context, parentID uint64, list schema.MenuTrees) error {
+	for _, item := range list {
+		id, err := s.syncMenu(ctx, parentID, item)
+		if err != nil {
+			return err
+		}
+
+		if item.Children != nil && len(*item.Children) > 0 {
+			err := s.syncMenus(ctx, id, *item.Children)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *menuSync) syncMenu(ctx context.Context, parentID uint64, item *schema.MenuTree) (uint64, error) {
+	key := menuSyncKey(item.Code, item.Router, item.Name)
+	m := s.match(item, parentID)
+	if m == nil {
+		s.addChange(schema.MenuSyncAdd, "menu", key, "", item.Name)
+		for _, aitem := range item.Actions {
+			s.addActionChanges(key, aitem)
+		}
+
+		sitem := schema.Menu{
+			Code:     item.Code,
+			Name:     item.Name,
+			Sequence: item.Sequence,
+			Icon:     item.Icon,
+			Router:   item.Router,
+			ParentID: parentID,
+			Status:   1,
+			IsShow:   1,
+			Actions:  item.Actions,
+		}
+		if v := item.IsShow; v > 0 {
+			sitem.IsShow = v
+		}
+		if v := item.Status; v > 0 {
+			sitem.Status = v
+		}
+
+		result, err := s.srv.Create(ctx, sitem)
+		if err != nil {
+			return 0, err
+		}
+		return result.ID, nil
+	}
+	s.matched[m.ID] = true
+
+	nitem := *m
+	var diffs []string
+	if item.Code != "" && item.Code != m.Code {
+		diffs = append(diffs, fmt.Sprintf("code: %q -> %q", m.Code, item.Code))
+		nitem.Code = item.Code
+	}
+	if item.Name != m.Name {
+		diffs = append(diffs, fmt.Sprintf("name: %q -> %q", m.Name, item.Name))
+		nitem.Name = item.Name
+	}
+	if item.Router != m.Router {
+		diffs = append(diffs, fmt.Sprintf("router: %q -> %q", m.Router, item.Router))
+		nitem.Router = item.Router
+	}
+	if item.Icon != m.Icon {
+		diffs = append(diffs, fmt.Sprintf("icon: %q -> %q", m.Icon, item.Icon))
+		nitem.Icon = item.Icon
+	}
+	if item.IsShow > 0 && item.IsShow != m.IsShow {
+		diffs = append(diffs, fmt.Sprintf("is_show: %d -> %d", m.IsShow, item.IsShow))
+		nitem.IsShow = item.IsShow
+	}
+	if item.Status > 0 && item.Status != m.Status {
+		diffs = append(diffs, fmt.Sprintf("status: %d -> %d", m.Status, item.Status))
+		nitem.Status = item.Status
+	}
+	if item.Sequence != m.Sequence {
+		diffs = append(diffs, fmt.Sprintf("sequence: %d -> %d", m.Sequence, item.Sequence))
+		nitem.Sequence = item.Sequence
+	}
+	if parentID != m.ParentID {
+		diffs = append(diffs, fmt.Sprintf("parent_id: %d -> %d", m.ParentID, parentID))
+		nitem.ParentID = parentID
+	}
+
+	if len(diffs) > 0 {
+		s.addChange(schema.MenuSyncUpdate, "menu", key, "", strings.Join(diffs, ", "))
+		err := s.updateMenu(ctx, m, nitem)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err := s.syncActions(ctx, m.ID, key, s.actions[m.ID], item.Actions)
+	if err != nil {
+		return 0, err
+	}
+	return m.ID, nil
+}
+
+func (s *menuSync) updateMenu(ctx context.Context, oldItem *schema.Menu, item schema.Menu) error {
+	if item.ParentID == oldItem.ParentID {
+		return s.srv.MenuRepo.UpdateDefinition(ctx, item.ID, item)
+	}
+
+	// 上级菜单可能已在本次同步中移动，重新获取父级路径
+	current, err := s.srv.MenuRepo.Get(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+
+	parentPath, err := getTreeParentPath(ctx, s.srv.treeStore(), item.ID, item.ParentID)
+	if err != nil {
+		return err
+	}
+	item.ParentPath = parentPath
+
+	err = s.srv.MenuRepo.UpdateDefinition(ctx, item.ID, item)
+	if err != nil {
+		return err
+	}
+	return updateTreeChildParentPath(ctx, s.srv.treeStore(), item.ID, current.ParentPath, item.ParentPath)
+}
+
+func (s *menuSync) addActionChanges(key string, item *schema.MenuAction) {
+	s.addChange(schema.MenuSyncAdd, "action", key, item.Code, item.Name)
+	for _, ritem := range item.Resources {
+		s.addChange(schema.MenuSyncAdd, "resource", key, item.Code, ritem.Method+" "+ritem.Path)
+	}
+}
+
+func (s *menuSync) syncActions(ctx context.Context, menuID uint64, key string, oldItems, newItems schema.MenuActions) error {
+	mOldItems := oldItems.ToMap()
+	for _, item := range newItems {
+		oitem, ok := mOldItems[item.Code]
+		if !ok {
+			s.addActionChanges(key, item)
+			err := s.srv.createActions(ctx, menuID, schema.MenuActions{item})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if item.Name != oitem.Name {
+			s.addChange(schema.MenuSyncUpdate, "action", key, item.Code, fmt.Sprintf("name: %q -> %q", oitem.Name, item.Name))
+			nitem := *oitem
+			nitem.Name = item.Name
+			err := s.srv.MenuActionRepo.Update(ctx, oitem.ID, nitem)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := s.syncResources(ctx, key, oitem, item.Resources)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.opts.Prune {
+		return nil
+	}
+
+	mNewItems := newItems.ToMap()
+	for _, oitem := range oldItems {
+		if _, ok := mNewItems[oitem.Code]; ok {
+			continue
+		}
+
+		s.addChange(schema.MenuSyncDelete, "action", key, oitem.Code, oitem.Name)
+		err := s.srv.MenuActionResourceRepo.DeleteByActionID(ctx, oitem.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.srv.MenuActionRepo.Delete(ctx, oitem.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *menuSync) syncResources(ctx context.Context, key string, action *schema.MenuAction, newItems schema.MenuActionResources) error {
+	mOldItems := action.Resources.ToMap()
+	for _, item := range newItems {
+		if _, ok := mOldItems[item.Method+item.Path]; ok {
+			continue
+		}
+
+		s.addChange(schema.MenuSyncAdd, "resource", key, action.Code, item.Method+" "+item.Path)
+		item.ID = snowflake.MustID()
+		item.ActionID = action.ID
+		err := s.srv.MenuActionResourceRepo.Create(ctx, *item)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.opts.Prune {
+		return nil
+	}
+
+	mNewItems := newItems.ToMap()
+	for _, item := range action.Resources {
+		if _, ok := mNewItems[item.Method+item.Path]; ok {
+			continue
+		}
+
+		s.addChange(schema.MenuSyncDelete, "resource", key, action.Code, item.Method+" "+item.Path)
+		err := s.srv.MenuActionResourceRepo.Delete(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 删除数据文件中不存在的菜单(包括通过接口添加的菜单)
+func (s *menuSync) pruneMenus(ctx context.Context) error {
+	var pruned schema.Menus
+	for _, m := range s.menus {
+		if s.matched[m.ID] {
+			continue
+		}
+
+		s.addChange(schema.MenuSyncDelete, "menu", menuSyncKey(m.Code, m.Router, m.Name), "", m.Name)
+		pruned = append(pruned, m)
+	}
+	return s.srv.deleteMenus(ctx, pruned)
+}
+
 func (a *MenuSrv) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
 	menuActionResult, err := a.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{})
 	if err != nil {
@@ -159,12 +609,38 @@ func (a *MenuSrv) checkName(ctx context.Context, item schema.Menu) error {
 	return nil
 }
 
+func (a *MenuSrv) checkCode(ctx context.Context, code string) error {
+	if code == "" {
+		return nil
+	}
+
+	result, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{
+		PaginationParam: schema.PaginationParam{
+			OnlyCount: true,
+		},
+		Code: code,
+	})
+	if err != nil {
+		return err
+	} else if result.PageResult.Total > 0 {
+		return errors.New400Response("编号已经存在")
+	}
+	return nil
+}
+
 func (a *MenuSrv) Create(ctx context.Context, item schema.Menu) (*schema.IDResult, error) {
+	// 菜单为所有租户共享，仅平台用户可以修改
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+
 	if err := a.checkName(ctx, item); err != nil {
 		return nil, err
+	} else if err := a.checkCode(ctx, item.Code); err != nil {
+		return nil, err
 	}
 
-	parentPath, err := a.getParentPath(ctx, item.ParentID)
+	parentPath, err := getTreeParentPath(ctx, a.treeStore(), 0, item.ParentID)
 	if err != nil {
 		return nil, err
 	}
@@ -208,30 +684,47 @@ func (a *MenuSrv) createActions(ctx context.Context, menuID uint64, items schema
 	return nil
 }
 
-func (a *MenuSrv) getParentPath(ctx context.Context, parentID uint64) (string, error) {
-	if parentID == 0 {
-		return "", nil
+// 菜单树节点的读写
+type menuTreeStore struct {
+	repo *dao.MenuRepo
+}
+
+func (s menuTreeStore) getNode(ctx context.Context, id uint64) (*treeNode, error) {
+	item, err := s.repo.Get(ctx, id)
+	if err != nil || item == nil {
+		return nil, err
 	}
+	return &treeNode{ID: item.ID, ParentPath: item.ParentPath}, nil
+}
 
-	pitem, err := a.MenuRepo.Get(ctx, parentID)
+func (s menuTreeStore) queryByPrefixParentPath(ctx context.Context, prefix string) ([]*treeNode, error) {
+	result, err := s.repo.Query(ctx, schema.MenuQueryParam{
+		PrefixParentPath: prefix,
+	})
 	if err != nil {
-		return "", err
-	} else if pitem == nil {
-		return "", errors.ErrInvalidParent
+		return nil, err
 	}
 
-	return a.joinParentPath(pitem.ParentPath, pitem.ID), nil
+	list := make([]*treeNode, len(result.Data))
+	for i, item := range result.Data {
+		list[i] = &treeNode{ID: item.ID, ParentPath: item.ParentPath}
+	}
+	return list, nil
 }
 
-func (a *MenuSrv) joinParentPath(parent string, id uint64) string {
-	if parent != "" {
-		parent += "/"
-	}
+func (s menuTreeStore) updateParentPath(ctx context.Context, id uint64, parentPath string) error {
+	return s.repo.UpdateParentPath(ctx, id, parentPath)
+}
 
-	return fmt.Sprintf("%s%d", parent, id)
+func (a *MenuSrv) treeStore() treeStore {
+	return menuTreeStore{repo: a.MenuRepo}
 }
 
 func (a *MenuSrv) Update(ctx context.Context, id uint64, item schema.Menu) error {
+	if err := checkPlatform(ctx); err != nil {
+		return err
+	}
+
 	if id == item.ParentID {
 		return errors.ErrInvalidParent
 	}
@@ -247,12 +740,18 @@ func (a *MenuSrv) Update(ctx context.Context, id uint64, item schema.Menu) error
 		}
 	}
 
+	if oldItem.Code != item.Code {
+		if err := a.checkCode(ctx, item.Code); err != nil {
+			return err
+		}
+	}
+
 	item.ID = oldItem.ID
 	item.Creator = oldItem.Creator
 	item.CreatedAt = oldItem.CreatedAt
 
 	if oldItem.ParentID != item.ParentID {
-		parentPath, err := a.getParentPath(ctx, item.ParentID)
+		parentPath, err := getTreeParentPath(ctx, a.treeStore(), id, item.ParentID)
 		if err != nil {
 			return err
 		}
@@ -261,19 +760,195 @@ func (a *MenuSrv) Update(ctx context.Context, id uint64, item schema.Menu) error
 		item.ParentPath = oldItem.ParentPath
 	}
 
-	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
 		err := a.updateActions(ctx, id, oldItem.Actions, item.Actions)
 		if err != nil {
 			return err
 		}
 
-		err = a.updateChildParentPath(ctx, *oldItem, item)
+		err = updateTreeChildParentPath(ctx, a.treeStore(), id, oldItem.ParentPath, item.ParentPath)
 		if err != nil {
 			return err
 		}
 
 		return a.MenuRepo.Update(ctx, id, item)
 	})
+	if err != nil {
+		return err
+	}
+
+	return a.reloadPolicy()
+}
+
+// Move 移动菜单到新的父级下(包含所有下级菜单)
+func (a *MenuSrv) Move(ctx context.Context, id uint64, params schema.MenuMoveParam) error {
+	if err := checkPlatform(ctx); err != nil {
+		return err
+	}
+
+	oldItem, err := a.MenuRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	parentPath := oldItem.ParentPath
+	if oldItem.ParentID != params.ParentID {
+		parentPath, err = getTreeParentPath(ctx, a.treeStore(), id, params.ParentID)
+		if err != nil {
+			return err
+		}
+
+		err = a.checkName(ctx, schema.Menu{ParentID: params.ParentID, Name: oldItem.Name})
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		if oldItem.ParentID != params.ParentID {
+			err := updateTreeChildParentPath(ctx, a.treeStore(), id, oldItem.ParentPath, parentPath)
+			if err != nil {
+				return err
+			}
+
+			err = a.MenuRepo.Move(ctx, id, params.ParentID, parentPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		if v := params.Sequence; v != nil && *v != oldItem.Sequence {
+			return a.MenuRepo.UpdateSequence(ctx, id, *v)
+		}
+		return nil
+	})
+}
+
+// Sort 批量调整菜单的父级及排序值(拖拽排序的结果)，重新计算受影响的菜单及其下级的父级路径，在同一事务中执行
+func (a *MenuSrv) Sort(ctx context.Context, items []*schema.MenuSortItem) error {
+	if err := checkPlatform(ctx); err != nil {
+		return err
+	} else if len(items) == 0 {
+		return nil
+	}
+
+	result, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{})
+	if err != nil {
+		return err
+	}
+	mMenus := result.Data.ToMap()
+
+	// 调整后的父级
+	parents := make(map[uint64]uint64, len(mMenus))
+	for id, item := range mMenus {
+		parents[id] = item.ParentID
+	}
+
+	mItems := make(map[uint64]*schema.MenuSortItem, len(items))
+	for _, item := range items {
+		if _, ok := mMenus[item.ID]; !ok {
+			return errors.ErrNotFound
+		} else if _, ok := mItems[item.ID]; ok {
+			return errors.New400Response("菜单[%d]重复", item.ID)
+		} else if _, ok := mMenus[item.ParentID]; item.ParentID != 0 && !ok {
+			return errors.ErrInvalidParent
+		}
+		mItems[item.ID] = item
+		parents[item.ID] = item.ParentID
+	}
+
+	// 调整的菜单及其原有的下级需要重新计算父级路径，其他菜单保持不变
+	paths := make(map[uint64]string, len(mMenus))
+	var affected []uint64
+	for id, item := range mMenus {
+		if _, ok := mItems[id]; ok {
+			affected = append(affected, id)
+			continue
+		}
+
+		isChild := false
+		for _, sitem := range items {
+			if hasParentPathID(item.ParentPath, sitem.ID) {
+				isChild = true
+				break
+			}
+		}
+		if isChild {
+			affected = append(affected, id)
+			continue
+		}
+		paths[id] = item.ParentPath
+	}
+
+	// 父级为自身或下级时形成循环，层级超过菜单数量
+	var getParentPath func(id uint64, depth int) (string, error)
+	getParentPath = func(id uint64, depth int) (string, error) {
+		if v, ok := paths[id]; ok {
+			return v, nil
+		} else if depth > len(mMenus) {
+			return "", errors.ErrInvalidParent
+		}
+
+		var parentPath string
+		if pid := parents[id]; pid != 0 {
+			ppath, err := getParentPath(pid, depth+1)
+			if err != nil {
+				return "", err
+			}
+			parentPath = joinParentPath(ppath, pid)
+		}
+		paths[id] = parentPath
+		return parentPath, nil
+	}
+
+	for _, id := range affected {
+		if _, err := getParentPath(id, 0); err != nil {
+			return err
+		}
+	}
+
+	// 同一父级下的名称不能重复
+	for _, item := range items {
+		menu := mMenus[item.ID]
+		if menu.ParentID == item.ParentID {
+			continue
+		}
+
+		for id, m := range mMenus {
+			if id != item.ID && parents[id] == item.ParentID && m.Name == menu.Name {
+				return errors.New400Response("名称[%s]不能重复", menu.Name)
+			}
+		}
+	}
+
+	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		for _, id := range affected {
+			menu := mMenus[id]
+			if parents[id] != menu.ParentID {
+				err := a.MenuRepo.Move(ctx, id, parents[id], paths[id])
+				if err != nil {
+					return err
+				}
+			} else if paths[id] != menu.ParentPath {
+				err := a.MenuRepo.UpdateParentPath(ctx, id, paths[id])
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, item := range items {
+			if item.Sequence != mMenus[item.ID].Sequence {
+				err := a.MenuRepo.UpdateSequence(ctx, item.ID, item.Sequence)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
 }
 
 func (a *MenuSrv) updateActions(ctx context.Context, menuID uint64, oldItems, newItems schema.MenuActions) error {
@@ -365,30 +1040,11 @@ func (a *MenuSrv) compareResources(ctx context.Context, oldResources, newResourc
 	return
 }
 
-func (a *MenuSrv) updateChildParentPath(ctx context.Context, oldItem, newItem schema.Menu) error {
-	if oldItem.ParentID == newItem.ParentID {
-		return nil
-	}
-
-	opath := a.joinParentPath(oldItem.ParentPath, oldItem.ID)
-	result, err := a.MenuRepo.Query(contextx.NewNoTrans(ctx), schema.MenuQueryParam{
-		PrefixParentPath: opath,
-	})
-	if err != nil {
+func (a *MenuSrv) Delete(ctx context.Context, id uint64) error {
+	if err := checkPlatform(ctx); err != nil {
 		return err
 	}
 
-	npath := a.joinParentPath(newItem.ParentPath, newItem.ID)
-	for _, menu := range result.Data {
-		err = a.MenuRepo.UpdateParentPath(ctx, menu.ID, npath+menu.ParentPath[len(opath):])
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (a *MenuSrv) Delete(ctx context.Context, id uint64) error {
 	oldItem, err := a.MenuRepo.Get(ctx, id)
 	if err != nil {
 		return err
@@ -403,25 +1059,201 @@ func (a *MenuSrv) Delete(ctx context.Context, id uint64) error {
 	if err != nil {
 		return err
 	} else if result.PageResult.Total > 0 {
-		return errors.New400Response("forbid delete")
+		return errors.New400Response("不允许删除存在下级菜单的菜单(可以级联删除)")
 	}
 
-	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
-		err = a.MenuActionResourceRepo.DeleteByMenuID(ctx, id)
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		return a.deleteMenus(ctx, schema.Menus{oldItem})
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reloadPolicy()
+}
+
+// 查询菜单及其全部下级菜单
+func (a *MenuSrv) querySubtree(ctx context.Context, id uint64) (schema.Menus, error) {
+	item, err := a.MenuRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	path := joinParentPath(item.ParentPath, item.ID)
+	result, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{
+		PrefixParentPath: path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := schema.Menus{item}
+	for _, child := range result.Data {
+		// 前缀匹配可能包含ID前缀相同的其他菜单
+		if isSubParentPath(child.ParentPath, path) {
+			list = append(list, child)
+		}
+	}
+	return list, nil
+}
+
+// DeleteImpact 预览级联删除菜单(包含下级菜单)的影响范围，失去访问权限的用户可能很多，仅返回总数及分页的用户
+func (a *MenuSrv) DeleteImpact(ctx context.Context, id uint64, params schema.MenuDeleteImpactParam) (*schema.MenuDeleteImpact, error) {
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+
+	menus, err := a.querySubtree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	menuIDs := make([]uint64, len(menus))
+	for i, item := range menus {
+		menuIDs[i] = item.ID
+	}
+
+	// 菜单为所有租户共享，按所有租户统计角色及用户
+	ctx = contextx.NewNoDataScope(contextx.NewNoTenant(ctx))
+	actionResult, err := a.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{
+		MenuIDs: menuIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roleMenuResult, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{
+		MenuIDs: menuIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pp := params.PaginationParam
+	if !pp.OnlyCount {
+		pp.Pagination = true
+	}
+	impact := &schema.MenuDeleteImpact{
+		Menus:          menus,
+		Actions:        actionResult.Data,
+		Roles:          schema.Roles{},
+		Users:          schema.Users{},
+		UserPagination: &schema.PaginationResult{},
+	}
+	if !pp.OnlyCount {
+		impact.UserPagination.Current = pp.GetCurrent()
+		impact.UserPagination.PageSize = pp.GetPageSize()
+	}
+
+	var roleIDs []uint64
+	mRoleIDs := make(map[uint64]struct{})
+	for _, item := range roleMenuResult.Data {
+		if _, ok := mRoleIDs[item.RoleID]; !ok {
+			mRoleIDs[item.RoleID] = struct{}{}
+			roleIDs = append(roleIDs, item.RoleID)
+		}
+	}
+	if len(roleIDs) == 0 {
+		return impact, nil
+	}
+
+	roleResult, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{
+		IDs: roleIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	impact.Roles = roleResult.Data
+
+	inheritingIDs, err := queryInheritingRoleIDs(ctx, a.RoleRepo, a.RoleParentRepo, roleIDs)
+	if err != nil {
+		return nil, err
+	} else if len(inheritingIDs) == 0 {
+		return impact, nil
+	}
+
+	userResult, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+		PaginationParam: pp,
+		RoleIDs:         inheritingIDs,
+		Status:          1,
+	}, schema.UserQueryOptions{
+		OrderFields: schema.NewOrderFields(schema.NewOrderField("id", schema.OrderByASC)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	impact.UserPagination = userResult.PageResult
+	for _, item := range userResult.Data {
+		impact.Users = append(impact.Users, item.CleanSecure())
+	}
+	return impact, nil
+}
+
+// DeleteCascade 删除菜单及其全部下级菜单、动作、资源和角色授权，返回影响范围
+func (a *MenuSrv) DeleteCascade(ctx context.Context, id uint64, params schema.MenuDeleteImpactParam) (*schema.MenuDeleteImpact, error) {
+	var impact *schema.MenuDeleteImpact
+	err := a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		var err error
+		impact, err = a.DeleteImpact(ctx, id, params)
 		if err != nil {
 			return err
 		}
+		return a.deleteMenus(ctx, impact.Menus)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.reloadPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
 
-		err := a.MenuActionRepo.DeleteByMenuID(ctx, id)
+// 删除菜单及其动作、资源，菜单为所有租户共享，同时删除所有租户角色的授权
+func (a *MenuSrv) deleteMenus(ctx context.Context, menus schema.Menus) error {
+	ctx = contextx.NewNoTenant(ctx)
+	for _, item := range menus {
+		err := a.MenuActionResourceRepo.DeleteByMenuID(ctx, item.ID)
 		if err != nil {
 			return err
 		}
 
-		return a.MenuRepo.Delete(ctx, id)
-	})
+		err = a.MenuActionRepo.DeleteByMenuID(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+
+		err = a.RoleMenuRepo.DeleteByMenuID(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+
+		err = a.MenuRepo.Delete(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 菜单资源变更会影响所有关联角色的策略，重新加载本实例的策略并通知其他实例
+func (a *MenuSrv) reloadPolicy() error {
+	err := a.Enforcer.LoadPolicy()
+	if err != nil {
+		return err
+	}
+	return a.CasbinWatcher.Update()
 }
 
 func (a *MenuSrv) UpdateStatus(ctx context.Context, id uint64, status int) error {
+	if err := checkPlatform(ctx); err != nil {
+		return err
+	}
+
 	oldItem, err := a.MenuRepo.Get(ctx, id)
 	if err != nil {
 		return err
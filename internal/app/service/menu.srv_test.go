@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
+	"github.com/LyricTian/gin-admin/v8/internal/app/module/adapter"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+)
+
+const testMenuData = `
+- name: 系统管理
+  sequence: 7
+  children:
+    - name: 用户管理
+      router: "/system/user"
+      sequence: 8
+      actions:
+        - code: query
+          name: 查询
+          resources:
+            - method: GET
+              path: "/api/v1/users"
+        - code: del
+          name: 删除
+          resources:
+            - method: DELETE
+              path: "/api/v1/users/:id"
+    - name: 部门管理
+      router: "/system/dept"
+      sequence: 7
+`
+
+// 系统管理指定编号，用户管理修改名称及排序、查询动作增加资源、删除动作移除，新增租户管理，部门管理移除
+const testMenuSyncData = `
+- name: 系统管理
+  code: system
+  sequence: 7
+  children:
+    - name: 用户
+      router: "/system/user"
+      sequence: 9
+      actions:
+        - code: query
+          name: 查询
+          resources:
+            - method: GET
+              path: "/api/v1/users"
+            - method: GET
+              path: "/api/v1/users/:id"
+    - name: 租户管理
+      router: "/system/tenant"
+      sequence: 6
+      actions:
+        - code: query
+          name: 查询
+          resources:
+            - method: GET
+              path: "/api/v1/tenants"
+`
+
+func newTestMenuSrv(t *testing.T, name string) *MenuSrv {
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+	assert.Nil(t, util.RegisterTenantCallbacks(db))
+
+	a := &MenuSrv{
+		CasbinWatcher:          &watcher.Watcher{},
+		TransRepo:              &dao.TransRepo{DB: db},
+		MenuRepo:               &dao.MenuRepo{DB: db},
+		MenuActionRepo:         &dao.MenuActionRepo{DB: db},
+		MenuActionResourceRepo: &dao.MenuActionResourceRepo{DB: db},
+		RoleRepo:               &dao.RoleRepo{DB: db},
+		RoleMenuRepo:           &dao.RoleMenuRepo{DB: db},
+		RoleParentRepo:         &dao.RoleParentRepo{DB: db},
+		UserRepo:               &dao.UserRepo{DB: db},
+	}
+	a.Enforcer, err = casbin.NewSyncedEnforcer("../../../configs/model.conf", &adapter.CasbinAdapter{
+		RoleRepo:         a.RoleRepo,
+		RoleMenuRepo:     a.RoleMenuRepo,
+		RoleParentRepo:   a.RoleParentRepo,
+		MenuResourceRepo: a.MenuActionResourceRepo,
+		UserRepo:         a.UserRepo,
+		UserRoleRepo:     &dao.UserRoleRepo{DB: db},
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return a
+}
+
+func writeTestMenuData(t *testing.T, data string) string {
+	name := filepath.Join(t.TempDir(), "menu.yaml")
+	if !assert.Nil(t, ioutil.WriteFile(name, []byte(data), 0644)) {
+		t.FailNow()
+	}
+	return name
+}
+
+func TestMenuSync(t *testing.T) {
+	a := newTestMenuSrv(t, "menusync")
+	ctx := context.Background()
+	assert.Nil(t, a.InitData(ctx, writeTestMenuData(t, testMenuData)))
+	assert.Nil(t, a.MenuRepo.Create(ctx, schema.Menu{ID: 1, Name: "custom", Router: "/custom", Status: 1, IsShow: 1}))
+
+	dataFile := writeTestMenuData(t, testMenuSyncData)
+	countMenus := func() int {
+		result, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{})
+		assert.Nil(t, err)
+		return len(result.Data)
+	}
+
+	// 预览不修改数据
+	result, err := a.Sync(ctx, dataFile, schema.MenuSyncOptions{Prune: true, DryRun: true})
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.NotEmpty(t, result.Changes)
+	assert.Equal(t, 4, countMenus())
+
+	// 不删除时保留数据文件中不存在的菜单、动作及资源
+	result, err = a.Sync(ctx, dataFile, schema.MenuSyncOptions{})
+	assert.Nil(t, err)
+	changes := make([]string, len(result.Changes))
+	for i, item := range result.Changes {
+		changes[i] = item.String()
+	}
+	assert.Equal(t, []string{
+		`~ menu [system]: code: "" -> "system"`,
+		`~ menu [/system/user]: name: "用户管理" -> "用户", sequence: 8 -> 9`,
+		`+ resource [/system/user] query: GET /api/v1/users/:id`,
+		`+ menu [/system/tenant]: 租户管理`,
+		`+ action [/system/tenant] query: 查询`,
+		`+ resource [/system/tenant] query: GET /api/v1/tenants`,
+	}, changes)
+	assert.Equal(t, 5, countMenus())
+
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{Code: "system"})
+	assert.Nil(t, err)
+	assert.Len(t, menuResult.Data, 1)
+
+	menuResult, err = a.MenuRepo.Query(ctx, schema.MenuQueryParam{Routers: []string{"/system/user"}})
+	assert.Nil(t, err)
+	if assert.Len(t, menuResult.Data, 1) {
+		actions, err := a.QueryActions(ctx, menuResult.Data[0].ID)
+		assert.Nil(t, err)
+		assert.Len(t, actions, 2)
+		assert.Len(t, actions.ToMap()["query"].Resources, 2)
+	}
+
+	// 再次同步没有变更
+	result, err = a.Sync(ctx, dataFile, schema.MenuSyncOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, result.Changes)
+
+	result, err = a.Sync(ctx, dataFile, schema.MenuSyncOptions{Prune: true})
+	assert.Nil(t, err)
+	changes = make([]string, len(result.Changes))
+	for i, item := range result.Changes {
+		changes[i] = item.String()
+	}
+	assert.ElementsMatch(t, []string{
+		`- action [/system/user] del: 删除`,
+		`- menu [/system/dept]: 部门管理`,
+		`- menu [/custom]: custom`,
+	}, changes)
+	assert.Equal(t, 3, countMenus())
+
+	result, err = a.Sync(ctx, dataFile, schema.MenuSyncOptions{Prune: true})
+	assert.Nil(t, err)
+	assert.Empty(t, result.Changes)
+}
+
+const testMenuMoveData = `
+- name: 系统管理
+  code: system
+  sequence: 7
+  children:
+    - name: 用户管理
+      router: "/system/user"
+      sequence: 8
+      children:
+        - name: 用户详情
+          router: "/system/user/detail"
+          sequence: 8
+          children:
+            - name: 登录日志
+              router: "/system/user/detail/log"
+              sequence: 8
+- name: 审计
+  code: audit
+  sequence: 6
+`
+
+// 用户管理移到审计下，其下级用户详情移到系统管理下
+const testMenuMoveSyncData = `
+- name: 审计
+  code: audit
+  sequence: 6
+  children:
+    - name: 用户管理
+      router: "/system/user"
+      sequence: 8
+- name: 系统管理
+  code: system
+  sequence: 7
+  children:
+    - name: 用户详情
+      router: "/system/user/detail"
+      sequence: 8
+      children:
+        - name: 登录日志
+          router: "/system/user/detail/log"
+          sequence: 8
+`
+
+func TestMenuSyncMove(t *testing.T) {
+	a := newTestMenuSrv(t, "menusyncmove")
+	ctx := context.Background()
+	assert.Nil(t, a.InitData(ctx, writeTestMenuData(t, testMenuMoveData)))
+
+	result, err := a.Sync(ctx, writeTestMenuData(t, testMenuMoveSyncData), schema.MenuSyncOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, result.Changes, 2)
+
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{})
+	assert.Nil(t, err)
+	mMenus := make(map[string]*schema.Menu)
+	for _, item := range menuResult.Data {
+		mMenus[menuSyncKey(item.Code, item.Router, item.Name)] = item
+	}
+
+	system, audit := mMenus["system"], mMenus["audit"]
+	user, detail, log := mMenus["/system/user"], mMenus["/system/user/detail"], mMenus["/system/user/detail/log"]
+	assert.Equal(t, audit.ID, user.ParentID)
+	assert.Equal(t, joinParentPath("", audit.ID), user.ParentPath)
+	assert.Equal(t, system.ID, detail.ParentID)
+	assert.Equal(t, joinParentPath("", system.ID), detail.ParentPath)
+	assert.Equal(t, detail.ID, log.ParentID)
+	assert.Equal(t, joinParentPath(detail.ParentPath, detail.ID), log.ParentPath)
+}
+
+func TestMenuExport(t *testing.T) {
+	a := newTestMenuSrv(t, "menuexport")
+	ctx := context.Background()
+	assert.Nil(t, a.InitData(ctx, "../../../configs/menu.yaml"))
+
+	// 导出的菜单树与数据文件一致
+	data, err := a.Export(ctx)
+	assert.Nil(t, err)
+	dataFile := writeTestMenuData(t, string(data))
+	source, err := a.readData("../../../configs/menu.yaml")
+	assert.Nil(t, err)
+	exported, err := a.readData(dataFile)
+	assert.Nil(t, err)
+	assert.Equal(t, source, exported)
+
+	// 隐藏及禁用的菜单经过导入后保持不变
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{Routers: []string{"/system/dept"}})
+	assert.Nil(t, err)
+	if assert.Len(t, menuResult.Data, 1) {
+		item := menuResult.Data[0]
+		item.IsShow = 2
+		assert.Nil(t, a.MenuRepo.UpdateDefinition(ctx, item.ID, *item))
+	}
+	menuResult, err = a.MenuRepo.Query(ctx, schema.MenuQueryParam{Routers: []string{"/system/user"}})
+	assert.Nil(t, err)
+	if assert.Len(t, menuResult.Data, 1) {
+		assert.Nil(t, a.UpdateStatus(ctx, menuResult.Data[0].ID, 2))
+	}
+	data, err = a.Export(ctx)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "is_show: 2")
+	assert.Contains(t, string(data), "status: 2")
+	assert.NotContains(t, string(data), "status: 1")
+	dataFile = writeTestMenuData(t, string(data))
+
+	b := newTestMenuSrv(t, "menuexport2")
+	assert.Nil(t, b.InitData(ctx, dataFile))
+	data2, err := b.Export(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), string(data2))
+
+	menuResult, err = b.MenuRepo.Query(ctx, schema.MenuQueryParam{Routers: []string{"/system/user"}})
+	assert.Nil(t, err)
+	if assert.Len(t, menuResult.Data, 1) {
+		assert.Equal(t, 2, menuResult.Data[0].Status)
+	}
+
+	// 同步时更新菜单状态
+	c := newTestMenuSrv(t, "menuexport3")
+	assert.Nil(t, c.InitData(ctx, "../../../configs/menu.yaml"))
+	result, err := c.Sync(ctx, dataFile, schema.MenuSyncOptions{})
+	assert.Nil(t, err)
+	changes := make([]string, len(result.Changes))
+	for i, item := range result.Changes {
+		changes[i] = item.String()
+	}
+	assert.Equal(t, []string{
+		`~ menu [/system/user]: status: 1 -> 2`,
+		`~ menu [/system/dept]: is_show: 1 -> 2`,
+	}, changes)
+}
+
+func TestMenuDeleteCascade(t *testing.T) {
+	a := newTestMenuSrv(t, "menudelete")
+	ctx := context.Background()
+	assert.Nil(t, a.InitData(ctx, writeTestMenuData(t, testMenuData)))
+
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{Name: "系统管理"})
+	assert.Nil(t, err)
+	if !assert.Len(t, menuResult.Data, 1) {
+		return
+	}
+	rootID := menuResult.Data[0].ID
+
+	menuResult, err = a.MenuRepo.Query(ctx, schema.MenuQueryParam{Routers: []string{"/system/user"}})
+	assert.Nil(t, err)
+	if !assert.Len(t, menuResult.Data, 1) {
+		return
+	}
+	userMenuID := menuResult.Data[0].ID
+	actions, err := a.QueryActions(ctx, userMenuID)
+	assert.Nil(t, err)
+	mActions := actions.ToMap()
+
+	// 角色2继承角色1(平台)，角色3属于租户5，用户12拥有角色2，用户13拥有角色3，用户14没有角色
+	pctx := contextx.NewTenantID(ctx, 0)
+	tctx := contextx.NewTenantID(ctx, 5)
+	userRoleRepo := &dao.UserRoleRepo{DB: a.UserRepo.DB}
+	assert.Nil(t, a.RoleRepo.Create(pctx, schema.Role{ID: 1, Name: "viewer", Status: 1}))
+	assert.Nil(t, a.RoleRepo.Create(pctx, schema.Role{ID: 2, Name: "auditor", Status: 1}))
+	assert.Nil(t, a.RoleRepo.Create(tctx, schema.Role{ID: 3, Name: "manager", Status: 1}))
+	assert.Nil(t, a.RoleParentRepo.Create(pctx, schema.RoleParent{ID: 1, RoleID: 2, ParentID: 1}))
+	assert.Nil(t, a.RoleMenuRepo.Create(pctx, schema.RoleMenu{ID: 1, RoleID: 1, MenuID: userMenuID, ActionID: mActions["query"].ID}))
+	assert.Nil(t, a.RoleMenuRepo.Create(tctx, schema.RoleMenu{ID: 2, RoleID: 3, MenuID: userMenuID, ActionID: mActions["del"].ID}))
+	assert.Nil(t, a.UserRepo.Create(pctx, schema.User{ID: 12, UserName: "u12", Status: 1}))
+	assert.Nil(t, a.UserRepo.Create(tctx, schema.User{ID: 13, UserName: "u13", Status: 1}))
+	assert.Nil(t, a.UserRepo.Create(pctx, schema.User{ID: 14, UserName: "u14", Status: 1}))
+	assert.Nil(t, userRoleRepo.Create(pctx, schema.UserRole{ID: 1, UserID: 12, RoleID: 2}))
+	assert.Nil(t, userRoleRepo.Create(tctx, schema.UserRole{ID: 2, UserID: 13, RoleID: 3}))
+
+	assert.Nil(t, a.Enforcer.LoadPolicy())
+	ok, err := a.Enforcer.Enforce("12", "0", "/api/v1/users", "GET")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// 存在下级菜单时不允许直接删除
+	assert.NotNil(t, a.Delete(pctx, rootID))
+
+	// 租户不能删除菜单
+	_, err = a.DeleteImpact(tctx, rootID, schema.MenuDeleteImpactParam{})
+	assert.NotNil(t, err)
+
+	impact, err := a.DeleteImpact(pctx, rootID, schema.MenuDeleteImpactParam{})
+	assert.Nil(t, err)
+	assert.Len(t, impact.Menus, 3)
+	assert.Len(t, impact.Actions, 2)
+	roleIDs := make([]uint64, len(impact.Roles))
+	for i, item := range impact.Roles {
+		roleIDs[i] = item.ID
+	}
+	assert.ElementsMatch(t, []uint64{1, 3}, roleIDs)
+	assert.ElementsMatch(t, []uint64{12, 13}, impact.Users.ToIDs())
+	for _, item := range impact.Users {
+		assert.Empty(t, item.Password)
+	}
+	assert.Equal(t, int64(2), impact.UserPagination.Total)
+
+	// 失去访问权限的用户分页返回，仅统计数量时不返回用户
+	impact, err = a.DeleteImpact(pctx, rootID, schema.MenuDeleteImpactParam{
+		PaginationParam: schema.PaginationParam{Current: 2, PageSize: 1},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{13}, impact.Users.ToIDs())
+	assert.Equal(t, int64(2), impact.UserPagination.Total)
+	assert.Equal(t, 2, impact.UserPagination.Current)
+
+	impact, err = a.DeleteImpact(pctx, rootID, schema.MenuDeleteImpactParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, impact.Users)
+	assert.Equal(t, int64(2), impact.UserPagination.Total)
+	assert.Len(t, impact.Roles, 2)
+
+	_, err = a.DeleteCascade(pctx, rootID, schema.MenuDeleteImpactParam{})
+	assert.Nil(t, err)
+	menuResult, err = a.MenuRepo.Query(ctx, schema.MenuQueryParam{})
+	assert.Nil(t, err)
+	assert.Empty(t, menuResult.Data)
+	roleMenuResult, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{})
+	assert.Nil(t, err)
+	assert.Empty(t, roleMenuResult.Data)
+
+	ok, err = a.Enforcer.Enforce("12", "0", "/api/v1/users", "GET")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/totp"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var MFASet = wire.NewSet(wire.Struct(new(MFASrv), "*"))
+
+// MFASrv 两步验证(TOTP动态口令与恢复码)
+type MFASrv struct {
+	TransRepo            *dao.TransRepo
+	UserRepo             *dao.UserRepo
+	UserMFARepo          *dao.UserMFARepo
+	UserRecoveryCodeRepo *dao.UserRecoveryCodeRepo
+}
+
+// IsEnabled 检查用户是否已启用两步验证
+func (a *MFASrv) IsEnabled(ctx context.Context, userID uint64) (bool, error) {
+	item, err := a.UserMFARepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return item != nil && item.Enabled, nil
+}
+
+// Enroll 生成新的TOTP密钥(需要通过Activate校验动态口令后才生效)
+func (a *MFASrv) Enroll(ctx context.Context, userID uint64) (*schema.MFAEnrollment, error) {
+	user, err := a.UserRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	oldItem, err := a.UserMFARepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if oldItem != nil && oldItem.Enabled {
+		return nil, errors.New400Response("已启用两步验证")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.UserMFARepo.DeleteByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		return a.UserMFARepo.Create(ctx, schema.UserMFA{
+			ID:     snowflake.MustID(),
+			UserID: userID,
+			Secret: secret,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(config.C.MFA.Issuer, user.UserName, secret),
+	}, nil
+}
+
+func (a *MFASrv) getPending(ctx context.Context, userID uint64) (*schema.UserMFA, error) {
+	item, err := a.UserMFARepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if item == nil || item.Enabled {
+		return nil, errors.New400Response("请先获取两步验证密钥")
+	}
+	return item, nil
+}
+
+// ResQRCode 响应待绑定密钥的二维码图片
+func (a *MFASrv) ResQRCode(ctx context.Context, w http.ResponseWriter, userID uint64) error {
+	item, err := a.getPending(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user, err := a.UserRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	} else if user == nil {
+		return errors.ErrNotFound
+	}
+
+	buf, err := totp.QRCode(totp.URI(config.C.MFA.Issuer, user.UserName, item.Secret))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Expires", "0")
+	w.Header().Set("Content-Type", "image/png")
+	_, err = w.Write(buf)
+	return errors.WithStack(err)
+}
+
+// Activate 校验动态口令完成绑定，并生成恢复码
+func (a *MFASrv) Activate(ctx context.Context, userID uint64, code string) (*schema.MFARecoveryCodes, error) {
+	item, err := a.getPending(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok, err := totp.Validate(item.Secret, normalizeMFACode(code), time.Now(), config.C.MFA.Skew)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, errors.New400Response("动态口令不正确")
+	}
+
+	codes, err := generateRecoveryCodes(config.C.MFA.RecoveryCodes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.UserMFARepo.Enable(ctx, item.ID, step)
+		if err != nil {
+			return err
+		}
+		return a.saveRecoveryCodes(ctx, userID, codes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.MFARecoveryCodes{Codes: codes}, nil
+}
+
+func (a *MFASrv) saveRecoveryCodes(ctx context.Context, userID uint64, codes []string) error {
+	err := a.UserRecoveryCodeRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		err := a.UserRecoveryCodeRepo.Create(ctx, schema.UserRecoveryCode{
+			ID:     snowflake.MustID(),
+			UserID: userID,
+			Code:   hashRecoveryCode(code),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify 校验动态口令或恢复码(均只能使用一次)
+func (a *MFASrv) Verify(ctx context.Context, userID uint64, code string) (bool, error) {
+	item, err := a.UserMFARepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	} else if item == nil || !item.Enabled {
+		return false, nil
+	}
+
+	code = normalizeMFACode(code)
+	if len(code) != totp.Digits || strings.Trim(code, "0123456789") != "" {
+		return a.UserRecoveryCodeRepo.Use(ctx, userID, hashRecoveryCode(code))
+	}
+
+	step, ok, err := totp.Validate(item.Secret, code, time.Now(), config.C.MFA.Skew)
+	if err != nil {
+		return false, errors.WithStack(err)
+	} else if !ok || step <= item.LastStep {
+		return false, nil
+	}
+	return a.UserMFARepo.UpdateLastStep(ctx, item.ID, step)
+}
+
+// Delete 删除用户的两步验证设置与恢复码
+func (a *MFASrv) Delete(ctx context.Context, userID uint64) error {
+	err := a.UserMFARepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return a.UserRecoveryCodeRepo.DeleteByUserID(ctx, userID)
+}
+
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+// 恢复码去掉分隔符后再计算哈希，输入时可省略分隔符
+func hashRecoveryCode(code string) string {
+	return hash.SHA256String(strings.ReplaceAll(normalizeMFACode(code), "-", ""))
+}
+
+// 恢复码字符集(去掉了容易混淆的字符)
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+func generateRecoveryCodes(n int) ([]string, error) {
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	codes := make([]string, n)
+	for i := range codes {
+		var b strings.Builder
+		for j := 0; j < 10; j++ {
+			if j == 5 {
+				b.WriteByte('-')
+			}
+			v, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, err
+			}
+			b.WriteByte(recoveryCodeAlphabet[v.Int64()])
+		}
+		codes[i] = b.String()
+	}
+	return codes, nil
+}
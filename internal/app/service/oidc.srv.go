@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var OIDCSet = wire.NewSet(wire.Struct(new(OIDCSrv), "*"))
+
+// OIDCSrv OpenID Connect登录(授权码模式 + PKCE)
+type OIDCSrv struct {
+	Provider    *oidc.Provider
+	IdentitySrv *IdentitySrv
+}
+
+func (a *OIDCSrv) getProvider() (*oidc.Provider, error) {
+	if a.Provider == nil {
+		return nil, errors.NewResponse(0, 404, "未启用OIDC登录")
+	}
+	return a.Provider, nil
+}
+
+// GetAuthURL 生成跳转到身份提供方的授权地址
+func (a *OIDCSrv) GetAuthURL(ctx context.Context) (*schema.LoginOIDCURL, error) {
+	p, err := a.getProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := p.AuthCodeURL(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &schema.LoginOIDCURL{URL: req.URL, State: req.State}, nil
+}
+
+// Login 使用授权码完成登录，返回对应的本地用户(按配置自动创建用户并同步角色)
+func (a *OIDCSrv) Login(ctx context.Context, code, state string) (*schema.User, error) {
+	p, err := a.getProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.Exchange(ctx, code, state)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidState) ||
+			errors.Is(err, oidc.ErrInvalidIDToken) ||
+			errors.Is(err, oidc.ErrTokenRequest) {
+			return nil, errors.Wrap400Response(err, "无效的授权码或状态")
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	cfg := config.C.OIDC
+	user, err := a.IdentitySrv.GetUser(ctx, schema.ExternalUser{
+		Source:        "oidc",
+		Provider:      cfg.Issuer,
+		Subject:       claims.Subject,
+		UserName:      claims.UserName,
+		RealName:      claims.Name,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Groups:        claims.Groups,
+	}, IdentityOptions{
+		AutoCreate:  cfg.AutoCreate,
+		LinkByEmail: cfg.LinkByEmail,
+	})
+	if err != nil {
+		return nil, err
+	} else if user.Status != 1 {
+		return nil, errors.ErrUserDisable
+	}
+
+	err = a.IdentitySrv.SyncRoles(ctx, user.ID, claims.Groups, cfg.GroupRoles)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
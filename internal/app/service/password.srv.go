@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var PasswordSet = wire.NewSet(wire.Struct(new(PasswordSrv), "*"))
+
+// PasswordSrv 密码策略(复杂度、历史密码、有效期)
+type PasswordSrv struct {
+	PasswordHasher   hash.PasswordHasher
+	UserPasswordRepo *dao.UserPasswordRepo
+}
+
+// 客户端提交明文密码，生成及校验哈希时使用其md5值(与早期客户端提交md5加密密码时生成的哈希保持兼容)
+func passwordDigest(password string) string {
+	return hash.MD5String(password)
+}
+
+// Check 校验新密码(客户端提交的明文密码)是否符合密码策略，current为用户当前的密码哈希(新建用户为空)
+func (a *PasswordSrv) Check(ctx context.Context, userID uint64, current, password string) error {
+	cfg := config.C.PasswordPolicy
+	violations := checkPasswordComplexity(cfg, password)
+
+	if n := cfg.HistoryCount; n > 0 && userID > 0 {
+		reused, err := a.isReused(ctx, userID, current, password, n)
+		if err != nil {
+			return err
+		} else if reused {
+			violations = append(violations, &schema.PasswordViolation{
+				Rule:    "history",
+				Message: fmt.Sprintf("不能使用最近%d次使用过的密码", n),
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.New400ResponseWithDetails(violations, "密码不符合安全策略")
+	}
+	return nil
+}
+
+func checkPasswordComplexity(cfg config.PasswordPolicy, password string) schema.PasswordViolations {
+	var violations schema.PasswordViolations
+	add := func(rule, message string) {
+		violations = append(violations, &schema.PasswordViolation{Rule: rule, Message: message})
+	}
+
+	if n := cfg.MinLength; n > 0 && len([]rune(password)) < n {
+		add("min_length", fmt.Sprintf("长度不能少于%d个字符", n))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	if cfg.RequireUpper && !upper {
+		add("upper", "必须包含大写字母")
+	}
+	if cfg.RequireLower && !lower {
+		add("lower", "必须包含小写字母")
+	}
+	if cfg.RequireDigit && !digit {
+		add("digit", "必须包含数字")
+	}
+	if cfg.RequireSymbol && !symbol {
+		add("symbol", "必须包含特殊字符")
+	}
+	return violations
+}
+
+// 检查是否与当前密码或最近使用过的密码相同
+func (a *PasswordSrv) isReused(ctx context.Context, userID uint64, current, password string, n int) (bool, error) {
+	encodedList := []string{current}
+
+	history, err := a.UserPasswordRepo.QueryRecent(ctx, userID, n)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range history {
+		encodedList = append(encodedList, item.Password)
+	}
+
+	password = passwordDigest(password)
+	for _, encoded := range encodedList {
+		if encoded == "" {
+			continue
+		}
+		ok, err := a.PasswordHasher.Verify(encoded, password)
+		if err != nil && err != hash.ErrUnsupportedHash {
+			return false, errors.WithStack(err)
+		} else if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Hash 生成明文密码的哈希
+func (a *PasswordSrv) Hash(password string) (string, error) {
+	encoded, err := a.PasswordHasher.Hash(passwordDigest(password))
+	return encoded, errors.WithStack(err)
+}
+
+// Verify 校验明文密码与密码哈希是否匹配
+func (a *PasswordSrv) Verify(encoded, password string) (bool, error) {
+	ok, err := a.PasswordHasher.Verify(encoded, passwordDigest(password))
+	return ok, errors.WithStack(err)
+}
+
+// SaveHistory 记录历史密码，仅保留最近的HistoryCount条
+func (a *PasswordSrv) SaveHistory(ctx context.Context, userID uint64, encoded string) error {
+	n := config.C.PasswordPolicy.HistoryCount
+	if n <= 0 {
+		return nil
+	}
+
+	err := a.UserPasswordRepo.Create(ctx, schema.UserPassword{
+		ID:       snowflake.MustID(),
+		UserID:   userID,
+		Password: encoded,
+	})
+	if err != nil {
+		return err
+	}
+
+	history, err := a.UserPasswordRepo.QueryRecent(ctx, userID, n)
+	if err != nil {
+		return err
+	} else if len(history) < n {
+		return nil
+	}
+	return a.UserPasswordRepo.DeleteBefore(ctx, userID, history[len(history)-1].ID)
+}
+
+// DeleteHistory 删除用户的历史密码
+func (a *PasswordSrv) DeleteHistory(ctx context.Context, userID uint64) error {
+	return a.UserPasswordRepo.DeleteByUserID(ctx, userID)
+}
+
+// NeedsChange 检查用户是否必须修改密码(设定了强制修改标记或密码已过期)，
+// 外部身份自动创建的用户由身份提供方管理密码，不需要修改
+func (a *PasswordSrv) NeedsChange(user *schema.User) bool {
+	if user.Source != "" {
+		return false
+	} else if user.MustChangePassword {
+		return true
+	}
+
+	maxAge := config.C.PasswordPolicy.MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return time.Since(changedAt) > time.Duration(maxAge)*24*time.Hour
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+)
+
+func TestCheckPasswordComplexity(t *testing.T) {
+	cfg := config.PasswordPolicy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	rules := func(password string) []string {
+		var list []string
+		for _, item := range checkPasswordComplexity(cfg, password) {
+			list = append(list, item.Rule)
+		}
+		return list
+	}
+
+	assert.Empty(t, rules("Secret-123"))
+	assert.ElementsMatch(t, []string{"min_length", "upper", "digit", "symbol"}, rules("abc"))
+	assert.ElementsMatch(t, []string{"lower", "symbol"}, rules("SECRET123"))
+	assert.ElementsMatch(t, []string{"min_length"}, rules("Ab-1"))
+	assert.Empty(t, checkPasswordComplexity(config.PasswordPolicy{}, "a"))
+}
+
+func TestPasswordNeedsChange(t *testing.T) {
+	policy := config.C.PasswordPolicy
+	defer func() { config.C.PasswordPolicy = policy }()
+	config.C.PasswordPolicy.MaxAge = 90
+
+	a := &PasswordSrv{}
+	now := time.Now()
+	expired := now.Add(-91 * 24 * time.Hour)
+
+	assert.False(t, a.NeedsChange(&schema.User{CreatedAt: now}))
+	assert.True(t, a.NeedsChange(&schema.User{CreatedAt: now, MustChangePassword: true}))
+
+	// 超过有效期(未修改过密码时按创建时间计算)
+	assert.True(t, a.NeedsChange(&schema.User{CreatedAt: expired}))
+	assert.True(t, a.NeedsChange(&schema.User{CreatedAt: expired, PasswordChangedAt: &expired}))
+	assert.False(t, a.NeedsChange(&schema.User{CreatedAt: expired, PasswordChangedAt: &now}))
+
+	// 外部身份的用户不需要修改密码
+	assert.False(t, a.NeedsChange(&schema.User{CreatedAt: expired, Source: "ldap", MustChangePassword: true}))
+
+	// 需要修改密码时签发的令牌仅允许修改密码
+	l := &LoginSrv{PasswordSrv: a}
+	ctx := l.NewTokenScopeContext(context.Background(), &schema.User{CreatedAt: expired})
+	assert.Equal(t, auth.ScopePasswordChange, auth.FromScopeContext(ctx))
+	ctx = l.NewTokenScopeContext(context.Background(), &schema.User{CreatedAt: now})
+	assert.Empty(t, auth.FromScopeContext(ctx))
+
+	config.C.PasswordPolicy.MaxAge = 0
+	assert.False(t, a.NeedsChange(&schema.User{CreatedAt: expired}))
+}
@@ -0,0 +1,583 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/casbin/casbin/v2"
+	casbinUtil "github.com/casbin/casbin/v2/util"
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/module/adapter"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var PermissionSet = wire.NewSet(wire.Struct(new(PermissionSrv), "*"))
+
+// PermissionSrv 权限校验及诊断
+type PermissionSrv struct {
+	Enforcer               *casbin.SyncedEnforcer
+	CasbinAdapter          *adapter.CasbinAdapter
+	UserRepo               *dao.UserRepo
+	UserRoleRepo           *dao.UserRoleRepo
+	RoleRepo               *dao.RoleRepo
+	RoleMenuRepo           *dao.RoleMenuRepo
+	RoleParentRepo         *dao.RoleParentRepo
+	MenuRepo               *dao.MenuRepo
+	MenuActionRepo         *dao.MenuActionRepo
+	MenuActionResourceRepo *dao.MenuActionResourceRepo
+	SuperAdminSrv          *SuperAdminSrv
+}
+
+// Enforce 校验用户访问接口的权限(限定了角色的API密钥仅按限定的角色校验)
+func (a *PermissionSrv) Enforce(ctx context.Context, userID uint64, path, method string) (bool, error) {
+	if !config.C.Casbin.Enable {
+		return true, nil
+	}
+
+	if roleIDs, ok := contextx.FromAPIKeyRoles(ctx); ok {
+		for _, roleID := range roleIDs {
+			if b, err := a.Enforcer.Enforce(roleID, tenantDomain(ctx), path, method); err != nil {
+				return false, errors.WithStack(err)
+			} else if b {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if err := a.CasbinAdapter.LoadUser(a.Enforcer, userID); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	b, err := a.Enforcer.Enforce(strconv.FormatUint(userID, 10), tenantDomain(ctx), path, method)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// Check 批量校验用户的权限，菜单动作需要其关联的资源全部允许访问(未关联资源的动作不允许)
+func (a *PermissionSrv) Check(ctx context.Context, userID uint64, items []*schema.PermissionCheckItem) ([]*schema.PermissionCheckResult, error) {
+	var routers []string
+	for _, item := range items {
+		if item.Router != "" && item.Code != "" {
+			routers = append(routers, item.Router)
+		} else if item.Method == "" || item.Path == "" {
+			return nil, errors.New400Response("校验项需要指定菜单路由及动作编号，或请求方式及请求路径")
+		}
+	}
+
+	mResources, err := a.queryActionResources(ctx, routers)
+	if err != nil {
+		return nil, err
+	}
+
+	isSuperAdmin, err := a.SuperAdminSrv.IsSuperAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	mAllowed := make(map[string]bool)
+	enforce := func(path, method string) (bool, error) {
+		if isSuperAdmin {
+			return true, nil
+		}
+
+		key := method + " " + path
+		if b, ok := mAllowed[key]; ok {
+			return b, nil
+		}
+		b, err := a.Enforce(ctx, userID, path, method)
+		if err != nil {
+			return false, err
+		}
+		mAllowed[key] = b
+		return b, nil
+	}
+
+	list := make([]*schema.PermissionCheckResult, len(items))
+	for i, item := range items {
+		result := &schema.PermissionCheckResult{PermissionCheckItem: *item}
+		list[i] = result
+
+		if item.Router == "" || item.Code == "" {
+			result.Allowed, err = enforce(item.Path, item.Method)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		resources, ok := mResources[item.Router+" "+item.Code]
+		if !ok {
+			continue
+		} else if isSuperAdmin {
+			result.Allowed = true
+			continue
+		}
+
+		result.Allowed = len(resources) > 0
+		for _, res := range resources {
+			b, err := enforce(res.Path, res.Method)
+			if err != nil {
+				return nil, err
+			} else if !b {
+				result.Allowed = false
+				break
+			}
+		}
+	}
+	return list, nil
+}
+
+// 获取菜单动作关联的资源(键为菜单路由及动作编号，仅包含启用的菜单)
+func (a *PermissionSrv) queryActionResources(ctx context.Context, routers []string) (map[string]schema.MenuActionResources, error) {
+	m := make(map[string]schema.MenuActionResources)
+	if len(routers) == 0 {
+		return m, nil
+	}
+
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{
+		Routers: routers,
+		Status:  1,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(menuResult.Data) == 0 {
+		return m, nil
+	}
+	mMenus := menuResult.Data.ToMap()
+
+	menuIDs := make([]uint64, len(menuResult.Data))
+	for i, item := range menuResult.Data {
+		menuIDs[i] = item.ID
+	}
+
+	actionResult, err := a.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{
+		MenuIDs: menuIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resourceResult, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{
+		MenuIDs: menuIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mResources := resourceResult.Data.ToActionIDMap()
+
+	for _, action := range actionResult.Data {
+		menu, ok := mMenus[action.MenuID]
+		if !ok {
+			continue
+		}
+		key := menu.Router + " " + action.Code
+		m[key] = append(m[key], mResources[action.ID]...)
+	}
+	return m, nil
+}
+
+// Explain 诊断用户(或角色)访问指定接口的权限判定过程
+func (a *PermissionSrv) Explain(ctx context.Context, params schema.PermissionExplainParam) (*schema.PermissionExplain, error) {
+	if (params.UserID == 0) == (params.RoleID == 0) {
+		return nil, errors.New400Response("用户ID与角色ID必须且只能指定一个")
+	}
+
+	// 诊断的用户及角色不受数据范围限制
+	ctx = contextx.NewNoDataScope(ctx)
+	result := &schema.PermissionExplain{}
+
+	var roleIDs []uint64
+	var userDisabled bool
+	if params.UserID > 0 {
+		user, err := a.UserRepo.Get(ctx, params.UserID)
+		if err != nil {
+			return nil, err
+		} else if user == nil {
+			return nil, errors.ErrNotFound
+		} else if user.Status == 1 && user.IsSuperAdmin {
+			result.Allowed = true
+			result.Subject = strconv.FormatUint(params.UserID, 10)
+			result.Reason = "超级管理员不校验权限"
+			return result, nil
+		}
+		userDisabled = user.Status != 1
+
+		userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+			UserID: params.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		roleIDs = userRoleResult.Data.ToRoleIDs()
+		result.Subject = strconv.FormatUint(params.UserID, 10)
+	} else {
+		role, err := a.RoleRepo.Get(ctx, params.RoleID)
+		if err != nil {
+			return nil, err
+		} else if role == nil {
+			return nil, errors.ErrNotFound
+		}
+		roleIDs = []uint64{params.RoleID}
+		result.Subject = strconv.FormatUint(params.RoleID, 10)
+	}
+
+	roles, err := a.explainRoles(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	result.Roles = roles
+
+	cfg := config.C.Casbin
+	if cfg.Model == "" {
+		result.Allowed = true
+		result.Reason = "未开启权限校验"
+		return result, nil
+	}
+
+	if params.UserID > 0 {
+		if err := a.CasbinAdapter.LoadUser(a.Enforcer, params.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	allowed, matched, err := a.Enforcer.EnforceEx(result.Subject, tenantDomain(ctx), params.Path, params.Method)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	result.Allowed = allowed
+	result.MatchedPolicy = matched
+
+	policies, err := a.explainPolicies(ctx, roles, params.Path, params.Method)
+	if err != nil {
+		return nil, err
+	}
+	result.Policies = policies
+	result.Reason = explainReason(result, userDisabled, cfg.Enable)
+
+	return result, nil
+}
+
+// 获取判定说明
+func explainReason(result *schema.PermissionExplain, userDisabled, enable bool) string {
+	if !enable {
+		return "未开启权限校验"
+	} else if userDisabled {
+		return "用户已禁用"
+	} else if result.Allowed {
+		return "命中策略"
+	} else if len(result.Roles) == 0 {
+		return "未分配角色"
+	}
+
+	var effective bool
+	for _, item := range result.Roles {
+		if item.Effective {
+			effective = true
+			break
+		}
+	}
+	if !effective {
+		return "分配的角色均已禁用"
+	}
+
+	for _, item := range result.Policies {
+		if item.PathMatched {
+			return "请求路径匹配但请求方式不匹配"
+		}
+	}
+	for _, item := range result.Policies {
+		if item.MethodMatched {
+			return "请求方式匹配但请求路径不匹配"
+		}
+	}
+	return "没有匹配的策略"
+}
+
+// 获取角色及其继承的全部上级角色(禁用的角色及仅通过禁用角色继承的上级角色不生效)
+func (a *PermissionSrv) explainRoles(ctx context.Context, roleIDs []uint64) ([]*schema.PermissionExplainRole, error) {
+	var list []*schema.PermissionExplainRole
+	mRoles := make(map[uint64]*schema.PermissionExplainRole)
+	mParents := make(map[uint64][]uint64)
+
+	queue := make([]*schema.PermissionExplainRole, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		queue = append(queue, &schema.PermissionExplainRole{ID: id})
+	}
+
+	for len(queue) > 0 {
+		var ids []uint64
+		mQueue := make(map[uint64]*schema.PermissionExplainRole)
+		for _, item := range queue {
+			if _, ok := mRoles[item.ID]; ok {
+				continue
+			} else if _, ok := mQueue[item.ID]; ok {
+				continue
+			}
+			mQueue[item.ID] = item
+			ids = append(ids, item.ID)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		roleResult, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{
+			IDs: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ids = ids[:0]
+		for _, role := range roleResult.Data {
+			item := mQueue[role.ID]
+			item.Name = role.Name
+			item.Status = role.Status
+			mRoles[role.ID] = item
+			list = append(list, item)
+			ids = append(ids, role.ID)
+		}
+
+		parentResult, err := a.RoleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+			RoleIDs: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		queue = nil
+		for _, item := range parentResult.Data {
+			mParents[item.RoleID] = append(mParents[item.RoleID], item.ParentID)
+			queue = append(queue, &schema.PermissionExplainRole{
+				ID:            item.ParentID,
+				InheritedFrom: item.RoleID,
+			})
+		}
+	}
+
+	// 从直接分配的角色开始，仅沿启用的角色向上继承
+	for len(roleIDs) > 0 {
+		var next []uint64
+		for _, id := range roleIDs {
+			item, ok := mRoles[id]
+			if !ok || item.Effective || item.Status != 1 {
+				continue
+			}
+			item.Effective = true
+			next = append(next, mParents[id]...)
+		}
+		roleIDs = next
+	}
+
+	return list, nil
+}
+
+// 获取生效角色中路径或请求方式匹配的策略，并关联产生策略的菜单动作及资源
+func (a *PermissionSrv) explainPolicies(ctx context.Context, roles []*schema.PermissionExplainRole, path, method string) ([]*schema.PermissionExplainPolicy, error) {
+	var list []*schema.PermissionExplainPolicy
+	var roleIDs []uint64
+	for _, role := range roles {
+		if !role.Effective {
+			continue
+		}
+
+		// 策略格式为(sub,dom,obj,act)
+		for _, rule := range a.Enforcer.GetFilteredPolicy(0, strconv.FormatUint(role.ID, 10), tenantDomain(ctx)) {
+			if len(rule) < 4 {
+				continue
+			}
+
+			item := &schema.PermissionExplainPolicy{
+				RoleID:        role.ID,
+				Path:          rule[2],
+				Method:        rule[3],
+				PathMatched:   casbinUtil.KeyMatch2(path, rule[2]),
+				MethodMatched: casbinUtil.RegexMatch(method, rule[3]),
+			}
+			if item.PathMatched || item.MethodMatched {
+				list = append(list, item)
+			}
+		}
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	sources, err := a.querySources(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list {
+		item.Sources = sources[policySourceKey(item.RoleID, item.Path, item.Method)]
+	}
+	return list, nil
+}
+
+func policySourceKey(roleID uint64, path, method string) string {
+	return strconv.FormatUint(roleID, 10) + "," + path + "," + method
+}
+
+// 获取角色的策略来源(键为角色ID、路径及请求方式)
+func (a *PermissionSrv) querySources(ctx context.Context, roleIDs []uint64) (map[string][]*schema.PermissionExplainSource, error) {
+	roleMenuResult, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{
+		RoleIDs: roleIDs,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(roleMenuResult.Data) == 0 {
+		return nil, nil
+	}
+
+	resourceResult, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{
+		MenuIDs: roleMenuResult.Data.ToMenuIDs(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	mResources := resourceResult.Data.ToActionIDMap()
+
+	actionResult, err := a.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{
+		IDs: roleMenuResult.Data.ToActionIDs(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{
+		IDs: roleMenuResult.Data.ToMenuIDs(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	mMenus := menuResult.Data.ToMap()
+
+	mActions := make(map[uint64]*schema.MenuAction)
+	for _, item := range actionResult.Data {
+		mActions[item.ID] = item
+	}
+
+	m := make(map[string][]*schema.PermissionExplainSource)
+	for _, rm := range roleMenuResult.Data {
+		action, ok := mActions[rm.ActionID]
+		if !ok {
+			continue
+		}
+
+		var menuName string
+		if menu, ok := mMenus[action.MenuID]; ok {
+			menuName = menu.Name
+		}
+
+		for _, res := range mResources[action.ID] {
+			key := policySourceKey(rm.RoleID, res.Path, res.Method)
+			m[key] = append(m[key], &schema.PermissionExplainSource{
+				MenuID:     action.MenuID,
+				MenuName:   menuName,
+				ActionID:   action.ID,
+				ActionCode: action.Code,
+				ActionName: action.Name,
+				ResourceID: res.ID,
+			})
+		}
+	}
+	return m, nil
+}
+
+// CheckRoutes 检查需要权限校验的接口路由与菜单动作资源的对应关系(与权限模型一致，路径按keyMatch2、请求方式按regexMatch匹配)
+func (a *PermissionSrv) CheckRoutes(ctx context.Context, routes schema.Routes) (*schema.RouteCheckResult, error) {
+	resources, err := a.queryRouteCheckResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &schema.RouteCheckResult{}
+	matched := make(map[uint64]bool)
+	for _, route := range routes {
+		var patterns schema.Routes
+		mPatterns := make(map[string]struct{})
+		for _, res := range resources {
+			if !casbinUtil.KeyMatch2(route.Path, res.Path) || !casbinUtil.RegexMatch(route.Method, res.Method) {
+				continue
+			}
+			matched[res.ResourceID] = true
+
+			key := res.Method + " " + res.Path
+			if _, ok := mPatterns[key]; ok {
+				continue
+			}
+			mPatterns[key] = struct{}{}
+			patterns = append(patterns, &schema.Route{Method: res.Method, Path: res.Path})
+		}
+
+		if len(patterns) == 0 {
+			result.UnprotectedRoutes = append(result.UnprotectedRoutes, route)
+		} else if len(patterns) > 1 {
+			result.OverlappingRoutes = append(result.OverlappingRoutes, &schema.RouteCheckOverlap{
+				Method:   route.Method,
+				Path:     route.Path,
+				Patterns: patterns,
+			})
+		}
+	}
+
+	for _, res := range resources {
+		if !matched[res.ResourceID] {
+			result.StaleResources = append(result.StaleResources, res)
+		}
+	}
+	return result, nil
+}
+
+// 获取全部菜单动作资源(忽略未设置请求方式或路径的资源，与加载策略时一致)
+func (a *PermissionSrv) queryRouteCheckResources(ctx context.Context) ([]*schema.RouteCheckResource, error) {
+	resourceResult, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{})
+	if err != nil {
+		return nil, err
+	}
+
+	actionResult, err := a.MenuActionRepo.Query(ctx, schema.MenuActionQueryParam{})
+	if err != nil {
+		return nil, err
+	}
+	mActions := make(map[uint64]*schema.MenuAction)
+	for _, item := range actionResult.Data {
+		mActions[item.ID] = item
+	}
+
+	menuResult, err := a.MenuRepo.Query(ctx, schema.MenuQueryParam{})
+	if err != nil {
+		return nil, err
+	}
+	mMenus := menuResult.Data.ToMap()
+
+	var list []*schema.RouteCheckResource
+	for _, res := range resourceResult.Data {
+		if res.Method == "" || res.Path == "" {
+			continue
+		}
+
+		item := &schema.RouteCheckResource{
+			ActionID:   res.ActionID,
+			ResourceID: res.ID,
+			Method:     res.Method,
+			Path:       res.Path,
+		}
+		if action, ok := mActions[res.ActionID]; ok {
+			item.MenuID = action.MenuID
+			item.ActionCode = action.Code
+			if menu, ok := mMenus[action.MenuID]; ok {
+				item.MenuName = menu.Name
+			}
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/module/adapter"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+)
+
+// 用户12的角色2继承角色1，角色1拥有用户查询权限
+func newTestPermissionSrv(t *testing.T, name string) *PermissionSrv {
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &PermissionSrv{
+		UserRepo:               &dao.UserRepo{DB: db},
+		UserRoleRepo:           &dao.UserRoleRepo{DB: db},
+		RoleRepo:               &dao.RoleRepo{DB: db},
+		RoleMenuRepo:           &dao.RoleMenuRepo{DB: db},
+		RoleParentRepo:         &dao.RoleParentRepo{DB: db},
+		MenuRepo:               &dao.MenuRepo{DB: db},
+		MenuActionRepo:         &dao.MenuActionRepo{DB: db},
+		MenuActionResourceRepo: &dao.MenuActionResourceRepo{DB: db},
+	}
+	a.SuperAdminSrv = &SuperAdminSrv{UserRepo: a.UserRepo}
+	a.CasbinAdapter = &adapter.CasbinAdapter{
+		RoleRepo:         a.RoleRepo,
+		RoleMenuRepo:     a.RoleMenuRepo,
+		RoleParentRepo:   a.RoleParentRepo,
+		MenuResourceRepo: a.MenuActionResourceRepo,
+		UserRepo:         a.UserRepo,
+		UserRoleRepo:     a.UserRoleRepo,
+	}
+
+	ctx := context.Background()
+	assert.Nil(t, a.MenuRepo.Create(ctx, schema.Menu{ID: 1, Name: "user", Router: "/system/user", Status: 1}))
+	assert.Nil(t, a.MenuActionRepo.Create(ctx, schema.MenuAction{ID: 1, MenuID: 1, Code: "query", Name: "query"}))
+	assert.Nil(t, a.MenuActionResourceRepo.Create(ctx, schema.MenuActionResource{ID: 1, ActionID: 1, Method: "GET", Path: "/api/v1/users/:id"}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 1, Name: "viewer", Status: 1}))
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 2, Name: "auditor", Status: 1}))
+	assert.Nil(t, a.RoleMenuRepo.Create(ctx, schema.RoleMenu{ID: 1, RoleID: 1, MenuID: 1, ActionID: 1}))
+	assert.Nil(t, a.RoleParentRepo.Create(ctx, schema.RoleParent{ID: 1, RoleID: 2, ParentID: 1}))
+	assert.Nil(t, a.UserRepo.Create(ctx, schema.User{ID: 12, UserName: "u12", Status: 1}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 1, UserID: 12, RoleID: 2}))
+
+	a.Enforcer, err = casbin.NewSyncedEnforcer(config.C.Casbin.Model, a.CasbinAdapter)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return a
+}
+
+func TestPermissionExplain(t *testing.T) {
+	casbinConfig := config.C.Casbin
+	defer func() { config.C.Casbin = casbinConfig }()
+	config.C.Casbin.Enable = true
+	config.C.Casbin.Model = "../../../configs/model.conf"
+
+	a := newTestPermissionSrv(t, "permexplain")
+	ctx := context.Background()
+
+	_, err := a.Explain(ctx, schema.PermissionExplainParam{Method: "GET", Path: "/api/v1/users/1"})
+	assert.NotNil(t, err)
+
+	result, err := a.Explain(ctx, schema.PermissionExplainParam{UserID: 12, Method: "GET", Path: "/api/v1/users/1"})
+	if assert.Nil(t, err) {
+		assert.True(t, result.Allowed)
+		assert.Equal(t, []string{"1", "0", "/api/v1/users/:id", "GET"}, result.MatchedPolicy)
+		if assert.Equal(t, 2, len(result.Roles)) {
+			assert.Equal(t, uint64(2), result.Roles[1].InheritedFrom)
+			assert.True(t, result.Roles[1].Effective)
+		}
+		if assert.Equal(t, 1, len(result.Policies)) && assert.Equal(t, 1, len(result.Policies[0].Sources)) {
+			assert.Equal(t, "query", result.Policies[0].Sources[0].ActionCode)
+			assert.Equal(t, "user", result.Policies[0].Sources[0].MenuName)
+		}
+	}
+
+	// 仅路径匹配
+	result, err = a.Explain(ctx, schema.PermissionExplainParam{UserID: 12, Method: "DELETE", Path: "/api/v1/users/1"})
+	if assert.Nil(t, err) {
+		assert.False(t, result.Allowed)
+		assert.Equal(t, "请求路径匹配但请求方式不匹配", result.Reason)
+		if assert.Equal(t, 1, len(result.Policies)) {
+			assert.True(t, result.Policies[0].PathMatched)
+			assert.False(t, result.Policies[0].MethodMatched)
+		}
+	}
+
+	// 禁用的角色不再继承上级角色
+	assert.Nil(t, a.RoleRepo.UpdateStatus(ctx, 2, 2))
+	assert.Nil(t, a.Enforcer.LoadPolicy())
+	result, err = a.Explain(ctx, schema.PermissionExplainParam{RoleID: 2, Method: "GET", Path: "/api/v1/users/1"})
+	if assert.Nil(t, err) {
+		assert.False(t, result.Allowed)
+		assert.Equal(t, "分配的角色均已禁用", result.Reason)
+		for _, item := range result.Roles {
+			assert.False(t, item.Effective)
+		}
+	}
+}
+
+func TestPermissionEnforceAPIKey(t *testing.T) {
+	casbinConfig := config.C.Casbin
+	defer func() { config.C.Casbin = casbinConfig }()
+	config.C.Casbin.Enable = true
+	config.C.Casbin.Model = "../../../configs/model.conf"
+
+	a := newTestPermissionSrv(t, "permapikey")
+	ctx := context.Background()
+	assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: 3, Name: "guest", Status: 1}))
+	assert.Nil(t, a.UserRoleRepo.Create(ctx, schema.UserRole{ID: 2, UserID: 12, RoleID: 3}))
+	assert.Nil(t, a.Enforcer.LoadPolicy())
+
+	enforce := func(ctx context.Context) bool {
+		ok, err := a.Enforce(ctx, 12, "/api/v1/users/1", "GET")
+		assert.Nil(t, err)
+		return ok
+	}
+
+	// 未限定角色的API密钥与所属用户的权限一致
+	assert.True(t, enforce(ctx))
+	assert.True(t, enforce(contextx.NewAPIKey(ctx, 1, nil, false)))
+
+	// 限定角色的API密钥仅按限定的角色(含继承的角色)校验
+	assert.True(t, enforce(contextx.NewAPIKey(ctx, 1, []string{"2"}, true)))
+	assert.False(t, enforce(contextx.NewAPIKey(ctx, 1, []string{"3"}, true)))
+	assert.False(t, enforce(contextx.NewAPIKey(ctx, 1, nil, true)))
+}
+
+func TestPermissionCheck(t *testing.T) {
+	casbinConfig := config.C.Casbin
+	defer func() { config.C.Casbin = casbinConfig }()
+	config.C.Casbin.Enable = true
+	config.C.Casbin.Model = "../../../configs/model.conf"
+
+	a := newTestPermissionSrv(t, "permcheck")
+	ctx := context.Background()
+
+	_, err := a.Check(ctx, 12, []*schema.PermissionCheckItem{{Router: "/system/user"}})
+	assert.NotNil(t, err)
+
+	assert.Nil(t, a.MenuActionRepo.Create(ctx, schema.MenuAction{ID: 2, MenuID: 1, Code: "del", Name: "del"}))
+	assert.Nil(t, a.MenuActionResourceRepo.Create(ctx, schema.MenuActionResource{ID: 2, ActionID: 2, Method: "DELETE", Path: "/api/v1/users/:id"}))
+	assert.Nil(t, a.MenuActionRepo.Create(ctx, schema.MenuAction{ID: 3, MenuID: 1, Code: "print", Name: "print"}))
+
+	items := []*schema.PermissionCheckItem{
+		{Router: "/system/user", Code: "query"},
+		{Router: "/system/user", Code: "del"},
+		{Router: "/system/user", Code: "print"},
+		{Router: "/system/user", Code: "none"},
+		{Method: "GET", Path: "/api/v1/users/1"},
+		{Method: "POST", Path: "/api/v1/users"},
+	}
+	result, err := a.Check(ctx, 12, items)
+	if assert.Nil(t, err) && assert.Equal(t, len(items), len(result)) {
+		var allowed []bool
+		for i, item := range result {
+			assert.Equal(t, *items[i], item.PermissionCheckItem)
+			allowed = append(allowed, item.Allowed)
+		}
+		assert.Equal(t, []bool{true, false, false, false, true, false}, allowed)
+	}
+
+	// 限定角色的API密钥仅按限定的角色校验
+	result, err = a.Check(contextx.NewAPIKey(ctx, 1, []string{"3"}, true), 12, items[:1])
+	if assert.Nil(t, err) {
+		assert.False(t, result[0].Allowed)
+	}
+}
+
+func TestCheckRoutes(t *testing.T) {
+	casbinConfig := config.C.Casbin
+	defer func() { config.C.Casbin = casbinConfig }()
+	config.C.Casbin.Model = "../../../configs/model.conf"
+
+	a := newTestPermissionSrv(t, "routecheck")
+	ctx := context.Background()
+	assert.Nil(t, a.MenuActionResourceRepo.Create(ctx, schema.MenuActionResource{ID: 2, ActionID: 1, Method: "DELETE", Path: "/api/v1/users/:id"}))
+	assert.Nil(t, a.MenuActionResourceRepo.Create(ctx, schema.MenuActionResource{ID: 3, ActionID: 1, Method: "GET", Path: "/api/v1/users/*"}))
+	assert.Nil(t, a.MenuActionResourceRepo.Create(ctx, schema.MenuActionResource{ID: 4, ActionID: 1, Method: "GET", Path: "/api/v1/legacy"}))
+	assert.Nil(t, a.MenuActionResourceRepo.Create(ctx, schema.MenuActionResource{ID: 5, ActionID: 1}))
+
+	result, err := a.CheckRoutes(ctx, schema.Routes{
+		{Method: "GET", Path: "/api/v1/users/:id"},
+		{Method: "DELETE", Path: "/api/v1/users/:id"},
+		{Method: "POST", Path: "/api/v1/users"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, result.HasProblems())
+	assert.Equal(t, schema.Routes{{Method: "POST", Path: "/api/v1/users"}}, result.UnprotectedRoutes)
+	if assert.Equal(t, 1, len(result.StaleResources)) {
+		assert.Equal(t, uint64(4), result.StaleResources[0].ResourceID)
+		assert.Equal(t, "user", result.StaleResources[0].MenuName)
+		assert.Equal(t, "query", result.StaleResources[0].ActionCode)
+	}
+	if assert.Equal(t, 1, len(result.OverlappingRoutes)) {
+		assert.Equal(t, "GET", result.OverlappingRoutes[0].Method)
+		assert.Equal(t, schema.Routes{
+			{Method: "GET", Path: "/api/v1/users/:id"},
+			{Method: "GET", Path: "/api/v1/users/*"},
+		}, result.OverlappingRoutes[0].Patterns)
+	}
+
+	result, err = a.CheckRoutes(ctx, schema.Routes{
+		{Method: "DELETE", Path: "/api/v1/users/:id"},
+	})
+	if assert.Nil(t, err) {
+		assert.Empty(t, result.UnprotectedRoutes)
+		assert.Empty(t, result.OverlappingRoutes)
+		assert.Equal(t, 3, len(result.StaleResources))
+	}
+}
@@ -7,19 +7,27 @@ import (
 	"github.com/casbin/casbin/v2"
 	"github.com/google/wire"
 
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
 	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
 	"github.com/LyricTian/gin-admin/v8/pkg/errors"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
 )
 
 var RoleSet = wire.NewSet(wire.Struct(new(RoleSrv), "*"))
 
+// 角色继承的最大层级(casbin默认的角色层级上限为10，用户到角色占一级)
+const maxRoleInheritDepth = 9
+
 type RoleSrv struct {
 	Enforcer               *casbin.SyncedEnforcer
+	CasbinWatcher          *watcher.Watcher
 	TransRepo              *dao.TransRepo
 	RoleRepo               *dao.RoleRepo
 	RoleMenuRepo           *dao.RoleMenuRepo
+	RoleDataUserRepo       *dao.RoleDataUserRepo
+	RoleParentRepo         *dao.RoleParentRepo
 	UserRepo               *dao.UserRepo
 	MenuActionResourceRepo *dao.MenuActionResourceRepo
 }
@@ -42,6 +50,22 @@ func (a *RoleSrv) Get(ctx context.Context, id uint64, opts ...schema.RoleQueryOp
 	}
 	item.RoleMenus = roleMenus
 
+	roleDataUsers, err := a.RoleDataUserRepo.Query(ctx, schema.RoleDataUserQueryParam{
+		RoleID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	item.RoleDataUsers = roleDataUsers.Data
+
+	roleParents, err := a.RoleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+		RoleID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	item.RoleParents = roleParents.Data
+
 	return item, nil
 }
 
@@ -61,6 +85,12 @@ func (a *RoleSrv) Create(ctx context.Context, item schema.Role) (*schema.IDResul
 		return nil, err
 	}
 
+	item.RoleParents, err = a.checkRoleParents(ctx, 0, item.RoleParents)
+	if err != nil {
+		return nil, err
+	}
+
+	fillDataScope(&item)
 	item.ID = snowflake.MustID()
 	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
 		for _, rmItem := range item.RoleMenus {
@@ -71,27 +101,292 @@ func (a *RoleSrv) Create(ctx context.Context, item schema.Role) (*schema.IDResul
 				return err
 			}
 		}
+
+		for _, duItem := range item.RoleDataUsers {
+			duItem.ID = snowflake.MustID()
+			duItem.RoleID = item.ID
+			err := a.RoleDataUserRepo.Create(ctx, *duItem)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, rpItem := range item.RoleParents {
+			rpItem.ID = snowflake.MustID()
+			rpItem.RoleID = item.ID
+			err := a.RoleParentRepo.Create(ctx, *rpItem)
+			if err != nil {
+				return err
+			}
+		}
 		return a.RoleRepo.Create(ctx, item)
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	if item.Status == 1 {
+		err = a.loadPolicy(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schema.NewIDResult(item.ID), nil
+}
+
+// 加载角色的权限策略(p,role_id,tenant_id,path,method)及继承关系(g,role_id,parent_id,tenant_id)
+func (a *RoleSrv) loadPolicy(ctx context.Context, id uint64) error {
+	roleMenus, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{
+		RoleID: id,
+	})
+	if err != nil {
+		return err
+	}
+
 	resources, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{
-		MenuIDs: item.RoleMenus.ToMenuIDs(),
+		MenuIDs: roleMenus.Data.ToMenuIDs(),
+	})
+	if err != nil {
+		return err
+	}
+
+	roleParents, err := a.RoleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+		RoleID: id,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 仅包含角色分配的菜单动作的资源(与策略加载保持一致)
+	mActions := make(map[uint64]struct{})
+	for _, actionID := range roleMenus.Data.ToActionIDs() {
+		mActions[actionID] = struct{}{}
+	}
+
+	roleID := strconv.FormatUint(id, 10)
+	for _, ritem := range resources.Data {
+		if _, ok := mActions[ritem.ActionID]; !ok {
+			continue
+		}
+		a.Enforcer.AddPermissionForUser(roleID, tenantDomain(ctx), ritem.Path, ritem.Method)
+	}
+	for _, pitem := range roleParents.Data {
+		a.Enforcer.AddRoleForUserInDomain(roleID, strconv.FormatUint(pitem.ParentID, 10), tenantDomain(ctx))
+	}
+	return a.CasbinWatcher.Update()
+}
+
+// 移除角色的权限策略及继承关系(保留用户与角色的关系)
+func (a *RoleSrv) removePolicy(id uint64) {
+	roleID := strconv.FormatUint(id, 10)
+	a.Enforcer.DeletePermissionsForUser(roleID)
+	a.Enforcer.RemoveFilteredGroupingPolicy(0, roleID)
+	_ = a.CasbinWatcher.Update()
+}
+
+// 检查继承的上级角色：上级角色必须存在，不允许继承自身及下级角色(循环继承)，且继承层级不能超过上限
+func (a *RoleSrv) checkRoleParents(ctx context.Context, id uint64, roleParents schema.RoleParents) (schema.RoleParents, error) {
+	var list schema.RoleParents
+	mParents := make(map[uint64]struct{})
+	for _, item := range roleParents {
+		if item.ParentID == id {
+			return nil, errors.New400Response("不允许继承自身")
+		} else if _, ok := mParents[item.ParentID]; ok {
+			continue
+		}
+		mParents[item.ParentID] = struct{}{}
+		list = append(list, item)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	// 上级角色不受数据范围限制
+	result, err := a.RoleRepo.Query(contextx.NewNoDataScope(ctx), schema.RoleQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		IDs:             list.ToParentIDs(),
 	})
 	if err != nil {
 		return nil, err
+	} else if int(result.PageResult.Total) != len(list) {
+		return nil, errors.New400Response("继承的角色不存在")
 	}
-	for _, ritem := range resources.Data.ToMap() {
-		a.Enforcer.AddPermissionForUser(strconv.FormatUint(item.ID, 10), ritem.Path, ritem.Method)
+
+	allResult, err := a.RoleParentRepo.Query(ctx, schema.RoleParentQueryParam{})
+	if err != nil {
+		return nil, err
 	}
 
-	return schema.NewIDResult(item.ID), nil
+	// 不包含当前角色原有的继承关系
+	parents := make(map[uint64][]uint64)
+	children := make(map[uint64][]uint64)
+	for _, item := range allResult.Data {
+		if item.RoleID == id {
+			continue
+		}
+		parents[item.RoleID] = append(parents[item.RoleID], item.ParentID)
+		children[item.ParentID] = append(children[item.ParentID], item.RoleID)
+	}
+
+	depth := 0
+	mDepth := make(map[uint64]int)
+	for _, item := range list {
+		if id != 0 && hasRoleAncestor(parents, item.ParentID, id) {
+			return nil, errors.New400Response("不允许继承下级角色")
+		}
+		if d := roleInheritDepth(parents, item.ParentID, mDepth); d > depth {
+			depth = d
+		}
+	}
+	if id != 0 {
+		depth += roleInheritDepth(children, id, make(map[uint64]int))
+	}
+	if depth+1 > maxRoleInheritDepth {
+		return nil, errors.New400Response("角色继承层级过深")
+	}
+
+	return list, nil
+}
+
+// 检查角色的上级角色(包含间接继承)中是否存在指定角色
+func hasRoleAncestor(parents map[uint64][]uint64, id, ancestorID uint64) bool {
+	visited := make(map[uint64]struct{})
+	queue := []uint64{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == ancestorID {
+			return true
+		} else if _, ok := visited[cur]; ok {
+			continue
+		}
+		visited[cur] = struct{}{}
+		queue = append(queue, parents[cur]...)
+	}
+	return false
+}
+
+// 获取沿继承关系的最长层级数
+func roleInheritDepth(next map[uint64][]uint64, id uint64, memo map[uint64]int) int {
+	if d, ok := memo[id]; ok {
+		return d
+	}
+	memo[id] = 0 // 防止异常数据中的循环继承
+
+	depth := 0
+	for _, nid := range next[id] {
+		if d := roleInheritDepth(next, nid, memo) + 1; d > depth {
+			depth = d
+		}
+	}
+	memo[id] = depth
+	return depth
+}
+
+// queryInheritedRoleIDs 获取角色及其继承的全部上级角色ID(仅包含启用的角色，禁用的角色不再向上继承)
+func queryInheritedRoleIDs(ctx context.Context, roleRepo *dao.RoleRepo, roleParentRepo *dao.RoleParentRepo, roleIDs []uint64) ([]uint64, error) {
+	var idList []uint64
+	mIDList := make(map[uint64]struct{})
+	for len(roleIDs) > 0 {
+		result, err := roleRepo.Query(contextx.NewNoDataScope(ctx), schema.RoleQueryParam{
+			IDs:    roleIDs,
+			Status: 1,
+		}, schema.RoleQueryOptions{
+			SelectFields: []string{"id"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var ids []uint64
+		for _, item := range result.Data {
+			if _, ok := mIDList[item.ID]; ok {
+				continue
+			}
+			mIDList[item.ID] = struct{}{}
+			ids = append(ids, item.ID)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		idList = append(idList, ids...)
+
+		parentResult, err := roleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+			RoleIDs: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		roleIDs = nil
+		for _, item := range parentResult.Data {
+			if _, ok := mIDList[item.ParentID]; !ok {
+				roleIDs = append(roleIDs, item.ParentID)
+			}
+		}
+	}
+	return idList, nil
+}
+
+// queryInheritingRoleIDs 获取角色及继承了这些角色的全部下级角色ID(仅包含启用的角色，禁用的角色不再向下传递)
+func queryInheritingRoleIDs(ctx context.Context, roleRepo *dao.RoleRepo, roleParentRepo *dao.RoleParentRepo, roleIDs []uint64) ([]uint64, error) {
+	var idList []uint64
+	mIDList := make(map[uint64]struct{})
+	for len(roleIDs) > 0 {
+		result, err := roleRepo.Query(contextx.NewNoDataScope(ctx), schema.RoleQueryParam{
+			IDs:    roleIDs,
+			Status: 1,
+		}, schema.RoleQueryOptions{
+			SelectFields: []string{"id"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var ids []uint64
+		for _, item := range result.Data {
+			if _, ok := mIDList[item.ID]; ok {
+				continue
+			}
+			mIDList[item.ID] = struct{}{}
+			ids = append(ids, item.ID)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		idList = append(idList, ids...)
+
+		childResult, err := roleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+			ParentIDs: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		roleIDs = nil
+		for _, item := range childResult.Data {
+			if _, ok := mIDList[item.RoleID]; !ok {
+				roleIDs = append(roleIDs, item.RoleID)
+			}
+		}
+	}
+	return idList, nil
+}
+
+// 默认为全部数据，非自定义数据范围时不保留指定的用户
+func fillDataScope(item *schema.Role) {
+	if item.DataScope == 0 {
+		item.DataScope = schema.DataScopeAll
+	}
+	if item.DataScope != schema.DataScopeCustom {
+		item.RoleDataUsers = nil
+	}
 }
 
 func (a *RoleSrv) checkName(ctx context.Context, item schema.Role) error {
-	result, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{
+	// 名称在全部角色中唯一，不受数据范围限制
+	result, err := a.RoleRepo.Query(contextx.NewNoDataScope(ctx), schema.RoleQueryParam{
 		PaginationParam: schema.PaginationParam{OnlyCount: true},
 		Name:            item.Name,
 	})
@@ -116,6 +411,12 @@ func (a *RoleSrv) Update(ctx context.Context, id uint64, item schema.Role) error
 		}
 	}
 
+	item.RoleParents, err = a.checkRoleParents(ctx, id, item.RoleParents)
+	if err != nil {
+		return err
+	}
+
+	fillDataScope(&item)
 	item.ID = oldItem.ID
 	item.Creator = oldItem.Creator
 	item.CreatedAt = oldItem.CreatedAt
@@ -137,31 +438,50 @@ func (a *RoleSrv) Update(ctx context.Context, id uint64, item schema.Role) error
 			}
 		}
 
-		return a.RoleRepo.Update(ctx, id, item)
-	})
-	if err != nil {
-		return err
-	}
+		addDataUsers, delDataUsers := a.compareRoleDataUsers(ctx, oldItem.RoleDataUsers, item.RoleDataUsers)
+		for _, duitem := range addDataUsers {
+			duitem.ID = snowflake.MustID()
+			duitem.RoleID = id
+			err := a.RoleDataUserRepo.Create(ctx, *duitem)
+			if err != nil {
+				return err
+			}
+		}
 
-	roleMenus, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{
-		RoleID: id,
-	})
-	if err != nil {
-		return err
-	}
+		for _, duitem := range delDataUsers {
+			err := a.RoleDataUserRepo.Delete(ctx, duitem.ID)
+			if err != nil {
+				return err
+			}
+		}
 
-	resources, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{
-		MenuIDs: roleMenus.Data.ToMenuIDs(),
+		addParents, delParents := a.compareRoleParents(ctx, oldItem.RoleParents, item.RoleParents)
+		for _, rpitem := range addParents {
+			rpitem.ID = snowflake.MustID()
+			rpitem.RoleID = id
+			err := a.RoleParentRepo.Create(ctx, *rpitem)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, rpitem := range delParents {
+			err := a.RoleParentRepo.Delete(ctx, rpitem.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return a.RoleRepo.Update(ctx, id, item)
 	})
 	if err != nil {
 		return err
 	}
 
-	a.Enforcer.DeleteRole(strconv.FormatUint(item.ID, 10))
-	for _, ritem := range resources.Data.ToMap() {
-		a.Enforcer.AddPermissionForUser(strconv.FormatUint(item.ID, 10), ritem.Path, ritem.Method)
+	a.removePolicy(id)
+	if item.Status == 1 {
+		return a.loadPolicy(ctx, id)
 	}
-
 	return nil
 }
 
@@ -183,6 +503,42 @@ func (a *RoleSrv) compareRoleMenus(ctx context.Context, oldRoleMenus, newRoleMen
 	return
 }
 
+func (a *RoleSrv) compareRoleDataUsers(ctx context.Context, oldDataUsers, newDataUsers schema.RoleDataUsers) (addList, delList schema.RoleDataUsers) {
+	mOldDataUsers := oldDataUsers.ToMap()
+	mNewDataUsers := newDataUsers.ToMap()
+
+	for k, item := range mNewDataUsers {
+		if _, ok := mOldDataUsers[k]; ok {
+			delete(mOldDataUsers, k)
+			continue
+		}
+		addList = append(addList, item)
+	}
+
+	for _, item := range mOldDataUsers {
+		delList = append(delList, item)
+	}
+	return
+}
+
+func (a *RoleSrv) compareRoleParents(ctx context.Context, oldParents, newParents schema.RoleParents) (addList, delList schema.RoleParents) {
+	mOldParents := oldParents.ToMap()
+	mNewParents := newParents.ToMap()
+
+	for k, item := range mNewParents {
+		if _, ok := mOldParents[k]; ok {
+			delete(mOldParents, k)
+			continue
+		}
+		addList = append(addList, item)
+	}
+
+	for _, item := range mOldParents {
+		delList = append(delList, item)
+	}
+	return
+}
+
 func (a *RoleSrv) Delete(ctx context.Context, id uint64) error {
 	oldItem, err := a.RoleRepo.Get(ctx, id)
 	if err != nil {
@@ -191,7 +547,8 @@ func (a *RoleSrv) Delete(ctx context.Context, id uint64) error {
 		return errors.ErrNotFound
 	}
 
-	userResult, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+	// 范围外的用户同样占用角色
+	userResult, err := a.UserRepo.Query(contextx.NewNoDataScope(ctx), schema.UserQueryParam{
 		PaginationParam: schema.PaginationParam{OnlyCount: true},
 		RoleIDs:         []uint64{id},
 	})
@@ -201,18 +558,39 @@ func (a *RoleSrv) Delete(ctx context.Context, id uint64) error {
 		return errors.New400Response("不允许删除已经存在用户的角色")
 	}
 
+	childResult, err := a.RoleParentRepo.Query(ctx, schema.RoleParentQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		ParentIDs:       []uint64{id},
+	})
+	if err != nil {
+		return err
+	} else if childResult.PageResult.Total > 0 {
+		return errors.New400Response("不允许删除被其他角色继承的角色")
+	}
+
 	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
 		err := a.RoleMenuRepo.DeleteByRoleID(ctx, id)
 		if err != nil {
 			return err
 		}
 
+		err = a.RoleDataUserRepo.DeleteByRoleID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = a.RoleParentRepo.DeleteByRoleID(ctx, id)
+		if err != nil {
+			return err
+		}
+
 		return a.RoleRepo.Delete(ctx, id)
 	})
 	if err != nil {
 		return err
 	}
 
+	a.removePolicy(id)
 	a.Enforcer.DeleteRole(strconv.FormatUint(id, 10))
 
 	return nil
@@ -233,27 +611,11 @@ func (a *RoleSrv) UpdateStatus(ctx context.Context, id uint64, status int) error
 		return err
 	}
 
+	// 禁用时保留用户与角色的关系，启用后恢复权限
 	if status == 1 {
-		roleMenus, err := a.RoleMenuRepo.Query(ctx, schema.RoleMenuQueryParam{
-			RoleID: id,
-		})
-		if err != nil {
-			return err
-		}
-
-		resources, err := a.MenuActionResourceRepo.Query(ctx, schema.MenuActionResourceQueryParam{
-			MenuIDs: roleMenus.Data.ToMenuIDs(),
-		})
-		if err != nil {
-			return err
-		}
-
-		for _, ritem := range resources.Data.ToMap() {
-			a.Enforcer.AddPermissionForUser(strconv.FormatUint(id, 10), ritem.Path, ritem.Method)
-		}
-	} else {
-		a.Enforcer.DeleteRole(strconv.FormatUint(id, 10))
+		return a.loadPolicy(ctx, id)
 	}
+	a.removePolicy(id)
 
 	return nil
 }
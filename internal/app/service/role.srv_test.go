@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+)
+
+func TestRoleInheritance(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:roleinherit?mode=memory&cache=shared"), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, dao.AutoMigrate(db))
+
+	a := &RoleSrv{
+		RoleRepo:       &dao.RoleRepo{DB: db},
+		RoleParentRepo: &dao.RoleParentRepo{DB: db},
+	}
+
+	// 角色链：1 -> 2 -> ... -> 10(角色n继承角色n+1)，角色5禁用
+	ctx := context.Background()
+	for i := uint64(1); i <= 11; i++ {
+		status := 1
+		if i == 5 {
+			status = 2
+		}
+		assert.Nil(t, a.RoleRepo.Create(ctx, schema.Role{ID: i, Name: string(rune('a' + i)), Status: status}))
+		if i < 10 {
+			assert.Nil(t, a.RoleParentRepo.Create(ctx, schema.RoleParent{ID: i, RoleID: i, ParentID: i + 1}))
+		}
+	}
+
+	// 禁用的角色不再向上继承
+	roleIDs, err := queryInheritedRoleIDs(ctx, a.RoleRepo, a.RoleParentRepo, []uint64{1})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []uint64{1, 2, 3, 4}, roleIDs)
+
+	roleIDs, err = queryInheritedRoleIDs(ctx, a.RoleRepo, a.RoleParentRepo, []uint64{6})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []uint64{6, 7, 8, 9, 10}, roleIDs)
+
+	// 循环继承
+	_, err = a.checkRoleParents(ctx, 5, schema.RoleParents{{ParentID: 3}})
+	assert.NotNil(t, err)
+	_, err = a.checkRoleParents(ctx, 5, schema.RoleParents{{ParentID: 5}})
+	assert.NotNil(t, err)
+
+	// 继承层级：已有9级时不允许继续继承
+	_, err = a.checkRoleParents(ctx, 9, schema.RoleParents{{ParentID: 10}})
+	assert.Nil(t, err)
+	_, err = a.checkRoleParents(ctx, 11, schema.RoleParents{{ParentID: 1}})
+	assert.NotNil(t, err)
+	_, err = a.checkRoleParents(ctx, 10, schema.RoleParents{{ParentID: 11}})
+	assert.NotNil(t, err)
+
+	list, err := a.checkRoleParents(ctx, 0, schema.RoleParents{{ParentID: 11}, {ParentID: 11}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(list))
+}
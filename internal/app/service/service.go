@@ -6,7 +6,20 @@ import (
 
 var ServiceSet = wire.NewSet(
 	MenuSet,
+	DeptSet,
 	RoleSet,
 	UserSet,
 	LoginSet,
+	SessionSet,
+	PasswordSet,
+	LockoutSet,
+	MFASet,
+	APIKeySet,
+	OIDCSet,
+	IdentitySet,
+	AuthenticatorSet,
+	DataScopeSet,
+	PermissionSet,
+	SuperAdminSet,
+	TenantSet,
 ) // end
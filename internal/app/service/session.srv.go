@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+var SessionSet = wire.NewSet(wire.Struct(new(SessionSrv), "*"))
+
+type SessionSrv struct {
+	Auth     auth.Auther
+	UserRepo *dao.UserRepo
+}
+
+// 用户对应的令牌主体(与登录时签发的令牌一致)
+func tokenSubject(userID uint64, userName string) string {
+	return fmt.Sprintf("%d-%s", userID, userName)
+}
+
+// 解析令牌主体中的用户ID与用户名
+func parseTokenSubject(subject string) (uint64, string, bool) {
+	idx := strings.Index(subject, "-")
+	if idx == -1 {
+		return 0, "", false
+	}
+
+	userID, err := strconv.ParseUint(subject[:idx], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return userID, subject[idx+1:], true
+}
+
+func (a *SessionSrv) getSubject(ctx context.Context, userID uint64) (string, error) {
+	user, err := a.UserRepo.Get(ctx, userID)
+	if err != nil {
+		return "", err
+	} else if user == nil {
+		return "", errors.ErrNotFound
+	}
+	return tokenSubject(user.ID, user.UserName), nil
+}
+
+func (a *SessionSrv) Query(ctx context.Context, userID uint64) (schema.UserSessions, error) {
+	subject, err := a.getSubject(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := a.Auth.QuerySessions(ctx, subject)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	list := make(schema.UserSessions, len(sessions))
+	for i, item := range sessions {
+		list[i] = &schema.UserSession{
+			ID:         item.ID,
+			IP:         item.IP,
+			UserAgent:  item.UserAgent,
+			CreatedAt:  time.Unix(item.CreatedAt, 0),
+			LastSeenAt: time.Unix(item.LastSeenAt, 0),
+			ExpiresAt:  time.Unix(item.ExpiresAt, 0),
+		}
+	}
+	return list, nil
+}
+
+func (a *SessionSrv) Revoke(ctx context.Context, userID uint64, sessionID string) error {
+	subject, err := a.getSubject(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = a.Auth.RevokeSession(ctx, subject, sessionID)
+	if err == auth.ErrSessionNotFound {
+		return errors.ErrNotFound
+	}
+	return errors.WithStack(err)
+}
+
+func (a *SessionSrv) RevokeAll(ctx context.Context, userID uint64) error {
+	subject, err := a.getSubject(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithStack(a.Auth.RevokeAllSessions(ctx, subject))
+}
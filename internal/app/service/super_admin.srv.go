@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var SuperAdminSet = wire.NewSet(wire.Struct(new(SuperAdminSrv), "*"))
+
+// SuperAdminSrv 超级管理员(用户表中标记的用户，不受权限及数据范围限制，可以有多个)
+type SuperAdminSrv struct {
+	Auth          auth.Auther
+	Enforcer      *casbin.SyncedEnforcer
+	CasbinWatcher *watcher.Watcher
+	TransRepo     *dao.TransRepo
+	UserRepo      *dao.UserRepo
+	UserRoleRepo  *dao.UserRoleRepo
+	PasswordSrv   *PasswordSrv
+}
+
+// IsSuperAdmin 检查用户是否为启用的超级管理员(限定了角色的API密钥仅按限定的角色校验，不视为超级管理员)
+func (a *SuperAdminSrv) IsSuperAdmin(ctx context.Context, userID uint64) (bool, error) {
+	if _, ok := contextx.FromAPIKeyRoles(ctx); ok || userID == 0 {
+		return false, nil
+	}
+
+	user, err := a.UserRepo.Get(contextx.NewNoDataScope(ctx), userID)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && user.Status == 1 && user.IsSuperAdmin, nil
+}
+
+// GetDefaultUser 获取配置的平台超级管理员(未开启认证时作为当前用户)
+func (a *SuperAdminSrv) GetDefaultUser(ctx context.Context) (*schema.User, error) {
+	userName := config.C.SuperAdmin.UserName
+	if userName == "" {
+		return nil, errors.ErrInvalidToken
+	}
+
+	ctx = contextx.NewTenantID(ctx, schema.PlatformTenantID)
+	result, err := a.UserRepo.Query(contextx.NewNoDataScope(ctx), schema.UserQueryParam{
+		UserName:   userName,
+		Status:     1,
+		SuperAdmin: true,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(result.Data) == 0 {
+		return nil, errors.ErrInvalidToken
+	}
+	return result.Data[0], nil
+}
+
+// InitData 平台不存在超级管理员时按配置创建(同名用户已存在时将其设为超级管理员，不修改密码)
+func (a *SuperAdminSrv) InitData(ctx context.Context) error {
+	cfg := config.C.SuperAdmin
+	if cfg.UserName == "" {
+		return nil
+	}
+
+	ctx = contextx.NewNoDataScope(withPlatformTenant(ctx))
+	n, err := a.count(ctx)
+	if err != nil {
+		return err
+	} else if n > 0 {
+		return nil
+	}
+
+	user, err := a.getByUserName(ctx, cfg.UserName)
+	if err != nil {
+		return err
+	}
+
+	params := schema.SuperAdminParam{
+		UserName: cfg.UserName,
+		Password: cfg.Password,
+		RealName: cfg.RealName,
+	}
+	if user != nil {
+		params.Password = ""
+	}
+	_, err = a.Create(ctx, params)
+	return err
+}
+
+// Create 在当前租户(未指定时为平台)创建超级管理员，用户名已存在时将该用户设为超级管理员并启用(指定了密码时同时重置密码)
+func (a *SuperAdminSrv) Create(ctx context.Context, params schema.SuperAdminParam) (*schema.User, error) {
+	if params.UserName == "" {
+		return nil, errors.New400Response("用户名不能为空")
+	}
+
+	ctx = contextx.NewNoDataScope(withPlatformTenant(ctx))
+	user, err := a.getByUserName(ctx, params.UserName)
+	if err != nil {
+		return nil, err
+	} else if user == nil && params.Password == "" {
+		return nil, errors.New400Response("创建超级管理员时密码不能为空")
+	}
+
+	var password string
+	if params.Password != "" {
+		password, err = a.PasswordSrv.Hash(params.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		return a.create(ctx, params, password)
+	}
+
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.UserRepo.UpdateSuperAdmin(ctx, user.ID, true)
+		if err != nil {
+			return err
+		}
+
+		err = a.UserRepo.UpdateStatus(ctx, user.ID, 1)
+		if err != nil {
+			return err
+		}
+
+		if password == "" {
+			return nil
+		}
+		err = a.UserRepo.ChangePassword(ctx, user.ID, password, false)
+		if err != nil {
+			return err
+		}
+		return a.PasswordSrv.SaveHistory(ctx, user.ID, password)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 停用的用户启用后恢复其角色
+	if user.Status != 1 {
+		userRoleResult, err := a.UserRoleRepo.Query(ctx, schema.UserRoleQueryParam{
+			UserID: user.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, urItem := range userRoleResult.Data {
+			a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(user.ID, 10), strconv.FormatUint(urItem.RoleID, 10), tenantDomain(ctx))
+		}
+	}
+	a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(user.ID, 10), schema.SuperAdminRole, tenantDomain(ctx))
+	_ = a.CasbinWatcher.Update()
+
+	if password != "" {
+		err := a.Auth.RevokeUser(ctx, tokenSubject(user.ID, user.UserName))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	user.Status = 1
+	user.IsSuperAdmin = true
+	return user.CleanSecure(), nil
+}
+
+func (a *SuperAdminSrv) create(ctx context.Context, params schema.SuperAdminParam, password string) (*schema.User, error) {
+	now := time.Now()
+	item := schema.User{
+		ID:                snowflake.MustID(),
+		UserName:          params.UserName,
+		RealName:          params.RealName,
+		Password:          password,
+		Status:            1,
+		PasswordChangedAt: &now,
+		IsSuperAdmin:      true,
+	}
+	if item.RealName == "" {
+		item.RealName = item.UserName
+	}
+
+	err := a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.PasswordSrv.SaveHistory(ctx, item.ID, item.Password)
+		if err != nil {
+			return err
+		}
+		return a.UserRepo.Create(ctx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(item.ID, 10), schema.SuperAdminRole, tenantDomain(ctx))
+	_ = a.CasbinWatcher.Update()
+	return item.CleanSecure(), nil
+}
+
+func (a *SuperAdminSrv) getByUserName(ctx context.Context, userName string) (*schema.User, error) {
+	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+		UserName: userName,
+	})
+	if err != nil {
+		return nil, err
+	} else if len(result.Data) == 0 {
+		return nil, nil
+	}
+	return result.Data[0], nil
+}
+
+// 启用的超级管理员数量
+func (a *SuperAdminSrv) count(ctx context.Context) (int64, error) {
+	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		Status:          1,
+		SuperAdmin:      true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.PageResult.Total, nil
+}
+
+// 仅超级管理员可以设置超级管理员，或修改、删除超级管理员
+func (a *SuperAdminSrv) checkOperator(ctx context.Context) error {
+	ok, err := a.IsSuperAdmin(ctx, contextx.FromUserID(ctx))
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.NewResponse(0, 403, "仅超级管理员可以设置或修改超级管理员")
+	}
+	return nil
+}
+
+// 停用、删除或取消启用的超级管理员时，用户所属租户至少保留一个启用的超级管理员
+func (a *SuperAdminSrv) checkRemain(ctx context.Context, user *schema.User) error {
+	if !user.IsSuperAdmin || user.Status != 1 {
+		return nil
+	}
+
+	n, err := a.count(contextx.NewNoDataScope(contextx.NewTenantID(ctx, user.TenantID)))
+	if err != nil {
+		return err
+	} else if n <= 1 {
+		return errors.New400Response("至少保留一个启用的超级管理员")
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/wire"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
+)
+
+var TenantSet = wire.NewSet(wire.Struct(new(TenantSrv), "*"))
+
+// 租户编号用于子域名及请求头(小写字母、数字及中划线)
+var tenantCodeRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// TenantSrv 租户管理(仅平台用户可以管理租户)
+type TenantSrv struct {
+	TransRepo     *dao.TransRepo
+	TenantRepo    *dao.TenantRepo
+	UserRepo      *dao.UserRepo
+	RoleRepo      *dao.RoleRepo
+	SuperAdminSrv *SuperAdminSrv
+}
+
+// 当前请求所属租户的casbin域(未区分租户时为平台)
+func tenantDomain(ctx context.Context) string {
+	tenantID, _ := contextx.FromTenantID(ctx)
+	return schema.TenantDomain(tenantID)
+}
+
+// 未指定租户时使用平台(用于启动初始化及命令行)
+func withPlatformTenant(ctx context.Context) context.Context {
+	if _, ok := contextx.FromTenantID(ctx); ok {
+		return ctx
+	}
+	return contextx.NewTenantID(ctx, schema.PlatformTenantID)
+}
+
+// 仅平台用户可以执行的操作(管理租户、修改菜单)
+func checkPlatform(ctx context.Context) error {
+	if tenantID, _ := contextx.FromTenantID(ctx); tenantID != schema.PlatformTenantID {
+		return errors.NewResponse(0, 403, "仅平台用户可以执行该操作")
+	}
+	return nil
+}
+
+func (a *TenantSrv) Query(ctx context.Context, params schema.TenantQueryParam, opts ...schema.TenantQueryOptions) (*schema.TenantQueryResult, error) {
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+	return a.TenantRepo.Query(ctx, params, opts...)
+}
+
+func (a *TenantSrv) Get(ctx context.Context, id uint64, opts ...schema.TenantQueryOptions) (*schema.Tenant, error) {
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+
+	item, err := a.TenantRepo.Get(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, errors.ErrNotFound
+	}
+	return item, nil
+}
+
+// GetEnabledID 按租户编号获取启用的租户ID(用于识别请求的租户)
+func (a *TenantSrv) GetEnabledID(ctx context.Context, code string) (uint64, error) {
+	result, err := a.TenantRepo.Query(ctx, schema.TenantQueryParam{
+		Code:   code,
+		Status: 1,
+	})
+	if err != nil {
+		return 0, err
+	} else if len(result.Data) == 0 {
+		return 0, errors.NewResponse(0, 403, "租户不存在或已停用")
+	}
+	return result.Data[0].ID, nil
+}
+
+// CheckEnabled 检查租户是否存在且启用
+func (a *TenantSrv) CheckEnabled(ctx context.Context, id uint64) error {
+	item, err := a.TenantRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if item == nil || item.Status != 1 {
+		return errors.NewResponse(0, 403, "租户不存在或已停用")
+	}
+	return nil
+}
+
+func (a *TenantSrv) checkCode(ctx context.Context, code string) error {
+	if !tenantCodeRegexp.MatchString(code) {
+		return errors.New400Response("租户编号只能包含小写字母、数字及中划线")
+	}
+
+	result, err := a.TenantRepo.Query(ctx, schema.TenantQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+		Code:            code,
+	})
+	if err != nil {
+		return err
+	} else if result.PageResult.Total > 0 {
+		return errors.New400Response("租户编号已经存在")
+	}
+	return nil
+}
+
+// Create 创建租户，指定了管理员用户名时同时创建租户的超级管理员
+func (a *TenantSrv) Create(ctx context.Context, item schema.Tenant) (*schema.IDResult, error) {
+	if err := checkPlatform(ctx); err != nil {
+		return nil, err
+	}
+
+	err := a.checkCode(ctx, item.Code)
+	if err != nil {
+		return nil, err
+	} else if item.AdminUserName != "" && item.AdminPassword == "" {
+		return nil, errors.New400Response("租户管理员密码不能为空")
+	}
+
+	item.ID = snowflake.MustID()
+	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.TenantRepo.Create(ctx, item)
+		if err != nil {
+			return err
+		}
+
+		if item.AdminUserName == "" {
+			return nil
+		}
+		_, err = a.SuperAdminSrv.Create(contextx.NewTenantID(ctx, item.ID), schema.SuperAdminParam{
+			UserName: item.AdminUserName,
+			Password: item.AdminPassword,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.NewIDResult(item.ID), nil
+}
+
+func (a *TenantSrv) Update(ctx context.Context, id uint64, item schema.Tenant) error {
+	oldItem, err := a.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem.Code != item.Code {
+		if err := a.checkCode(ctx, item.Code); err != nil {
+			return err
+		}
+	}
+
+	item.ID = oldItem.ID
+	item.Creator = oldItem.Creator
+	item.CreatedAt = oldItem.CreatedAt
+	return a.TenantRepo.Update(ctx, id, item)
+}
+
+// Delete 删除租户(租户下存在用户或角色时不允许删除)
+func (a *TenantSrv) Delete(ctx context.Context, id uint64) error {
+	_, err := a.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tctx := contextx.NewNoDataScope(contextx.NewTenantID(ctx, id))
+	userResult, err := a.UserRepo.Query(tctx, schema.UserQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+	})
+	if err != nil {
+		return err
+	}
+	roleResult, err := a.RoleRepo.Query(tctx, schema.RoleQueryParam{
+		PaginationParam: schema.PaginationParam{OnlyCount: true},
+	})
+	if err != nil {
+		return err
+	} else if userResult.PageResult.Total > 0 || roleResult.PageResult.Total > 0 {
+		return errors.New400Response("不允许删除存在用户或角色的租户")
+	}
+
+	return a.TenantRepo.Delete(ctx, id)
+}
+
+// UpdateStatus 更新租户状态(停用后该租户的请求均被拒绝)
+func (a *TenantSrv) UpdateStatus(ctx context.Context, id uint64, status int) error {
+	_, err := a.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.TenantRepo.UpdateStatus(ctx, id, status)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+// 树形数据(菜单、部门)通过父级路径(上级ID以"/"连接)维护层级关系
+
+// treeNode 树节点
+type treeNode struct {
+	ID         uint64
+	ParentPath string
+}
+
+// treeStore 树节点的读写
+type treeStore interface {
+	// 获取节点(不存在时返回nil)
+	getNode(ctx context.Context, id uint64) (*treeNode, error)
+	// 查询父级路径以指定值开头的节点
+	queryByPrefixParentPath(ctx context.Context, prefix string) ([]*treeNode, error)
+	// 更新节点的父级路径
+	updateParentPath(ctx context.Context, id uint64, parentPath string) error
+}
+
+func joinParentPath(parent string, id uint64) string {
+	if parent != "" {
+		parent += "/"
+	}
+
+	return fmt.Sprintf("%s%d", parent, id)
+}
+
+// 检查父级路径中是否包含指定节点
+func hasParentPathID(parentPath string, id uint64) bool {
+	sid := strconv.FormatUint(id, 10)
+	for _, pp := range strings.Split(parentPath, "/") {
+		if pp == sid {
+			return true
+		}
+	}
+	return false
+}
+
+// 检查父级路径是否在指定路径下(包含自身)
+func isSubParentPath(parentPath, path string) bool {
+	return parentPath == path || strings.HasPrefix(parentPath, path+"/")
+}
+
+// getTreeParentPath 获取节点(id为0时表示新建节点)挂到父级下的父级路径，父级不存在或为节点自身及其下级时返回错误
+func getTreeParentPath(ctx context.Context, s treeStore, id, parentID uint64) (string, error) {
+	if parentID == 0 {
+		return "", nil
+	} else if id != 0 && id == parentID {
+		return "", errors.ErrInvalidParent
+	}
+
+	pitem, err := s.getNode(ctx, parentID)
+	if err != nil {
+		return "", err
+	} else if pitem == nil {
+		return "", errors.ErrInvalidParent
+	} else if id != 0 && hasParentPathID(pitem.ParentPath, id) {
+		return "", errors.ErrInvalidParent
+	}
+
+	return joinParentPath(pitem.ParentPath, pitem.ID), nil
+}
+
+// updateTreeChildParentPath 节点的父级路径变更后，同步更新所有下级节点的父级路径
+func updateTreeChildParentPath(ctx context.Context, s treeStore, id uint64, oldParentPath, newParentPath string) error {
+	if oldParentPath == newParentPath {
+		return nil
+	}
+
+	opath := joinParentPath(oldParentPath, id)
+	// 在当前事务中查询，可读取到同一事务中已移动的节点
+	list, err := s.queryByPrefixParentPath(ctx, opath)
+	if err != nil {
+		return err
+	}
+
+	npath := joinParentPath(newParentPath, id)
+	for _, item := range list {
+		// 前缀匹配可能包含ID前缀相同的其他节点
+		if !isSubParentPath(item.ParentPath, opath) {
+			continue
+		}
+
+		err = s.updateParentPath(ctx, item.ID, npath+item.ParentPath[len(opath):])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
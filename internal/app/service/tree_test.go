@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/errors"
+)
+
+type testTreeStore map[uint64]*treeNode
+
+func (s testTreeStore) getNode(ctx context.Context, id uint64) (*treeNode, error) {
+	return s[id], nil
+}
+
+func (s testTreeStore) queryByPrefixParentPath(ctx context.Context, prefix string) ([]*treeNode, error) {
+	var list []*treeNode
+	for _, item := range s {
+		if strings.HasPrefix(item.ParentPath, prefix) {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+func (s testTreeStore) updateParentPath(ctx context.Context, id uint64, parentPath string) error {
+	s[id].ParentPath = parentPath
+	return nil
+}
+
+func TestTreeMove(t *testing.T) {
+	// 1 -> 2 -> 3, 12(ID前缀与1相同), 4
+	s := testTreeStore{
+		1:  {ID: 1},
+		2:  {ID: 2, ParentPath: "1"},
+		3:  {ID: 3, ParentPath: "1/2"},
+		12: {ID: 12},
+		4:  {ID: 4},
+	}
+	ctx := context.Background()
+
+	// 不允许移动到自身或下级
+	for _, parentID := range []uint64{1, 2, 3} {
+		_, err := getTreeParentPath(ctx, s, 1, parentID)
+		assert.Equal(t, errors.ErrInvalidParent, err)
+	}
+	_, err := getTreeParentPath(ctx, s, 1, 99)
+	assert.Equal(t, errors.ErrInvalidParent, err)
+
+	parentPath, err := getTreeParentPath(ctx, s, 2, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, "12", parentPath)
+	parentPath, err = getTreeParentPath(ctx, s, 0, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "1/2/3", parentPath)
+
+	// 1移动到4下，下级节点的父级路径同步更新
+	parentPath, err = getTreeParentPath(ctx, s, 1, 4)
+	assert.Nil(t, err)
+	assert.Nil(t, updateTreeChildParentPath(ctx, s, 1, "", parentPath))
+	s[1].ParentPath = parentPath
+	assert.Equal(t, "4/1", s[2].ParentPath)
+	assert.Equal(t, "4/1/2", s[3].ParentPath)
+	assert.Equal(t, "", s[12].ParentPath)
+
+	// 4已是1的上级，不允许再移动到1的下级
+	_, err = getTreeParentPath(ctx, s, 4, 3)
+	assert.Equal(t, errors.ErrInvalidParent, err)
+}
@@ -3,12 +3,16 @@ package service
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/google/wire"
 
+	"github.com/LyricTian/gin-admin/v8/internal/app/contextx"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao"
 	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
 	"github.com/LyricTian/gin-admin/v8/pkg/errors"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/snowflake"
@@ -17,19 +21,49 @@ import (
 var UserSet = wire.NewSet(wire.Struct(new(UserSrv), "*"))
 
 type UserSrv struct {
-	Enforcer     *casbin.SyncedEnforcer
-	TransRepo    *dao.TransRepo
-	UserRepo     *dao.UserRepo
-	UserRoleRepo *dao.UserRoleRepo
-	RoleRepo     *dao.RoleRepo
+	Auth             auth.Auther
+	Enforcer         *casbin.SyncedEnforcer
+	CasbinWatcher    *watcher.Watcher
+	TransRepo        *dao.TransRepo
+	UserRepo         *dao.UserRepo
+	UserRoleRepo     *dao.UserRoleRepo
+	UserDeptRepo     *dao.UserDeptRepo
+	RoleRepo         *dao.RoleRepo
+	RoleDataUserRepo *dao.RoleDataUserRepo
+	DeptSrv          *DeptSrv
+	PasswordHasher   hash.PasswordHasher
+	PasswordSrv      *PasswordSrv
+	LockoutSrv       *LockoutSrv
+	MFASrv           *MFASrv
+	APIKeySrv        *APIKeySrv
+	UserIdentityRepo *dao.UserIdentityRepo
+	SuperAdminSrv    *SuperAdminSrv
 }
 
 func (a *UserSrv) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	err := a.fillDeptParam(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
 	return a.UserRepo.Query(ctx, params, opts...)
 }
 
+// 按部门查询时包含所有下级部门
+func (a *UserSrv) fillDeptParam(ctx context.Context, params *schema.UserQueryParam) error {
+	if params.DeptID == 0 {
+		return nil
+	}
+
+	deptIDs, err := a.DeptSrv.QuerySubIDs(ctx, params.DeptID)
+	if err != nil {
+		return err
+	}
+	params.DeptIDs = append(params.DeptIDs, deptIDs...)
+	return nil
+}
+
 func (a *UserSrv) QueryShow(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserShowQueryResult, error) {
-	result, err := a.UserRepo.Query(ctx, params, opts...)
+	result, err := a.Query(ctx, params, opts...)
 	if err != nil {
 		return nil, err
 	} else if result == nil {
@@ -43,7 +77,8 @@ func (a *UserSrv) QueryShow(ctx context.Context, params schema.UserQueryParam, o
 		return nil, err
 	}
 
-	roleResult, err := a.RoleRepo.Query(ctx, schema.RoleQueryParam{
+	// 角色名称仅用于展示，不受数据范围限制
+	roleResult, err := a.RoleRepo.Query(contextx.NewNoDataScope(ctx), schema.RoleQueryParam{
 		IDs: userRoleResult.Data.ToRoleIDs(),
 	})
 	if err != nil {
@@ -69,18 +104,118 @@ func (a *UserSrv) Get(ctx context.Context, id uint64, opts ...schema.UserQueryOp
 	}
 	item.UserRoles = userRoleResult.Data
 
+	userDeptResult, err := a.UserDeptRepo.Query(ctx, schema.UserDeptQueryParam{
+		UserID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	item.UserDepts = userDeptResult.Data
+
+	lockoutStatus, err := a.LockoutSrv.Status(ctx, item.UserName)
+	if err != nil {
+		return nil, err
+	}
+	item.LoginFailures = lockoutStatus.LoginFailures
+	item.LockedUntil = lockoutStatus.LockedUntil
+
+	item.MFAEnabled, err = a.MFASrv.IsEnabled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	return item, nil
 }
 
+// Unlock 解除用户的登录锁定
+func (a *UserSrv) Unlock(ctx context.Context, id uint64) error {
+	oldItem, err := a.UserRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.LockoutSrv.Reset(ctx, oldItem.UserName)
+}
+
+// ResetMFA 重置用户的两步验证(用户需要重新绑定)
+func (a *UserSrv) ResetMFA(ctx context.Context, id uint64) error {
+	oldItem, err := a.UserRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		return a.MFASrv.Delete(ctx, id)
+	})
+}
+
+// QueryAPIKeys 查询用户的API密钥列表
+func (a *UserSrv) QueryAPIKeys(ctx context.Context, id uint64) (schema.UserAPIKeys, error) {
+	oldItem, err := a.UserRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if oldItem == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	return a.APIKeySrv.Query(ctx, id)
+}
+
+// DeleteAPIKey 吊销用户的API密钥
+func (a *UserSrv) DeleteAPIKey(ctx context.Context, id, keyID uint64) error {
+	oldItem, err := a.UserRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.APIKeySrv.Delete(ctx, id, keyID)
+}
+
 func (a *UserSrv) Create(ctx context.Context, item schema.User) (*schema.IDResult, error) {
+	if item.IsSuperAdmin {
+		err := a.SuperAdminSrv.checkOperator(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err := a.checkUserName(ctx, item)
 	if err != nil {
 		return nil, err
 	}
 
-	item.Password = hash.SHA1String(item.Password)
+	err = a.PasswordSrv.Check(ctx, 0, "", item.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	item.UserDepts = fillUserDepts(item)
+	err = a.DeptSrv.checkDepts(ctx, append(item.UserDepts.ToDeptIDs(), item.DeptID)...)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Password, err = a.PasswordSrv.Hash(item.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	item.PasswordChangedAt = &now
+	item.Source = ""
 	item.ID = snowflake.MustID()
 	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
+		err := a.PasswordSrv.SaveHistory(ctx, item.ID, item.Password)
+		if err != nil {
+			return err
+		}
+
 		for _, urItem := range item.UserRoles {
 			urItem.ID = snowflake.MustID()
 			urItem.UserID = item.ID
@@ -90,6 +225,15 @@ func (a *UserSrv) Create(ctx context.Context, item schema.User) (*schema.IDResul
 			}
 		}
 
+		for _, udItem := range item.UserDepts {
+			udItem.ID = snowflake.MustID()
+			udItem.UserID = item.ID
+			err := a.UserDeptRepo.Create(ctx, *udItem)
+			if err != nil {
+				return err
+			}
+		}
+
 		return a.UserRepo.Create(ctx, item)
 	})
 	if err != nil {
@@ -97,18 +241,32 @@ func (a *UserSrv) Create(ctx context.Context, item schema.User) (*schema.IDResul
 	}
 
 	for _, urItem := range item.UserRoles {
-		a.Enforcer.AddRoleForUser(strconv.FormatUint(urItem.UserID, 10), strconv.FormatUint(urItem.RoleID, 10))
+		a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(urItem.UserID, 10), strconv.FormatUint(urItem.RoleID, 10), tenantDomain(ctx))
+	}
+	if item.IsSuperAdmin && item.Status == 1 {
+		a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(item.ID, 10), schema.SuperAdminRole, tenantDomain(ctx))
 	}
+	a.notifyPolicyChanged()
 
 	return schema.NewIDResult(item.ID), nil
 }
 
-func (a *UserSrv) checkUserName(ctx context.Context, item schema.User) error {
-	if item.UserName == schema.GetRootUser().UserName {
-		return errors.New400Response("user_name has been exists")
+// 附属部门去重并排除主部门
+func fillUserDepts(item schema.User) schema.UserDepts {
+	var list schema.UserDepts
+	m := map[uint64]struct{}{item.DeptID: {}}
+	for _, udItem := range item.UserDepts {
+		if _, ok := m[udItem.DeptID]; ok {
+			continue
+		}
+		m[udItem.DeptID] = struct{}{}
+		list = append(list, udItem)
 	}
+	return list
+}
 
-	result, err := a.UserRepo.Query(ctx, schema.UserQueryParam{
+func (a *UserSrv) checkUserName(ctx context.Context, item schema.User) error {
+	result, err := a.UserRepo.Query(contextx.NewNoDataScope(ctx), schema.UserQueryParam{
 		PaginationParam: schema.PaginationParam{OnlyCount: true},
 		UserName:        item.UserName,
 	})
@@ -133,13 +291,44 @@ func (a *UserSrv) Update(ctx context.Context, id uint64, item schema.User) error
 		}
 	}
 
-	if item.Password != "" {
-		item.Password = hash.SHA1String(item.Password)
-	} else {
-		item.Password = oldItem.Password
+	if oldItem.IsSuperAdmin || item.IsSuperAdmin {
+		err := a.SuperAdminSrv.checkOperator(ctx)
+		if err != nil {
+			return err
+		}
 	}
 
+	if !item.IsSuperAdmin || item.Status != 1 {
+		err := a.SuperAdminSrv.checkRemain(ctx, oldItem)
+		if err != nil {
+			return err
+		}
+	}
+
+	item.UserDepts = fillUserDepts(item)
+	err = a.DeptSrv.checkDepts(ctx, append(item.UserDepts.ToDeptIDs(), item.DeptID)...)
+	if err != nil {
+		return err
+	}
+
+	// 密码与强制修改标记单独更新(Updates不更新零值字段)
+	password := item.Password
+	if password != "" {
+		err := a.PasswordSrv.Check(ctx, id, oldItem.Password, password)
+		if err != nil {
+			return err
+		}
+
+		password, err = a.PasswordSrv.Hash(password)
+		if err != nil {
+			return err
+		}
+	}
+	item.Password = oldItem.Password
+	item.PasswordChangedAt = oldItem.PasswordChangedAt
+
 	item.ID = oldItem.ID
+	item.Source = oldItem.Source
 	item.Creator = oldItem.Creator
 	item.CreatedAt = oldItem.CreatedAt
 
@@ -161,23 +350,80 @@ func (a *UserSrv) Update(ctx context.Context, id uint64, item schema.User) error
 			}
 		}
 
-		return a.UserRepo.Update(ctx, id, item)
+		addUserDepts, delUserDepts := a.compareUserDepts(ctx, oldItem.UserDepts, item.UserDepts)
+		for _, aitem := range addUserDepts {
+			aitem.ID = snowflake.MustID()
+			aitem.UserID = id
+			err := a.UserDeptRepo.Create(ctx, *aitem)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, ditem := range delUserDepts {
+			err := a.UserDeptRepo.Delete(ctx, ditem.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := a.UserRepo.Update(ctx, id, item)
+		if err != nil {
+			return err
+		}
+
+		if item.IsSuperAdmin != oldItem.IsSuperAdmin {
+			err := a.UserRepo.UpdateSuperAdmin(ctx, id, item.IsSuperAdmin)
+			if err != nil {
+				return err
+			}
+		}
+
+		if password != "" {
+			err := a.UserRepo.ChangePassword(ctx, id, password, item.MustChangePassword)
+			if err != nil {
+				return err
+			}
+			return a.PasswordSrv.SaveHistory(ctx, id, password)
+		} else if item.MustChangePassword != oldItem.MustChangePassword {
+			return a.UserRepo.UpdateMustChangePassword(ctx, id, item.MustChangePassword)
+		}
+		return nil
 	})
 	if err != nil {
 		return err
 	}
 
 	for _, aitem := range addUserRoles {
-		a.Enforcer.AddRoleForUser(strconv.FormatUint(id, 10), strconv.FormatUint(aitem.RoleID, 10))
+		a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(id, 10), strconv.FormatUint(aitem.RoleID, 10), tenantDomain(ctx))
 	}
 
 	for _, ritem := range delUserRoles {
-		a.Enforcer.DeleteRoleForUser(strconv.FormatUint(id, 10), strconv.FormatUint(ritem.RoleID, 10))
+		a.Enforcer.DeleteRoleForUserInDomain(strconv.FormatUint(id, 10), strconv.FormatUint(ritem.RoleID, 10), tenantDomain(ctx))
+	}
+
+	if item.IsSuperAdmin && item.Status == 1 {
+		a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(id, 10), schema.SuperAdminRole, tenantDomain(ctx))
+	} else if oldItem.IsSuperAdmin {
+		a.Enforcer.DeleteRoleForUserInDomain(strconv.FormatUint(id, 10), schema.SuperAdminRole, tenantDomain(ctx))
+	}
+	a.notifyPolicyChanged()
+
+	// 修改密码、用户名、要求修改密码或停用用户后，已签发的令牌失效
+	if password != "" || item.UserName != oldItem.UserName ||
+		(item.MustChangePassword && !oldItem.MustChangePassword) ||
+		(item.Status != oldItem.Status && item.Status != 1) {
+		return a.revokeTokens(ctx, oldItem)
 	}
 
 	return nil
 }
 
+func (a *UserSrv) revokeTokens(ctx context.Context, item *schema.User) error {
+	err := a.Auth.RevokeUser(ctx, tokenSubject(item.ID, item.UserName))
+	return errors.WithStack(err)
+}
+
 func (a *UserSrv) compareUserRoles(ctx context.Context, oldUserRoles, newUserRoles schema.UserRoles) (addList, delList schema.UserRoles) {
 	mOldUserRoles := oldUserRoles.ToMap()
 	mNewUserRoles := newUserRoles.ToMap()
@@ -196,12 +442,40 @@ func (a *UserSrv) compareUserRoles(ctx context.Context, oldUserRoles, newUserRol
 	return
 }
 
+func (a *UserSrv) compareUserDepts(ctx context.Context, oldUserDepts, newUserDepts schema.UserDepts) (addList, delList schema.UserDepts) {
+	mOldUserDepts := oldUserDepts.ToMap()
+	mNewUserDepts := newUserDepts.ToMap()
+
+	for k, item := range mNewUserDepts {
+		if _, ok := mOldUserDepts[k]; ok {
+			delete(mOldUserDepts, k)
+			continue
+		}
+		addList = append(addList, item)
+	}
+
+	for _, item := range mOldUserDepts {
+		delList = append(delList, item)
+	}
+	return
+}
+
 func (a *UserSrv) Delete(ctx context.Context, id uint64) error {
 	oldItem, err := a.UserRepo.Get(ctx, id)
 	if err != nil {
 		return err
 	} else if oldItem == nil {
 		return errors.ErrNotFound
+	} else if oldItem.IsSuperAdmin {
+		err := a.SuperAdminSrv.checkOperator(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = a.SuperAdminSrv.checkRemain(ctx, oldItem)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = a.TransRepo.Exec(ctx, func(ctx context.Context) error {
@@ -210,6 +484,36 @@ func (a *UserSrv) Delete(ctx context.Context, id uint64) error {
 			return err
 		}
 
+		err = a.UserDeptRepo.DeleteByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = a.PasswordSrv.DeleteHistory(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = a.MFASrv.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = a.APIKeySrv.DeleteByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = a.UserIdentityRepo.DeleteByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = a.RoleDataUserRepo.DeleteByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+
 		return a.UserRepo.Delete(ctx, id)
 	})
 	if err != nil {
@@ -217,7 +521,13 @@ func (a *UserSrv) Delete(ctx context.Context, id uint64) error {
 	}
 
 	a.Enforcer.DeleteUser(strconv.FormatUint(id, 10))
-	return nil
+	a.notifyPolicyChanged()
+	return a.revokeTokens(ctx, oldItem)
+}
+
+// 通知其他实例重新加载策略(按需加载用户角色时增量加载不会触发通知，策略变更均需显式通知)
+func (a *UserSrv) notifyPolicyChanged() {
+	_ = a.CasbinWatcher.Update()
 }
 
 func (a *UserSrv) UpdateStatus(ctx context.Context, id uint64, status int) error {
@@ -228,6 +538,18 @@ func (a *UserSrv) UpdateStatus(ctx context.Context, id uint64, status int) error
 		return errors.ErrNotFound
 	} else if oldItem.Status == status {
 		return nil
+	} else if oldItem.IsSuperAdmin {
+		err := a.SuperAdminSrv.checkOperator(ctx)
+		if err != nil {
+			return err
+		}
+
+		if status != 1 {
+			err = a.SuperAdminSrv.checkRemain(ctx, oldItem)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	err = a.UserRepo.UpdateStatus(ctx, id, status)
@@ -237,10 +559,16 @@ func (a *UserSrv) UpdateStatus(ctx context.Context, id uint64, status int) error
 
 	if status == 1 {
 		for _, uritem := range oldItem.UserRoles {
-			a.Enforcer.AddRoleForUser(strconv.FormatUint(id, 10), strconv.FormatUint(uritem.RoleID, 10))
+			a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(id, 10), strconv.FormatUint(uritem.RoleID, 10), tenantDomain(ctx))
+		}
+		if oldItem.IsSuperAdmin {
+			a.Enforcer.AddRoleForUserInDomain(strconv.FormatUint(id, 10), schema.SuperAdminRole, tenantDomain(ctx))
 		}
+		a.notifyPolicyChanged()
 	} else {
 		a.Enforcer.DeleteUser(strconv.FormatUint(id, 10))
+		a.notifyPolicyChanged()
+		return a.revokeTokens(ctx, oldItem)
 	}
 
 	return nil
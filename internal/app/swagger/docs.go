@@ -27,7 +27,23 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/menus": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "获取令牌验证公钥集合(JWKS)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/jwtauth.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/depts": {
             "get": {
                 "security": [
                     {
@@ -35,7 +51,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "查询数据",
                 "parameters": [
@@ -67,12 +83,6 @@ var doc = `{
                         "name": "status",
                         "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "是否显示(1:显示 2:隐藏)",
-                        "name": "isShow",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
                         "description": "父级ID",
@@ -94,7 +104,7 @@ var doc = `{
                                         "list": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/schema.Menu"
+                                                "$ref": "#/definitions/schema.Dept"
                                             }
                                         }
                                     }
@@ -129,7 +139,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "创建数据",
                 "parameters": [
@@ -139,7 +149,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.Menu"
+                            "$ref": "#/definitions/schema.Dept"
                         }
                     }
                 ],
@@ -171,7 +181,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/menus.tree": {
+        "/api/v1/depts.tree": {
             "get": {
                 "security": [
                     {
@@ -179,9 +189,9 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
-                "summary": "查询菜单树",
+                "summary": "查询部门树",
                 "parameters": [
                     {
                         "type": "integer",
@@ -210,7 +220,7 @@ var doc = `{
                                         "list": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/schema.MenuTree"
+                                                "$ref": "#/definitions/schema.DeptTree"
                                             }
                                         }
                                     }
@@ -233,7 +243,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/menus/{id}": {
+        "/api/v1/depts/{id}": {
             "get": {
                 "security": [
                     {
@@ -241,7 +251,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "查询指定数据",
                 "parameters": [
@@ -257,7 +267,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.Menu"
+                            "$ref": "#/definitions/schema.Dept"
                         }
                     },
                     "401": {
@@ -281,7 +291,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "更新数据",
                 "parameters": [
@@ -298,7 +308,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.Menu"
+                            "$ref": "#/definitions/schema.Dept"
                         }
                     }
                 ],
@@ -336,7 +346,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "删除数据",
                 "parameters": [
@@ -370,7 +380,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/menus/{id}/disable": {
+        "/api/v1/depts/{id}/disable": {
             "patch": {
                 "security": [
                     {
@@ -378,7 +388,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "禁用数据",
                 "parameters": [
@@ -412,7 +422,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/menus/{id}/enable": {
+        "/api/v1/depts/{id}/enable": {
             "patch": {
                 "security": [
                     {
@@ -420,7 +430,7 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "MenuAPI"
+                    "DeptAPI"
                 ],
                 "summary": "启用数据",
                 "parameters": [
@@ -454,7 +464,64 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/pub/current/menutree": {
+        "/api/v1/depts/{id}/move": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "DeptAPI"
+                ],
+                "summary": "移动部门(包含下级部门)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "移动参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.DeptMoveParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/menus": {
             "get": {
                 "security": [
                     {
@@ -462,9 +529,51 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "MenuAPI"
+                ],
+                "summary": "查询数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "分页索引",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "分页大小",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "查询值",
+                        "name": "queryValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态(1:启用 2:禁用)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "是否显示(1:显示 2:隐藏)",
+                        "name": "isShow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "父级ID",
+                        "name": "parentID",
+                        "in": "query"
+                    }
                 ],
-                "summary": "查询当前用户菜单树",
                 "responses": {
                     "200": {
                         "description": "查询结果",
@@ -479,7 +588,7 @@ var doc = `{
                                         "list": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/schema.MenuTree"
+                                                "$ref": "#/definitions/schema.Menu"
                                             }
                                         }
                                     }
@@ -487,6 +596,12 @@ var doc = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
                     "401": {
                         "description": "{error:{code:9999,message:invalid signature}}",
                         "schema": {
@@ -500,35 +615,39 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/pub/current/password": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "MenuAPI"
                 ],
-                "summary": "更新个人密码",
+                "summary": "创建数据",
                 "parameters": [
                     {
-                        "description": "请求参数",
+                        "description": "创建数据",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.UpdatePasswordParam"
+                            "$ref": "#/definitions/schema.Menu"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{status:OK}",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.StatusResult"
+                            "$ref": "#/definitions/schema.IDResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
                     "401": {
@@ -546,22 +665,25 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/pub/current/user": {
+        "/api/v1/menus.export": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "produces": [
+                    "application/x-yaml"
+                ],
                 "tags": [
-                    "LoginAPI"
+                    "MenuAPI"
                 ],
-                "summary": "获取当前用户信息",
+                "summary": "导出菜单数据文件(yaml，可通过菜单初始化或同步导入)",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "菜单数据文件",
                         "schema": {
-                            "$ref": "#/definitions/schema.UserLoginInfo"
+                            "type": "string"
                         }
                     },
                     "401": {
@@ -570,6 +692,12 @@ var doc = `{
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
                     "500": {
                         "description": "{error:{code:0,message:internal server error}}",
                         "schema": {
@@ -579,28 +707,36 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/pub/login": {
-            "post": {
+        "/api/v1/menus.sort": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "tags": [
-                    "LoginAPI"
+                    "MenuAPI"
                 ],
-                "summary": "用户登录",
+                "summary": "批量调整菜单的父级及排序值(拖拽排序的结果，在同一事务中执行)",
                 "parameters": [
                     {
-                        "description": "请求参数",
+                        "description": "排序项列表",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.LoginParam"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/schema.MenuSortItem"
+                            }
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{status:OK}",
                         "schema": {
-                            "$ref": "#/definitions/schema.LoginTokenInfo"
+                            "$ref": "#/definitions/schema.StatusResult"
                         }
                     },
                     "400": {
@@ -609,6 +745,12 @@ var doc = `{
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
                     "500": {
                         "description": "{error:{code:0,message:internal server error}}",
                         "schema": {
@@ -618,36 +760,55 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/pub/login/captcha": {
+        "/api/v1/menus.tree": {
             "get": {
-                "produces": [
-                    "image/png"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "MenuAPI"
                 ],
-                "summary": "响应图形验证码",
+                "summary": "查询菜单树",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "验证码ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "type": "integer",
+                        "description": "状态(1:启用 2:禁用)",
+                        "name": "status",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "重新加载",
-                        "name": "reload",
+                        "type": "integer",
+                        "description": "父级ID",
+                        "name": "parentID",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "图形验证码"
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.MenuTree"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    "400": {
-                        "description": "{error:{code:0,message:bad request}}",
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
                         "schema": {
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
@@ -661,54 +822,31 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/pub/login/captchaid": {
+        "/api/v1/menus/{id}": {
             "get": {
-                "tags": [
-                    "LoginAPI"
-                ],
-                "summary": "获取验证码信息",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/schema.LoginCaptcha"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/pub/login/exit": {
-            "post": {
-                "tags": [
-                    "LoginAPI"
-                ],
-                "summary": "用户登出",
-                "responses": {
-                    "200": {
-                        "description": "{status:OK}",
-                        "schema": {
-                            "$ref": "#/definitions/schema.StatusResult"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/pub/refresh-token": {
-            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "LoginAPI"
+                    "MenuAPI"
+                ],
+                "summary": "查询指定数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "刷新令牌",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.LoginTokenInfo"
+                            "$ref": "#/definitions/schema.Menu"
                         }
                     },
                     "401": {
@@ -724,69 +862,46 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/roles": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "MenuAPI"
                 ],
-                "summary": "查询数据",
+                "summary": "更新数据",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "分页索引",
-                        "name": "current",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "分页大小",
-                        "name": "pageSize",
-                        "in": "query",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "查询值",
-                        "name": "queryValue",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "状态(1:启用 2:禁用)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "更新数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.Menu"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "查询结果",
+                        "description": "{status:OK}",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/schema.ListResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "list": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/schema.Role"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
                     "401": {
@@ -803,32 +918,50 @@ var doc = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "MenuAPI"
                 ],
-                "summary": "创建数据",
+                "summary": "删除数据(级联删除时同时删除下级菜单、动作、资源及所有租户角色的授权，并返回影响范围)",
                 "parameters": [
                     {
-                        "description": "创建数据",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schema.Role"
-                        }
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "级联删除",
+                        "name": "cascade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "失去访问权限的用户的分页索引(级联删除时有效)",
+                        "name": "current",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "失去访问权限的用户的分页大小(级联删除时有效，最大100)",
+                        "name": "pageSize",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{status:OK}或级联删除的影响范围",
                         "schema": {
-                            "$ref": "#/definitions/schema.IDResult"
+                            "$ref": "#/definitions/schema.MenuDeleteImpact"
                         }
                     },
                     "400": {
@@ -852,51 +985,31 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/roles.select": {
-            "get": {
+        "/api/v1/menus/{id}/disable": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "MenuAPI"
                 ],
-                "summary": "查询选择数据",
+                "summary": "禁用数据",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "查询值",
-                        "name": "queryValue",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "状态(1:启用 2:禁用)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "查询结果",
+                        "description": "{status:OK}",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/schema.ListResult"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "list": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/schema.Role"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/schema.StatusResult"
                         }
                     },
                     "401": {
@@ -914,17 +1027,17 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/roles/{id}": {
-            "get": {
+        "/api/v1/menus/{id}/enable": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "MenuAPI"
                 ],
-                "summary": "查询指定数据",
+                "summary": "启用数据",
                 "parameters": [
                     {
                         "type": "integer",
@@ -936,15 +1049,9 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/schema.Role"
-                        }
-                    },
-                    "400": {
-                        "description": "{error:{code:0,message:bad request}}",
+                        "description": "{status:OK}",
                         "schema": {
-                            "$ref": "#/definitions/schema.ErrorResult"
+                            "$ref": "#/definitions/schema.StatusResult"
                         }
                     },
                     "401": {
@@ -960,17 +1067,19 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/menus/{id}/impact": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "MenuAPI"
                 ],
-                "summary": "更新数据",
+                "summary": "预览级联删除的影响范围(删除的菜单、动作，移除授权的角色及失去访问权限的用户，用户分页返回)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -980,20 +1089,25 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "更新数据",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schema.Role"
-                        }
+                        "type": "integer",
+                        "default": 1,
+                        "description": "失去访问权限的用户的分页索引",
+                        "name": "current",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "失去访问权限的用户的分页大小(最大100)",
+                        "name": "pageSize",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.Role"
+                            "$ref": "#/definitions/schema.MenuDeleteImpact"
                         }
                     },
                     "400": {
@@ -1008,6 +1122,12 @@ var doc = `{
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
                     "500": {
                         "description": "{error:{code:0,message:internal server error}}",
                         "schema": {
@@ -1015,17 +1135,19 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/menus/{id}/move": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "MenuAPI"
                 ],
-                "summary": "删除数据",
+                "summary": "移动菜单(包含下级菜单)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1033,6 +1155,15 @@ var doc = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "移动参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.MenuMoveParam"
+                        }
                     }
                 ],
                 "responses": {
@@ -1042,6 +1173,12 @@ var doc = `{
                             "$ref": "#/definitions/schema.StatusResult"
                         }
                     },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
                     "401": {
                         "description": "{error:{code:9999,message:invalid signature}}",
                         "schema": {
@@ -1057,77 +1194,66 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/roles/{id}/disable": {
-            "patch": {
+        "/api/v1/permissions.explain": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "RoleAPI"
+                    "PermissionAPI"
                 ],
-                "summary": "禁用数据",
+                "summary": "权限诊断(查看用户或角色访问接口的判定过程)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
-                        "in": "path",
+                        "description": "用户ID(与角色ID二选一)",
+                        "name": "userID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "角色ID(与用户ID二选一)",
+                        "name": "roleID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求方式",
+                        "name": "method",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求路径",
+                        "name": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "{status:OK}",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.StatusResult"
+                            "$ref": "#/definitions/schema.PermissionExplain"
                         }
                     },
-                    "401": {
-                        "description": "{error:{code:9999,message:invalid signature}}",
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
                         "schema": {
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
-                    "500": {
-                        "description": "{error:{code:0,message:internal server error}}",
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
                         "schema": {
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/roles/{id}/enable": {
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "tags": [
-                    "RoleAPI"
-                ],
-                "summary": "启用数据",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "{status:OK}",
-                        "schema": {
-                            "$ref": "#/definitions/schema.StatusResult"
-                        }
                     },
-                    "401": {
-                        "description": "{error:{code:9999,message:invalid signature}}",
+                    "404": {
+                        "description": "{error:{code:0,message:not found}}",
                         "schema": {
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
@@ -1141,7 +1267,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/users": {
+        "/api/v1/pub/current/apikeys": {
             "get": {
                 "security": [
                     {
@@ -1149,45 +1275,9 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
-                ],
-                "summary": "查询数据",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "分页索引",
-                        "name": "current",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "分页大小",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "查询值",
-                        "name": "queryValue",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "角色ID(多个以英文逗号分隔)",
-                        "name": "roleIDs",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "状态(1:启用 2:停用)",
-                        "name": "status",
-                        "in": "query"
-                    }
+                    "LoginAPI"
                 ],
+                "summary": "查询当前用户API密钥",
                 "responses": {
                     "200": {
                         "description": "查询结果",
@@ -1202,7 +1292,7 @@ var doc = `{
                                         "list": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/schema.UserShow"
+                                                "$ref": "#/definitions/schema.UserAPIKey"
                                             }
                                         }
                                     }
@@ -1231,17 +1321,17 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "LoginAPI"
                 ],
-                "summary": "创建数据",
+                "summary": "创建当前用户API密钥(密钥仅返回一次，使用方式 Authorization: Bearer \u003ckey\u003e)",
                 "parameters": [
                     {
-                        "description": "创建数据",
+                        "description": "请求参数",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.User"
+                            "$ref": "#/definitions/schema.UserAPIKeyCreateParam"
                         }
                     }
                 ],
@@ -1249,7 +1339,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.IDResult"
+                            "$ref": "#/definitions/schema.UserAPIKeyCreateResult"
                         }
                     },
                     "400": {
@@ -1264,6 +1354,12 @@ var doc = `{
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
+                    "403": {
+                        "description": "{error:{code:0,message:API密钥不能用于创建新的API密钥}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
                     "500": {
                         "description": "{error:{code:0,message:internal server error}}",
                         "schema": {
@@ -1273,31 +1369,31 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/users/{id}": {
-            "get": {
+        "/api/v1/pub/current/apikeys/{kid}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "LoginAPI"
                 ],
-                "summary": "查询指定数据",
+                "summary": "吊销当前用户API密钥",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
+                        "description": "API密钥ID",
+                        "name": "kid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "{status:OK}",
                         "schema": {
-                            "$ref": "#/definitions/schema.User"
+                            "$ref": "#/definitions/schema.StatusResult"
                         }
                     },
                     "401": {
@@ -1313,7 +1409,57 @@ var doc = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/pub/current/menutree": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "查询当前用户菜单树",
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.MenuTree"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/current/mfa": {
             "put": {
                 "security": [
                     {
@@ -1321,24 +1467,17 @@ var doc = `{
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "LoginAPI"
                 ],
-                "summary": "更新数据",
+                "summary": "校验动态口令完成两步验证绑定(返回一次性恢复码)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新数据",
+                        "description": "请求参数",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.User"
+                            "$ref": "#/definitions/schema.MFACodeParam"
                         }
                     }
                 ],
@@ -1346,11 +1485,11 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.User"
+                            "$ref": "#/definitions/schema.MFARecoveryCodes"
                         }
                     },
                     "400": {
-                        "description": "{error:{code:0,message:bad request}}",
+                        "description": "{error:{code:0,message:动态口令不正确}}",
                         "schema": {
                             "$ref": "#/definitions/schema.ErrorResult"
                         }
@@ -1369,30 +1508,27 @@ var doc = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "UserAPI"
-                ],
-                "summary": "删除数据",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "LoginAPI"
                 ],
+                "summary": "获取两步验证密钥(重新获取将替换未完成绑定的密钥)",
                 "responses": {
                     "200": {
-                        "description": "{status:OK}",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.StatusResult"
+                            "$ref": "#/definitions/schema.MFAEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:已启用两步验证}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
                     "401": {
@@ -1410,31 +1546,28 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/users/{id}/disable": {
-            "patch": {
+        "/api/v1/pub/current/mfa/qrcode": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "tags": [
-                    "UserAPI"
+                "produces": [
+                    "image/png"
                 ],
-                "summary": "禁用数据",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "LoginAPI"
                 ],
+                "summary": "响应两步验证密钥二维码",
                 "responses": {
                     "200": {
-                        "description": "{status:OK}",
+                        "description": "二维码"
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:请先获取两步验证密钥}}",
                         "schema": {
-                            "$ref": "#/definitions/schema.StatusResult"
+                            "$ref": "#/definitions/schema.ErrorResult"
                         }
                     },
                     "401": {
@@ -1452,28 +1585,2092 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/users/{id}/enable": {
-            "patch": {
+        "/api/v1/pub/current/password": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "UserAPI"
+                    "LoginAPI"
                 ],
-                "summary": "启用数据",
+                "summary": "更新个人密码(旧密码及新密码均为明文，新密码需符合密码策略)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "唯一标识",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
+                        "description": "请求参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.UpdatePasswordParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:密码不符合安全策略,details:[]}}",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ErrorResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/schema.ErrorItem"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "details": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/schema.PasswordViolation"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/current/permissions": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "批量校验当前用户的权限",
+                "parameters": [
+                    {
+                        "description": "校验项(菜单路由及动作编号，或请求方式及请求路径)",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.PermissionCheckParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "校验结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.PermissionCheckResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/current/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "查询当前用户登录会话",
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.UserSession"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "吊销当前用户全部登录会话",
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/current/sessions/{sid}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "吊销当前用户登录会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/current/user": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "获取当前用户信息",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.UserLoginInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/login": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "用户登录(提交明文密码)",
+                "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginTokenInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "429": {
+                        "description": "{error:{code:0,message:登录失败次数过多,details:{retry_after:30}}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/login/captcha": {
+            "get": {
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "响应图形验证码",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "验证码ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "重新加载",
+                        "name": "reload",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "图形验证码"
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/login/captchaid": {
+            "get": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "获取验证码信息",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginCaptcha"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/login/exit": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "用户登出",
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/login/mfa": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "两步验证登录(使用登录返回的挑战令牌和动态口令或恢复码换取令牌)",
+                "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginMFAParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginTokenInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:动态口令不正确}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "429": {
+                        "description": "{error:{code:0,message:登录失败次数过多,details:{retry_after:30}}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/login/oidc": {
+            "get": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "获取OIDC授权地址(前端跳转到该地址，身份提供方回调时携带code与state)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginOIDCURL"
+                        }
+                    },
+                    "404": {
+                        "description": "{error:{code:0,message:未启用OIDC登录}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "OIDC登录(使用回调中的code与state换取令牌)",
+                "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginOIDCParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginTokenInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:无效的授权码或状态}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:用户未开通，请联系管理员}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "404": {
+                        "description": "{error:{code:0,message:未启用OIDC登录}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pub/refresh-token": {
+            "post": {
+                "tags": [
+                    "LoginAPI"
+                ],
+                "summary": "刷新令牌(刷新令牌每次使用后轮换，重复使用将吊销该登录的全部令牌)",
+                "parameters": [
+                    {
+                        "description": "请求参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.RefreshTokenParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.LoginTokenInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "查询数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "分页索引",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "分页大小",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "查询值",
+                        "name": "queryValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态(1:启用 2:禁用)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Role"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "创建数据",
+                "parameters": [
+                    {
+                        "description": "创建数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.IDResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles.select": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "查询选择数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "查询值",
+                        "name": "queryValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态(1:启用 2:禁用)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Role"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "查询指定数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "更新数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "删除数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles/{id}/disable": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "禁用数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/roles/{id}/enable": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "RoleAPI"
+                ],
+                "summary": "启用数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "查询数据(仅平台用户)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "分页索引",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "分页大小",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "查询值(租户编号或名称)",
+                        "name": "queryValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态(1:启用 2:停用)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.Tenant"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "创建数据(仅平台用户，指定管理员用户名时同时创建租户的超级管理员，管理员密码为明文)",
+                "parameters": [
+                    {
+                        "description": "创建数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.Tenant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.IDResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "查询指定数据(仅平台用户)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Tenant"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "404": {
+                        "description": "{error:{code:0,message:not found}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "更新数据(仅平台用户)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.Tenant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "删除数据(仅平台用户，租户下存在用户或角色时不允许删除)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants/{id}/disable": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "停用数据(仅平台用户，停用后该租户的请求均被拒绝)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tenants/{id}/enable": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "TenantAPI"
+                ],
+                "summary": "启用数据(仅平台用户)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "403": {
+                        "description": "{error:{code:0,message:仅平台用户可以执行该操作}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "查询数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "分页索引",
+                        "name": "current",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "分页大小",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "查询值",
+                        "name": "queryValue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "角色ID(多个以英文逗号分隔)",
+                        "name": "roleIDs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "部门ID(包含下级部门)",
+                        "name": "deptID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "状态(1:启用 2:停用)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.UserShow"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "创建数据(密码为明文，需符合密码策略)",
+                "parameters": [
+                    {
+                        "description": "创建数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.IDResult"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "查询指定数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.User"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "更新数据(密码为明文，为空时不修改，需符合密码策略)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.User"
+                        }
+                    },
+                    "400": {
+                        "description": "{error:{code:0,message:bad request}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "删除数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/apikeys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "查询用户API密钥",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.UserAPIKey"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/apikeys/{kid}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "吊销用户API密钥",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "API密钥ID",
+                        "name": "kid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/disable": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "禁用数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/enable": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "启用数据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/mfa": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "重置两步验证",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "查询用户登录会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/schema.ListResult"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "list": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/schema.UserSession"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "吊销用户全部登录会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/sessions/{sid}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "吊销用户登录会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{status:OK}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.StatusResult"
+                        }
+                    },
+                    "401": {
+                        "description": "{error:{code:9999,message:invalid signature}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    },
+                    "500": {
+                        "description": "{error:{code:0,message:internal server error}}",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/unlock": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "UserAPI"
+                ],
+                "summary": "解除登录锁定",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "唯一标识",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
                         "description": "{status:OK}",
                         "schema": {
                             "$ref": "#/definitions/schema.StatusResult"
@@ -1496,12 +3693,186 @@ var doc = `{
         }
     },
     "definitions": {
+        "jwtauth.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "算法",
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "曲线",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "RSA指数",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "密钥ID",
+                    "type": "string"
+                },
+                "kty": {
+                    "description": "密钥类型",
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA模数",
+                    "type": "string"
+                },
+                "use": {
+                    "description": "用途",
+                    "type": "string"
+                },
+                "x": {
+                    "description": "曲线坐标x",
+                    "type": "string"
+                },
+                "y": {
+                    "description": "曲线坐标y",
+                    "type": "string"
+                }
+            }
+        },
+        "jwtauth.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jwtauth.JWK"
+                    }
+                }
+            }
+        },
+        "schema.Dept": {
+            "type": "object",
+            "required": [
+                "name",
+                "status"
+            ],
+            "properties": {
+                "created_at": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "创建者",
+                    "type": "integer"
+                },
+                "email": {
+                    "description": "邮箱",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "leader": {
+                    "description": "负责人",
+                    "type": "string"
+                },
+                "memo": {
+                    "description": "备注",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "部门名称",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "父级ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "parent_path": {
+                    "description": "父级路径",
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "联系电话",
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "排序值",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "状态(1:启用 2:禁用)",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.DeptMoveParam": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "新的父级ID(为0时移动到顶级)",
+                    "type": "string",
+                    "example": "0"
+                },
+                "sequence": {
+                    "description": "排序值(为空时不修改)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.DeptTree": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "子级树",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.DeptTree"
+                    }
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "leader": {
+                    "description": "负责人",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "部门名称",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "父级ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "parent_path": {
+                    "description": "父级路径",
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "排序值",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "状态(1:启用 2:禁用)",
+                    "type": "integer"
+                }
+            }
+        },
         "schema.ErrorItem": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
+                "details": {
+                    "type": "object"
+                },
                 "message": {
                     "type": "string"
                 }
@@ -1535,11 +3906,71 @@ var doc = `{
                 }
             }
         },
-        "schema.LoginCaptcha": {
+        "schema.LoginCaptcha": {
+            "type": "object",
+            "properties": {
+                "captcha_id": {
+                    "description": "验证码ID",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.LoginMFAChallenge": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "description": "挑战令牌",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "过期时间戳",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.LoginMFAParam": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "description": "挑战令牌",
+                    "type": "string"
+                },
+                "code": {
+                    "description": "动态口令或恢复码",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.LoginOIDCParam": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "description": "授权码",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "状态",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.LoginOIDCURL": {
             "type": "object",
             "properties": {
-                "captcha_id": {
-                    "description": "验证码ID",
+                "state": {
+                    "description": "状态(回调时原样返回)",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "跳转到身份提供方的授权地址",
                     "type": "string"
                 }
             }
@@ -1562,7 +3993,7 @@ var doc = `{
                     "type": "string"
                 },
                 "password": {
-                    "description": "密码(md5加密)",
+                    "description": "密码(明文)",
                     "type": "string"
                 },
                 "user_name": {
@@ -1582,12 +4013,65 @@ var doc = `{
                     "description": "过期时间戳",
                     "type": "integer"
                 },
+                "mfa_challenge": {
+                    "description": "两步验证挑战(存在时不签发令牌，需继续验证动态口令)",
+                    "$ref": "#/definitions/schema.LoginMFAChallenge"
+                },
+                "must_change_password": {
+                    "description": "必须修改密码(令牌仅允许修改密码)",
+                    "type": "boolean"
+                },
+                "refresh_expires_at": {
+                    "description": "刷新令牌过期时间戳",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "刷新令牌",
+                    "type": "string"
+                },
                 "token_type": {
                     "description": "令牌类型",
                     "type": "string"
                 }
             }
         },
+        "schema.MFACodeParam": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "动态口令",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.MFAEnrollment": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "TOTP密钥(Base32)",
+                    "type": "string"
+                },
+                "uri": {
+                    "description": "otpauth URI",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.MFARecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "恢复码列表",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "schema.Menu": {
             "type": "object",
             "required": [
@@ -1603,6 +4087,10 @@ var doc = `{
                         "$ref": "#/definitions/schema.MenuAction"
                     }
                 },
+                "code": {
+                    "description": "菜单编号(唯一，用于同步菜单数据)",
+                    "type": "string"
+                },
                 "created_at": {
                     "description": "创建时间",
                     "type": "string"
@@ -1721,6 +4209,76 @@ var doc = `{
                 }
             }
         },
+        "schema.MenuDeleteImpact": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "删除的动作",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.MenuAction"
+                    }
+                },
+                "menus": {
+                    "description": "删除的菜单(包含下级菜单)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.Menu"
+                    }
+                },
+                "roles": {
+                    "description": "移除授权的角色(包含所有租户)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.Role"
+                    }
+                },
+                "user_pagination": {
+                    "description": "用户的分页信息(total为失去访问权限的用户总数)",
+                    "$ref": "#/definitions/schema.PaginationResult"
+                },
+                "users": {
+                    "description": "失去访问权限的用户(直接或通过继承拥有以上角色，分页返回)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.User"
+                    }
+                }
+            }
+        },
+        "schema.MenuMoveParam": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "新的父级ID(为0时移动到顶级)",
+                    "type": "string",
+                    "example": "0"
+                },
+                "sequence": {
+                    "description": "排序值(为空时不修改)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.MenuSortItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "parent_id": {
+                    "description": "父级ID(为0时为顶级)",
+                    "type": "string",
+                    "example": "0"
+                },
+                "sequence": {
+                    "description": "排序值",
+                    "type": "integer"
+                }
+            }
+        },
         "schema.MenuTree": {
             "type": "object",
             "properties": {
@@ -1738,6 +4296,10 @@ var doc = `{
                         "$ref": "#/definitions/schema.MenuTree"
                     }
                 },
+                "code": {
+                    "description": "菜单编号",
+                    "type": "string"
+                },
                 "icon": {
                     "description": "菜单图标",
                     "type": "string"
@@ -1768,27 +4330,243 @@ var doc = `{
                     "description": "访问路由",
                     "type": "string"
                 },
-                "sequence": {
-                    "description": "排序值",
-                    "type": "integer"
+                "sequence": {
+                    "description": "排序值",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "状态(1:启用 2:禁用)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.PaginationResult": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.PasswordViolation": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "说明",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "规则(min_length/upper/lower/digit/symbol/history)",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.PermissionCheckItem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "动作编号",
+                    "type": "string"
+                },
+                "method": {
+                    "description": "请求方式",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "请求路径",
+                    "type": "string"
+                },
+                "router": {
+                    "description": "菜单路由",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.PermissionCheckParam": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "description": "校验项列表(最多100项)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.PermissionCheckItem"
+                    }
+                }
+            }
+        },
+        "schema.PermissionCheckResult": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "是否允许访问",
+                    "type": "boolean"
+                },
+                "code": {
+                    "description": "动作编号",
+                    "type": "string"
+                },
+                "method": {
+                    "description": "请求方式",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "请求路径",
+                    "type": "string"
+                },
+                "router": {
+                    "description": "菜单路由",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.PermissionExplain": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "是否允许访问",
+                    "type": "boolean"
+                },
+                "matched_policy": {
+                    "description": "命中的策略(p,sub,dom,obj,act)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "policies": {
+                    "description": "命中或部分命中(仅路径或请求方式匹配)的策略",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.PermissionExplainPolicy"
+                    }
+                },
+                "reason": {
+                    "description": "判定说明",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "参与校验的角色(包含继承的上级角色)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.PermissionExplainRole"
+                    }
+                },
+                "subject": {
+                    "description": "校验的主体(用户ID或角色ID)",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.PermissionExplainPolicy": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "description": "策略请求方式",
+                    "type": "string"
+                },
+                "method_matched": {
+                    "description": "请求方式是否匹配(regexMatch)",
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "策略路径",
+                    "type": "string"
+                },
+                "path_matched": {
+                    "description": "路径是否匹配(keyMatch2)",
+                    "type": "boolean"
+                },
+                "role_id": {
+                    "description": "角色ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "sources": {
+                    "description": "产生该策略的菜单动作及资源",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.PermissionExplainSource"
+                    }
+                }
+            }
+        },
+        "schema.PermissionExplainRole": {
+            "type": "object",
+            "properties": {
+                "effective": {
+                    "description": "是否已生效(禁用的角色及仅通过禁用角色继承的上级角色不生效)",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "角色ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "inherited_from": {
+                    "description": "继承自的下级角色ID(为0时表示直接分配的角色)",
+                    "type": "string",
+                    "example": "0"
+                },
+                "name": {
+                    "description": "角色名称",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态(1:启用 2:禁用)",
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.PermissionExplainSource": {
+            "type": "object",
+            "properties": {
+                "action_code": {
+                    "description": "动作编号",
+                    "type": "string"
+                },
+                "action_id": {
+                    "description": "动作ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "action_name": {
+                    "description": "动作名称",
+                    "type": "string"
+                },
+                "menu_id": {
+                    "description": "菜单ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "menu_name": {
+                    "description": "菜单名称",
+                    "type": "string"
                 },
-                "status": {
-                    "description": "状态(1:启用 2:禁用)",
-                    "type": "integer"
+                "resource_id": {
+                    "description": "资源ID",
+                    "type": "string",
+                    "example": "0"
                 }
             }
         },
-        "schema.PaginationResult": {
+        "schema.RefreshTokenParam": {
             "type": "object",
+            "required": [
+                "refresh_token"
+            ],
             "properties": {
-                "current": {
-                    "type": "integer"
-                },
-                "pageSize": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
+                "refresh_token": {
+                    "description": "刷新令牌",
+                    "type": "string"
                 }
             }
         },
@@ -1808,6 +4586,10 @@ var doc = `{
                     "description": "创建者",
                     "type": "integer"
                 },
+                "data_scope": {
+                    "description": "数据范围(1:全部 2:仅本人 3:自定义 4:本部门及以下)，默认全部",
+                    "type": "integer"
+                },
                 "id": {
                     "description": "唯一标识",
                     "type": "string",
@@ -1821,6 +4603,13 @@ var doc = `{
                     "description": "角色名称",
                     "type": "string"
                 },
+                "role_data_users": {
+                    "description": "自定义数据范围的用户列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleDataUser"
+                    }
+                },
                 "role_menus": {
                     "description": "角色菜单列表",
                     "type": "array",
@@ -1828,6 +4617,13 @@ var doc = `{
                         "$ref": "#/definitions/schema.RoleMenu"
                     }
                 },
+                "role_parents": {
+                    "description": "继承的上级角色列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.RoleParent"
+                    }
+                },
                 "sequence": {
                     "description": "排序值",
                     "type": "integer"
@@ -1842,6 +4638,29 @@ var doc = `{
                 }
             }
         },
+        "schema.RoleDataUser": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "role_id": {
+                    "description": "角色ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "user_id": {
+                    "description": "用户ID",
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
         "schema.RoleMenu": {
             "type": "object",
             "required": [
@@ -1872,6 +4691,29 @@ var doc = `{
                 }
             }
         },
+        "schema.RoleParent": {
+            "type": "object",
+            "required": [
+                "parent_id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "parent_id": {
+                    "description": "上级角色ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "role_id": {
+                    "description": "角色ID",
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
         "schema.StatusResult": {
             "type": "object",
             "properties": {
@@ -1880,6 +4722,57 @@ var doc = `{
                 }
             }
         },
+        "schema.Tenant": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "status"
+            ],
+            "properties": {
+                "admin_password": {
+                    "description": "租户超级管理员密码(明文，仅创建时有效)",
+                    "type": "string"
+                },
+                "admin_user_name": {
+                    "description": "租户超级管理员用户名(仅创建时有效，为空时不创建)",
+                    "type": "string"
+                },
+                "code": {
+                    "description": "租户编号(用于子域名及请求头)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "creator": {
+                    "description": "创建者",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "memo": {
+                    "description": "备注",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "租户名称",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态(1:启用 2:停用)",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
+                }
+            }
+        },
         "schema.UpdatePasswordParam": {
             "type": "object",
             "required": [
@@ -1888,11 +4781,11 @@ var doc = `{
             ],
             "properties": {
                 "new_password": {
-                    "description": "新密码(md5加密)",
+                    "description": "新密码(明文，需符合密码策略)",
                     "type": "string"
                 },
                 "old_password": {
-                    "description": "旧密码(md5加密)",
+                    "description": "旧密码(明文)",
                     "type": "string"
                 }
             }
@@ -1914,6 +4807,11 @@ var doc = `{
                     "description": "创建者",
                     "type": "integer"
                 },
+                "dept_id": {
+                    "description": "主部门ID",
+                    "type": "string",
+                    "example": "0"
+                },
                 "email": {
                     "description": "邮箱",
                     "type": "string"
@@ -1923,8 +4821,32 @@ var doc = `{
                     "type": "string",
                     "example": "0"
                 },
+                "is_super_admin": {
+                    "description": "是否超级管理员(不受权限及数据范围限制)",
+                    "type": "boolean"
+                },
+                "locked_until": {
+                    "description": "登录锁定截止时间",
+                    "type": "string"
+                },
+                "login_failures": {
+                    "description": "登录失败次数",
+                    "type": "integer"
+                },
+                "mfa_enabled": {
+                    "description": "是否启用两步验证",
+                    "type": "boolean"
+                },
+                "must_change_password": {
+                    "description": "下次登录必须修改密码",
+                    "type": "boolean"
+                },
                 "password": {
-                    "description": "密码",
+                    "description": "密码(明文，仅创建及修改时有效)",
+                    "type": "string"
+                },
+                "password_changed_at": {
+                    "description": "密码修改时间",
                     "type": "string"
                 },
                 "phone": {
@@ -1935,10 +4857,21 @@ var doc = `{
                     "description": "真实姓名",
                     "type": "string"
                 },
+                "source": {
+                    "description": "用户来源(为空时为本地用户，外部身份自动创建时为oidc/ldap)",
+                    "type": "string"
+                },
                 "status": {
                     "description": "用户状态(1:启用 2:停用)",
                     "type": "integer"
                 },
+                "user_depts": {
+                    "description": "附属部门",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.UserDept"
+                    }
+                },
                 "user_name": {
                     "description": "用户名",
                     "type": "string"
@@ -1952,9 +4885,135 @@ var doc = `{
                 }
             }
         },
+        "schema.UserAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "过期时间(为空表示不过期)",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "key_roles": {
+                    "description": "限定的角色(为空表示与所属用户的角色一致)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.UserAPIKeyRole"
+                    }
+                },
+                "last_used_at": {
+                    "description": "最近使用时间",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "密钥前缀(用于识别)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "所属用户ID",
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "schema.UserAPIKeyCreateParam": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "过期时间(为空表示不过期)",
+                    "type": "string"
+                },
+                "key_roles": {
+                    "description": "限定的角色(必须是用户已授权的角色)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.UserAPIKeyRole"
+                    }
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.UserAPIKeyCreateResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "key": {
+                    "description": "密钥",
+                    "type": "string"
+                }
+            }
+        },
+        "schema.UserAPIKeyRole": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "description": "API密钥ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "role_id": {
+                    "description": "角色ID",
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "schema.UserDept": {
+            "type": "object",
+            "required": [
+                "dept_id"
+            ],
+            "properties": {
+                "dept_id": {
+                    "description": "部门ID",
+                    "type": "string",
+                    "example": "0"
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "example": "0"
+                },
+                "user_id": {
+                    "description": "用户ID",
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
         "schema.UserLoginInfo": {
             "type": "object",
             "properties": {
+                "is_super_admin": {
+                    "description": "是否超级管理员",
+                    "type": "boolean"
+                },
                 "real_name": {
                     "description": "真实姓名",
                     "type": "string"
@@ -1997,6 +5056,35 @@ var doc = `{
                 }
             }
         },
+        "schema.UserSession": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "登录时间",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "过期时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "会话ID",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "客户端IP",
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "description": "最近活动时间",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "客户端标识",
+                    "type": "string"
+                }
+            }
+        },
         "schema.UserShow": {
             "type": "object",
             "properties": {
@@ -2004,6 +5092,11 @@ var doc = `{
                     "description": "创建时间",
                     "type": "string"
                 },
+                "dept_id": {
+                    "description": "主部门ID",
+                    "type": "string",
+                    "example": "0"
+                },
                 "email": {
                     "description": "邮箱",
                     "type": "string"
@@ -2013,6 +5106,14 @@ var doc = `{
                     "type": "string",
                     "example": "0"
                 },
+                "is_super_admin": {
+                    "description": "是否超级管理员",
+                    "type": "boolean"
+                },
+                "must_change_password": {
+                    "description": "下次登录必须修改密码",
+                    "type": "boolean"
+                },
                 "phone": {
                     "description": "手机号",
                     "type": "string"
@@ -2028,6 +5129,10 @@ var doc = `{
                         "$ref": "#/definitions/schema.Role"
                     }
                 },
+                "source": {
+                    "description": "用户来源",
+                    "type": "string"
+                },
                 "status": {
                     "description": "用户状态(1:启用 2:停用)",
                     "type": "integer"
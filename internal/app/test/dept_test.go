@@ -0,0 +1,139 @@
+package test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDept(t *testing.T) {
+	const router = apiPrefix + "v1/depts"
+	var err error
+
+	w := httptest.NewRecorder()
+
+	createDept := func(parentID uint64) uint64 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPostRequest(router, &schema.Dept{
+			Name:     uuid.MustUUID().String(),
+			ParentID: parentID,
+			Status:   1,
+		}))
+		assert.Equal(t, 200, w.Code)
+		var res ResID
+		assert.Nil(t, parseReader(w.Body, &res))
+		return res.ID
+	}
+
+	getDept := func(id uint64) *schema.Dept {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, id))
+		assert.Equal(t, 200, w.Code)
+		var item schema.Dept
+		assert.Nil(t, parseReader(w.Body, &item))
+		return &item
+	}
+
+	// post /depts: a -> b -> c
+	aID := createDept(0)
+	bID := createDept(aID)
+	cID := createDept(bID)
+	assert.Equal(t, fmt.Sprintf("%d/%d", aID, bID), getDept(cID).ParentPath)
+
+	// get /depts.tree
+	engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/depts.tree", nil))
+	assert.Equal(t, 200, w.Code)
+	var trees []*schema.DeptTree
+	err = parseReader(w.Body, &schema.ListResult{List: &trees})
+	assert.Nil(t, err)
+	for _, item := range trees {
+		if item.ID == aID {
+			if assert.NotNil(t, item.Children) && assert.Equal(t, 1, len(*item.Children)) {
+				assert.Equal(t, bID, (*item.Children)[0].ID)
+			}
+		}
+	}
+
+	// put /depts/:id/move 不允许移动到下级部门
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d/move", schema.DeptMoveParam{ParentID: cID}, router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	// put /depts/:id/move 下级部门的父级路径同步更新
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d/move", schema.DeptMoveParam{ParentID: 0}, router, bID))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "", getDept(bID).ParentPath)
+	assert.Equal(t, fmt.Sprintf("%d", bID), getDept(cID).ParentPath)
+
+	// put /depts/:id
+	putItem := getDept(bID)
+	putItem.ParentID = aID
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d", putItem, router, bID))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, fmt.Sprintf("%d/%d", aID, bID), getDept(cID).ParentPath)
+
+	// post /users: 主部门为c，附属部门为a
+	addUserItem := &schema.User{
+		UserName: uuid.MustUUID().String(),
+		RealName: uuid.MustUUID().String(),
+		Status:   1,
+		Password: "test",
+		UserRoles: schema.UserRoles{
+			&schema.UserRole{RoleID: 1},
+		},
+		DeptID: cID,
+		UserDepts: schema.UserDepts{
+			&schema.UserDept{DeptID: aID},
+		},
+	}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/users", addUserItem))
+	assert.Equal(t, 200, w.Code)
+	var addUserItemRes ResID
+	err = parseReader(w.Body, &addUserItemRes)
+	assert.Nil(t, err)
+
+	// get /users?deptID= 包含下级部门的用户
+	for _, id := range []uint64{aID, bID, cID} {
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/users", newPageParam(map[string]string{
+			"deptID": fmt.Sprintf("%d", id),
+		})))
+		assert.Equal(t, 200, w.Code)
+		var users []*schema.UserShow
+		err = parsePageReader(w.Body, &users)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(users)) {
+			assert.Equal(t, addUserItemRes.ID, users[0].ID)
+			assert.Equal(t, cID, users[0].DeptID)
+		}
+	}
+
+	// delete /depts/:id 存在下级部门或用户时不允许删除
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, bID))
+	assert.Equal(t, 400, w.Code)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, cID))
+	assert.Equal(t, 400, w.Code)
+
+	// delete /users/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/users/%d", addUserItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+
+	// delete /depts/:id
+	for _, id := range []uint64{cID, bID, aID} {
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, id))
+		assert.Equal(t, 200, w.Code)
+		err = parseOK(w.Body)
+		assert.Nil(t, err)
+	}
+}
@@ -2,15 +2,23 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"time"
 
+	"github.com/LyricTian/captcha"
+	"github.com/LyricTian/captcha/store"
 	"github.com/LyricTian/gin-admin/v8/internal/app"
 	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/ldapauth/ldaptest"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc/oidctest"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,7 +28,12 @@ const (
 	apiPrefix  = "/api/"
 )
 
-var engine *gin.Engine
+var (
+	engine       *gin.Engine
+	idp          *oidctest.Server
+	ldapServer   *ldaptest.Server
+	captchaStore store.Store
+)
 
 func init() {
 	config.MustLoad(configFile)
@@ -34,11 +47,47 @@ func init() {
 	config.C.Gorm.DBType = "sqlite3"
 	config.C.Log.EnableHook = false
 
+	idp = oidctest.NewServer("gin-admin", "secret")
+	config.C.OIDC = config.OIDC{
+		Enable:       true,
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "http://127.0.0.1/oidc/callback",
+		StateStore:   "memory",
+	}
+
+	ldapServer = ldaptest.NewServer()
+	ldapServer.AddEntry(ldaptest.Entry{
+		DN:       "cn=admin,dc=example,dc=com",
+		Password: "admin",
+	})
+	config.C.Login.Authenticators = []string{"local", "ldap"}
+	config.C.LDAP = config.LDAP{
+		URL:          ldapServer.URL(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		BaseDN:       "dc=example,dc=com",
+		UserFilter:   "(uid=%s)",
+		GroupAttr:    "memberOf",
+		AutoCreate:   true,
+	}
+
+	// 测试时从存储中读取验证码
+	captchaStore = store.NewMemoryStore(time.Minute, captcha.Expiration)
+	captcha.SetCustomStore(captchaStore)
+
 	app.InitLogger()
 	injector, _, err := app.BuildInjector()
 	if err != nil {
 		panic(err)
 	}
+
+	// 未开启认证时以配置的超级管理员作为当前用户
+	err = injector.SuperAdminSrv.InitData(context.Background())
+	if err != nil {
+		panic(err)
+	}
 	engine = injector.Engine
 }
 
@@ -102,6 +151,28 @@ func parsePageReader(r io.Reader, v interface{}) error {
 	return parseReader(r, result)
 }
 
+// 获取验证码并构造登录参数
+func newLoginParam(userName, password string) *schema.LoginParam {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/pub/login/captchaid", nil))
+
+	var item schema.LoginCaptcha
+	_ = parseReader(w.Body, &item)
+
+	digits := captchaStore.Get(item.CaptchaID, false)
+	code := make([]byte, len(digits))
+	for i, d := range digits {
+		code[i] = '0' + d
+	}
+
+	return &schema.LoginParam{
+		UserName:    userName,
+		Password:    password,
+		CaptchaID:   item.CaptchaID,
+		CaptchaCode: string(code),
+	}
+}
+
 func newPostRequest(formatRouter string, v interface{}, args ...interface{}) *http.Request {
 	req, _ := http.NewRequest("POST", fmt.Sprintf(formatRouter, args...), toReader(v))
 	return req
@@ -112,6 +183,11 @@ func newPutRequest(formatRouter string, v interface{}, args ...interface{}) *htt
 	return req
 }
 
+func newPatchRequest(formatRouter string, args ...interface{}) *http.Request {
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf(formatRouter, args...), nil)
+	return req
+}
+
 func newDeleteRequest(formatRouter string, args ...interface{}) *http.Request {
 	req, _ := http.NewRequest("DELETE", fmt.Sprintf(formatRouter, args...), nil)
 	return req
@@ -0,0 +1,149 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/ldapauth/ldaptest"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLDAP(t *testing.T) {
+	const router = apiPrefix + "v1/users"
+	var err error
+
+	lockoutConfig := config.C.LoginLockout
+	ldapConfig := config.C.LDAP
+	defer func() {
+		config.C.LoginLockout = lockoutConfig
+		config.C.LDAP = ldapConfig
+	}()
+	config.C.LoginLockout.Enable = false
+
+	w := httptest.NewRecorder()
+
+	// post /menus
+	addMenuItem := &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		IsShow: 1,
+		Status: 1,
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", addMenuItem))
+	assert.Equal(t, 200, w.Code)
+	var addMenuItemRes ResID
+	err = parseReader(w.Body, &addMenuItemRes)
+	assert.Nil(t, err)
+
+	// post /roles
+	addRoleItem := &schema.Role{
+		Name:   uuid.MustUUID().String(),
+		Status: 1,
+		RoleMenus: schema.RoleMenus{
+			&schema.RoleMenu{
+				MenuID: addMenuItemRes.ID,
+			},
+		},
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/roles", addRoleItem))
+	assert.Equal(t, 200, w.Code)
+	var addRoleItemRes ResID
+	err = parseReader(w.Body, &addRoleItemRes)
+	assert.Nil(t, err)
+
+	config.C.LDAP.GroupRoles = map[string][]string{
+		"admins": {addRoleItem.Name},
+	}
+
+	userName := uuid.MustUUID().String()
+	ldapServer.AddEntry(ldaptest.Entry{
+		DN:       "uid=" + userName + ",ou=people,dc=example,dc=com",
+		Password: "secret",
+		Attributes: map[string][]string{
+			"uid":      {userName},
+			"cn":       {"LDAP User"},
+			"mail":     {userName + "@example.com"},
+			"memberOf": {"cn=admins,ou=groups,dc=example,dc=com"},
+		},
+	})
+
+	// post /pub/login
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(userName, "secret")))
+	assert.Equal(t, 200, w.Code)
+	var tokenInfo schema.LoginTokenInfo
+	err = parseReader(w.Body, &tokenInfo)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokenInfo.AccessToken)
+
+	// get /users?userName=
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(router, newPageParam(map[string]string{"userName": userName})))
+	assert.Equal(t, 200, w.Code)
+	var pageItems []*schema.UserShow
+	err = parsePageReader(w.Body, &pageItems)
+	assert.Nil(t, err)
+	if !assert.Equal(t, 1, len(pageItems)) {
+		return
+	}
+	assert.Equal(t, "LDAP User", pageItems[0].RealName)
+	assert.Equal(t, "ldap", pageItems[0].Source)
+	assert.Equal(t, []string{addRoleItem.Name}, schema.Roles(pageItems[0].Roles).ToNames())
+	userID := pageItems[0].ID
+
+	// 密码错误
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(userName, "wrong")))
+	assert.Equal(t, 400, w.Code)
+
+	// 用户不存在
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(uuid.MustUUID().String(), "secret")))
+	assert.Equal(t, 400, w.Code)
+
+	// 本地用户优先使用本地认证
+	addUserItem := &schema.User{
+		UserName: uuid.MustUUID().String(),
+		RealName: "Local User",
+		Password: "test",
+		Status:   1,
+		UserRoles: schema.UserRoles{
+			&schema.UserRole{RoleID: addRoleItemRes.ID},
+		},
+	}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, addUserItem))
+	assert.Equal(t, 200, w.Code)
+	var addUserItemRes ResID
+	err = parseReader(w.Body, &addUserItemRes)
+	assert.Nil(t, err)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(addUserItem.UserName, "test")))
+	assert.Equal(t, 200, w.Code)
+
+	// delete /users/:id
+	for _, id := range []uint64{userID, addUserItemRes.ID} {
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, id))
+		assert.Equal(t, 200, w.Code)
+		err = parseOK(w.Body)
+		assert.Nil(t, err)
+	}
+
+	// delete /roles/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/roles/%d", addRoleItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// delete /menus/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", addMenuItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+}
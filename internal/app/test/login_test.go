@@ -0,0 +1,187 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/totp"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+// 为当前用户(超级管理员)绑定两步验证，测试结束后重置两步验证并解除锁定
+func enrollTestMFA(t *testing.T) (string, *schema.MFARecoveryCodes) {
+	// 关闭失败后的逐次等待，仅保留失败计数与锁定
+	baseDelay := config.C.LoginLockout.BaseDelay
+	config.C.LoginLockout.BaseDelay = 0
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/pub/current/user", nil))
+	assert.Equal(t, 200, w.Code)
+	var info schema.UserLoginInfo
+	assert.Nil(t, parseReader(w.Body, &info))
+
+	t.Cleanup(func() {
+		config.C.LoginLockout.BaseDelay = baseDelay
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/users/%d/mfa", info.UserID))
+		assert.Equal(t, 200, w.Code)
+
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, newPatchRequest(apiPrefix+"v1/users/%d/unlock", info.UserID))
+		assert.Equal(t, 200, w.Code)
+	})
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/current/mfa", nil))
+	assert.Equal(t, 200, w.Code)
+	var enrollment schema.MFAEnrollment
+	assert.Nil(t, parseReader(w.Body, &enrollment))
+	assert.NotEmpty(t, enrollment.Secret)
+
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	assert.Nil(t, err)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest(apiPrefix+"v1/pub/current/mfa", &schema.MFACodeParam{Code: code}))
+	assert.Equal(t, 200, w.Code)
+	var codes schema.MFARecoveryCodes
+	assert.Nil(t, parseReader(w.Body, &codes))
+	assert.Len(t, codes.Codes, config.C.MFA.RecoveryCodes)
+
+	return enrollment.Secret, &codes
+}
+
+// 密码登录并返回两步验证挑战
+func loginMFAChallenge(t *testing.T) *schema.LoginMFAChallenge {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(config.C.SuperAdmin.UserName, config.C.SuperAdmin.Password)))
+	if !assert.Equal(t, 200, w.Code) {
+		return &schema.LoginMFAChallenge{}
+	}
+
+	var tokenInfo schema.LoginTokenInfo
+	assert.Nil(t, parseReader(w.Body, &tokenInfo))
+	assert.Empty(t, tokenInfo.AccessToken)
+	if !assert.NotNil(t, tokenInfo.MFAChallenge) {
+		return &schema.LoginMFAChallenge{}
+	}
+	return tokenInfo.MFAChallenge
+}
+
+func TestLoginMFALockout(t *testing.T) {
+	enrollTestMFA(t)
+
+	// 密码正确但动态口令错误，密码登录不会清除失败计数
+	for i := 0; i < config.C.LoginLockout.MaxFailures; i++ {
+		challenge := loginMFAChallenge(t)
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login/mfa", &schema.LoginMFAParam{
+			ChallengeToken: challenge.ChallengeToken,
+			Code:           "invalid-code",
+		}))
+		assert.Equal(t, 400, w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(config.C.SuperAdmin.UserName, config.C.SuperAdmin.Password)))
+	assert.Equal(t, 429, w.Code)
+}
+
+func TestLoginMFA(t *testing.T) {
+	secret, codes := enrollTestMFA(t)
+
+	loginMFA := func(challenge *schema.LoginMFAChallenge, code string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login/mfa", &schema.LoginMFAParam{
+			ChallengeToken: challenge.ChallengeToken,
+			Code:           code,
+		}))
+		return w
+	}
+
+	// 绑定时已使用当前时间步，登录使用下一时间步的动态口令
+	code, err := totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	assert.Nil(t, err)
+
+	challenge := loginMFAChallenge(t)
+	w := loginMFA(challenge, code)
+	assert.Equal(t, 200, w.Code)
+	var tokenInfo schema.LoginTokenInfo
+	assert.Nil(t, parseReader(w.Body, &tokenInfo))
+	assert.NotEmpty(t, tokenInfo.AccessToken)
+	assert.Nil(t, tokenInfo.MFAChallenge)
+
+	// 挑战令牌使用后失效
+	w = loginMFA(challenge, code)
+	assert.Equal(t, 401, w.Code)
+
+	// 同一时间步的动态口令不能重复使用
+	challenge = loginMFAChallenge(t)
+	w = loginMFA(challenge, code)
+	assert.Equal(t, 400, w.Code)
+
+	// 恢复码只能使用一次
+	w = loginMFA(challenge, codes.Codes[0])
+	assert.Equal(t, 200, w.Code)
+
+	challenge = loginMFAChallenge(t)
+	w = loginMFA(challenge, codes.Codes[0])
+	assert.Equal(t, 400, w.Code)
+	w = loginMFA(challenge, codes.Codes[1])
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestLoginUpdatePassword(t *testing.T) {
+	const router = apiPrefix + "v1/pub/current/password"
+
+	// 关闭历史密码校验，测试结束后恢复原密码
+	historyCount := config.C.PasswordPolicy.HistoryCount
+	baseDelay := config.C.LoginLockout.BaseDelay
+	defer func() {
+		config.C.PasswordPolicy.HistoryCount = historyCount
+		config.C.LoginLockout.BaseDelay = baseDelay
+	}()
+	config.C.PasswordPolicy.HistoryCount = 0
+	config.C.LoginLockout.BaseDelay = 0
+
+	userName := config.C.SuperAdmin.UserName
+	password := config.C.SuperAdmin.Password
+	newPassword := "Changed-123"
+
+	// put /pub/current/password (旧密码及新密码均为明文)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest(router, &schema.UpdatePasswordParam{
+		OldPassword: hash.MD5String(password),
+		NewPassword: newPassword,
+	}))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest(router, &schema.UpdatePasswordParam{
+		OldPassword: password,
+		NewPassword: newPassword,
+	}))
+	assert.Equal(t, 200, w.Code)
+	defer func() {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPutRequest(router, &schema.UpdatePasswordParam{
+			OldPassword: newPassword,
+			NewPassword: password,
+		}))
+		assert.Equal(t, 200, w.Code)
+	}()
+
+	// post /pub/login (使用新的明文密码登录)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(userName, password)))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(userName, newPassword)))
+	assert.Equal(t, 200, w.Code)
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
@@ -57,9 +58,116 @@ func TestMenu(t *testing.T) {
 		assert.Equal(t, putItem.Name, pageItems[0].Name)
 	}
 
+	// get /menus.export
+	ew := httptest.NewRecorder()
+	engine.ServeHTTP(ew, newGetRequest(apiPrefix+"v1/menus.export", nil))
+	assert.Equal(t, 200, ew.Code)
+	assert.Contains(t, ew.Header().Get("Content-Type"), "application/x-yaml")
+	assert.Contains(t, ew.Body.String(), "name: "+putItem.Name)
+
 	// delete /menus/:id
 	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, addItemRes.ID))
 	assert.Equal(t, 200, w.Code)
 	err = parseOK(w.Body)
 	assert.Nil(t, err)
 }
+
+func TestMenuMove(t *testing.T) {
+	const router = apiPrefix + "v1/menus"
+
+	createMenu := func(parentID uint64) uint64 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPostRequest(router, &schema.Menu{
+			Name:     uuid.MustUUID().String(),
+			ParentID: parentID,
+			IsShow:   1,
+			Status:   1,
+		}))
+		assert.Equal(t, 200, w.Code)
+		var res ResID
+		assert.Nil(t, parseReader(w.Body, &res))
+		return res.ID
+	}
+
+	getMenu := func(id uint64) *schema.Menu {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, id))
+		assert.Equal(t, 200, w.Code)
+		var item schema.Menu
+		assert.Nil(t, parseReader(w.Body, &item))
+		return &item
+	}
+
+	// post /menus: a -> b -> c, d
+	aID := createMenu(0)
+	bID := createMenu(aID)
+	cID := createMenu(bID)
+	dID := createMenu(0)
+	assert.Equal(t, fmt.Sprintf("%d/%d", aID, bID), getMenu(cID).ParentPath)
+
+	// put /menus/:id/move 不允许移动到下级菜单
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d/move", schema.MenuMoveParam{ParentID: cID}, router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	// put /menus/:id/move 下级菜单的父级路径同步更新
+	sequence := 5
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d/move", schema.MenuMoveParam{ParentID: 0, Sequence: &sequence}, router, bID))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "", getMenu(bID).ParentPath)
+	assert.Equal(t, 5, getMenu(bID).Sequence)
+	assert.Equal(t, fmt.Sprintf("%d", bID), getMenu(cID).ParentPath)
+
+	// put /menus.sort: a -> b -> c -> d
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest(apiPrefix+"v1/menus.sort", []*schema.MenuSortItem{
+		{ID: aID, ParentID: 0, Sequence: 3},
+		{ID: bID, ParentID: aID, Sequence: 2},
+		{ID: dID, ParentID: cID, Sequence: 1},
+	}))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, fmt.Sprintf("%d", aID), getMenu(bID).ParentPath)
+	assert.Equal(t, fmt.Sprintf("%d/%d", aID, bID), getMenu(cID).ParentPath)
+	assert.Equal(t, fmt.Sprintf("%d/%d/%d", aID, bID, cID), getMenu(dID).ParentPath)
+	assert.Equal(t, 3, getMenu(aID).Sequence)
+	assert.Equal(t, 2, getMenu(bID).Sequence)
+
+	// put /menus.sort 形成循环时不修改任何菜单
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest(apiPrefix+"v1/menus.sort", []*schema.MenuSortItem{
+		{ID: aID, ParentID: dID, Sequence: 9},
+	}))
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "", getMenu(aID).ParentPath)
+	assert.Equal(t, 3, getMenu(aID).Sequence)
+
+	// delete /menus/:id 存在下级菜单时需要级联删除
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	// get /menus/:id/impact
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest("%s/%d/impact", nil, router, aID))
+	assert.Equal(t, 200, w.Code)
+	var impact schema.MenuDeleteImpact
+	assert.Nil(t, parseReader(w.Body, &impact))
+	assert.Len(t, impact.Menus, 4)
+	assert.Equal(t, 10, impact.UserPagination.PageSize)
+
+	// get /menus/:id/impact?pageSize=101
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest("%s/%d/impact", map[string]string{"pageSize": "101"}, router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	// delete /menus/:id?cascade=true
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d?cascade=true", router, aID))
+	assert.Equal(t, 200, w.Code)
+	for _, id := range []uint64{aID, bID, cID, dID} {
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, id))
+		assert.Equal(t, 404, w.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func loginOIDC(t *testing.T, claims map[string]interface{}) (*httptest.ResponseRecorder, schema.LoginOIDCParam) {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/pub/login/oidc", nil))
+	assert.Equal(t, 200, w.Code)
+	var authURL schema.LoginOIDCURL
+	err := parseReader(w.Body, &authURL)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, authURL.State)
+
+	code, state, err := idp.Authorize(authURL.URL, claims)
+	assert.Nil(t, err)
+	assert.Equal(t, authURL.State, state)
+
+	param := schema.LoginOIDCParam{Code: code, State: state}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login/oidc", param))
+	return w, param
+}
+
+func TestLoginOIDC(t *testing.T) {
+	const router = apiPrefix + "v1/users"
+	var err error
+
+	w := httptest.NewRecorder()
+
+	// post /menus
+	addMenuItem := &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		IsShow: 1,
+		Status: 1,
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", addMenuItem))
+	assert.Equal(t, 200, w.Code)
+	var addMenuItemRes ResID
+	err = parseReader(w.Body, &addMenuItemRes)
+	assert.Nil(t, err)
+
+	// post /roles
+	addRoleItem := &schema.Role{
+		Name:   uuid.MustUUID().String(),
+		Status: 1,
+		RoleMenus: schema.RoleMenus{
+			&schema.RoleMenu{
+				MenuID: addMenuItemRes.ID,
+			},
+		},
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/roles", addRoleItem))
+	assert.Equal(t, 200, w.Code)
+	var addRoleItemRes ResID
+	err = parseReader(w.Body, &addRoleItemRes)
+	assert.Nil(t, err)
+
+	oidcConfig := config.C.OIDC
+	defer func() { config.C.OIDC = oidcConfig }()
+	config.C.OIDC.GroupRoles = map[string][]string{
+		"admins": {addRoleItem.Name},
+	}
+
+	userName := uuid.MustUUID().String()
+	claims := map[string]interface{}{
+		"sub":                uuid.MustUUID().String(),
+		"preferred_username": userName,
+		"email":              userName + "@example.com",
+		"email_verified":     true,
+		"name":               "OIDC User",
+		"groups":             []string{"admins"},
+	}
+
+	// 未开启自动创建用户
+	w, _ = loginOIDC(t, claims)
+	assert.Equal(t, 403, w.Code)
+
+	// post /pub/login/oidc
+	config.C.OIDC.AutoCreate = true
+	w, param := loginOIDC(t, claims)
+	assert.Equal(t, 200, w.Code)
+	var tokenInfo schema.LoginTokenInfo
+	err = parseReader(w.Body, &tokenInfo)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokenInfo.AccessToken)
+
+	// get /users?userName=
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(router, newPageParam(map[string]string{"userName": userName})))
+	assert.Equal(t, 200, w.Code)
+	var pageItems []*schema.UserShow
+	err = parsePageReader(w.Body, &pageItems)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(pageItems)) {
+		assert.Equal(t, "OIDC User", pageItems[0].RealName)
+		assert.Equal(t, []string{addRoleItem.Name}, schema.Roles(pageItems[0].Roles).ToNames())
+	}
+	userID := pageItems[0].ID
+
+	// 再次登录时按用户组同步角色
+	claims["groups"] = []string{}
+	w, _ = loginOIDC(t, claims)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, userID))
+	assert.Equal(t, 200, w.Code)
+	var getItem schema.User
+	err = parseReader(w.Body, &getItem)
+	assert.Nil(t, err)
+	assert.Equal(t, userName, getItem.UserName)
+	assert.Equal(t, 0, len(getItem.UserRoles))
+
+	// 同一授权码与状态不能重复使用
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login/oidc", param))
+	assert.Equal(t, 400, w.Code)
+
+	// 停用的用户不能登录
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPatchRequest("%s/%d/disable", router, userID))
+	assert.Equal(t, 200, w.Code)
+	w, _ = loginOIDC(t, claims)
+	assert.Equal(t, 400, w.Code)
+
+	// delete /users/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, userID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// delete /roles/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/roles/%d", addRoleItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// delete /menus/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", addMenuItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionExplain(t *testing.T) {
+	const router = apiPrefix + "v1/permissions.explain"
+
+	// post /menus
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		IsShow: 1,
+		Status: 1,
+		Actions: schema.MenuActions{
+			&schema.MenuAction{
+				Code: "query",
+				Name: "查询",
+				Resources: schema.MenuActionResources{
+					&schema.MenuActionResource{Method: "GET", Path: "/api/v1/explain/:id"},
+				},
+			},
+		},
+	}))
+	assert.Equal(t, 200, w.Code)
+	var menuRes ResID
+	assert.Nil(t, parseReader(w.Body, &menuRes))
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/menus/%d", nil, menuRes.ID))
+	assert.Equal(t, 200, w.Code)
+	var menu schema.Menu
+	assert.Nil(t, parseReader(w.Body, &menu))
+
+	// post /roles
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/roles", &schema.Role{
+		Name:   uuid.MustUUID().String(),
+		Status: 1,
+		RoleMenus: schema.RoleMenus{
+			&schema.RoleMenu{MenuID: menuRes.ID, ActionID: menu.Actions[0].ID},
+		},
+	}))
+	assert.Equal(t, 200, w.Code)
+	var roleRes ResID
+	assert.Nil(t, parseReader(w.Body, &roleRes))
+
+	// get /permissions.explain 用户ID与角色ID必须指定一个
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(router, map[string]string{"method": "GET", "path": "/api/v1/explain/1"}))
+	assert.Equal(t, 400, w.Code)
+
+	// get /permissions.explain
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(router, map[string]string{
+		"roleID": fmt.Sprintf("%d", roleRes.ID),
+		"method": "GET",
+		"path":   "/api/v1/explain/1",
+	}))
+	assert.Equal(t, 200, w.Code)
+	var result schema.PermissionExplain
+	assert.Nil(t, parseReader(w.Body, &result))
+	if assert.Equal(t, 1, len(result.Policies)) && assert.Equal(t, 1, len(result.Policies[0].Sources)) {
+		assert.True(t, result.Policies[0].PathMatched && result.Policies[0].MethodMatched)
+		assert.Equal(t, menu.Actions[0].ID, result.Policies[0].Sources[0].ActionID)
+	}
+
+	// delete /roles/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/roles/%d", roleRes.ID))
+	assert.Equal(t, 200, w.Code)
+
+	// delete /menus/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", menuRes.ID))
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestPermissionCheck(t *testing.T) {
+	const router = apiPrefix + "v1/pub/current/permissions"
+
+	// post /menus
+	menuRouter := "/" + uuid.MustUUID().String()
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		Router: menuRouter,
+		IsShow: 1,
+		Status: 1,
+		Actions: schema.MenuActions{
+			&schema.MenuAction{
+				Code: "add",
+				Name: "新增",
+				Resources: schema.MenuActionResources{
+					&schema.MenuActionResource{Method: "POST", Path: "/api/v1/explain"},
+				},
+			},
+		},
+	}))
+	assert.Equal(t, 200, w.Code)
+	var menuRes ResID
+	assert.Nil(t, parseReader(w.Body, &menuRes))
+
+	// post /pub/current/permissions 校验项不完整
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, schema.PermissionCheckParam{
+		Items: []*schema.PermissionCheckItem{{Method: "GET"}},
+	}))
+	assert.Equal(t, 400, w.Code)
+
+	// post /pub/current/permissions
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, schema.PermissionCheckParam{
+		Items: []*schema.PermissionCheckItem{
+			{Router: menuRouter, Code: "add"},
+			{Router: menuRouter, Code: "none"},
+			{Method: "GET", Path: "/api/v1/users"},
+		},
+	}))
+	assert.Equal(t, 200, w.Code)
+	var result []*schema.PermissionCheckResult
+	assert.Nil(t, parseReader(w.Body, &schema.ListResult{List: &result}))
+	if assert.Equal(t, 3, len(result)) {
+		assert.True(t, result[0].Allowed)
+		assert.False(t, result[1].Allowed)
+		assert.True(t, result[2].Allowed)
+	}
+
+	// delete /menus/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", menuRes.ID))
+	assert.Equal(t, 200, w.Code)
+}
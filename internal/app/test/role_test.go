@@ -51,16 +51,31 @@ func TestRole(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, addItem.Name, getItem.Name)
 	assert.Equal(t, addItem.Status, getItem.Status)
+	assert.Equal(t, schema.DataScopeAll, getItem.DataScope)
 	assert.NotEmpty(t, getItem.ID)
 
 	// put /roles/:id
 	putItem := getItem
 	putItem.Name = uuid.MustUUID().String()
+	putItem.DataScope = schema.DataScopeCustom
+	putItem.RoleDataUsers = schema.RoleDataUsers{
+		&schema.RoleDataUser{UserID: 1},
+	}
 	engine.ServeHTTP(w, newPutRequest("%s/%d", putItem, router, getItem.ID))
 	assert.Equal(t, 200, w.Code)
 	err = parseOK(w.Body)
 	assert.Nil(t, err)
 
+	engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	var getScopeItem schema.Role
+	err = parseReader(w.Body, &getScopeItem)
+	assert.Nil(t, err)
+	assert.Equal(t, schema.DataScopeCustom, getScopeItem.DataScope)
+	if assert.Equal(t, 1, len(getScopeItem.RoleDataUsers)) {
+		assert.Equal(t, uint64(1), getScopeItem.RoleDataUsers[0].UserID)
+	}
+
 	// query /roles
 	engine.ServeHTTP(w, newGetRequest(router, newPageParam()))
 	assert.Equal(t, 200, w.Code)
@@ -85,3 +100,101 @@ func TestRole(t *testing.T) {
 	err = parseOK(w.Body)
 	assert.Nil(t, err)
 }
+
+func TestRoleParent(t *testing.T) {
+	const router = apiPrefix + "v1/roles"
+
+	// post /menus
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		IsShow: 1,
+		Status: 1,
+	}))
+	assert.Equal(t, 200, w.Code)
+	var menuRes ResID
+	assert.Nil(t, parseReader(w.Body, &menuRes))
+
+	createRole := func(parentIDs ...uint64) uint64 {
+		item := &schema.Role{
+			Name:   uuid.MustUUID().String(),
+			Status: 1,
+			RoleMenus: schema.RoleMenus{
+				&schema.RoleMenu{MenuID: menuRes.ID},
+			},
+		}
+		for _, id := range parentIDs {
+			item.RoleParents = append(item.RoleParents, &schema.RoleParent{ParentID: id})
+		}
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPostRequest(router, item))
+		assert.Equal(t, 200, w.Code)
+		var res ResID
+		assert.Nil(t, parseReader(w.Body, &res))
+		return res.ID
+	}
+
+	getRole := func(id uint64) *schema.Role {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, id))
+		assert.Equal(t, 200, w.Code)
+		var item schema.Role
+		assert.Nil(t, parseReader(w.Body, &item))
+		return &item
+	}
+
+	// post /roles: b继承a
+	aID := createRole()
+	bID := createRole(aID)
+	if roleParents := getRole(bID).RoleParents; assert.Equal(t, 1, len(roleParents)) {
+		assert.Equal(t, aID, roleParents[0].ParentID)
+	}
+
+	// post /roles 继承的角色不存在
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, &schema.Role{
+		Name:        uuid.MustUUID().String(),
+		Status:      1,
+		RoleMenus:   schema.RoleMenus{&schema.RoleMenu{MenuID: menuRes.ID}},
+		RoleParents: schema.RoleParents{&schema.RoleParent{ParentID: 1}},
+	}))
+	assert.Equal(t, 400, w.Code)
+
+	// put /roles/:id 不允许循环继承
+	putItem := getRole(aID)
+	putItem.RoleParents = schema.RoleParents{&schema.RoleParent{ParentID: bID}}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d", putItem, router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	putItem.RoleParents = schema.RoleParents{&schema.RoleParent{ParentID: aID}}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d", putItem, router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	// delete /roles/:id 不允许删除被继承的角色
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, aID))
+	assert.Equal(t, 400, w.Code)
+
+	// put /roles/:id 取消继承
+	putItem = getRole(bID)
+	putItem.RoleParents = nil
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d", putItem, router, bID))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, 0, len(getRole(bID).RoleParents))
+
+	// delete /roles/:id
+	for _, id := range []uint64{aID, bID} {
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, id))
+		assert.Equal(t, 200, w.Code)
+	}
+
+	// delete /menus/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", menuRes.ID))
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
+	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+)
+
+func TestTenant(t *testing.T) {
+	const router = apiPrefix + "v1/tenants"
+	var err error
+
+	tenantConfig := config.C.Tenant
+	defer func() { config.C.Tenant = tenantConfig }()
+	config.C.Tenant.Enable = true
+	config.C.Tenant.Header = "X-Tenant"
+
+	code := uuid.MustString()[:8]
+	w := httptest.NewRecorder()
+
+	// post /tenants (租户编号格式不正确)
+	engine.ServeHTTP(w, newPostRequest(router, &schema.Tenant{Code: "Acme_1", Name: "acme", Status: 1}))
+	assert.Equal(t, 400, w.Code)
+
+	// post /tenants (同时创建租户的超级管理员)
+	addItem := &schema.Tenant{
+		Code:          code,
+		Name:          "acme",
+		Status:        1,
+		AdminUserName: "root",
+		AdminPassword: "secret",
+	}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, addItem))
+	assert.Equal(t, 200, w.Code)
+	var addItemRes ResID
+	err = parseReader(w.Body, &addItemRes)
+	assert.Nil(t, err)
+
+	// post /tenants (租户编号重复)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, addItem))
+	assert.Equal(t, 400, w.Code)
+
+	// get /tenants?queryValue=code
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest(router, newPageParam(map[string]string{"queryValue": code})))
+	assert.Equal(t, 200, w.Code)
+	var pageItems []*schema.Tenant
+	err = parsePageReader(w.Body, &pageItems)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(pageItems)) {
+		assert.Equal(t, addItemRes.ID, pageItems[0].ID)
+		assert.Empty(t, pageItems[0].AdminPassword)
+	}
+
+	// post /pub/login (租户的用户仅能在该租户下登录，与平台用户同名互不影响)
+	req := newPostRequest(apiPrefix+"v1/pub/login", newLoginParam("root", "secret"))
+	req.Header.Set("X-Tenant", code)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam("root", "secret")))
+	assert.NotEqual(t, 200, w.Code)
+
+	// 平台用户访问租户的接口
+	req = newGetRequest(apiPrefix+"v1/users", newPageParam())
+	req.Header.Set("X-Tenant", code)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	// 不存在的租户
+	req = newGetRequest(apiPrefix+"v1/users", newPageParam())
+	req.Header.Set("X-Tenant", uuid.MustString()[:8])
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// delete /tenants/:id (租户下存在用户)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, addItemRes.ID))
+	assert.Equal(t, 400, w.Code)
+
+	// patch /tenants/:id/disable (停用后拒绝该租户的请求)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPatchRequest("%s/%d/disable", router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+
+	req = newPostRequest(apiPrefix+"v1/pub/login", newLoginParam("root", "secret"))
+	req.Header.Set("X-Tenant", code)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// post /tenants (未创建管理员的租户可以删除)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, &schema.Tenant{Code: code + "-b", Name: "empty", Status: 1}))
+	assert.Equal(t, 200, w.Code)
+	var emptyItemRes ResID
+	err = parseReader(w.Body, &emptyItemRes)
+	assert.Nil(t, err)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, emptyItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+}
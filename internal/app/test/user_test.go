@@ -4,6 +4,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/LyricTian/gin-admin/v8/internal/app/config"
 	"github.com/LyricTian/gin-admin/v8/internal/app/schema"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/hash"
 	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
@@ -49,7 +50,7 @@ func TestUser(t *testing.T) {
 		UserName: uuid.MustUUID().String(),
 		RealName: uuid.MustUUID().String(),
 		Status:   1,
-		Password: hash.MD5String("test"),
+		Password: "test",
 		UserRoles: schema.UserRoles{
 			&schema.UserRole{
 				RoleID: addRoleItemRes.ID,
@@ -71,6 +72,29 @@ func TestUser(t *testing.T) {
 	assert.Equal(t, addItem.UserName, getItem.UserName)
 	assert.Equal(t, addItem.Status, getItem.Status)
 	assert.NotEmpty(t, getItem.ID)
+	assert.Equal(t, 0, getItem.LoginFailures)
+	assert.Nil(t, getItem.LockedUntil)
+	assert.False(t, getItem.MFAEnabled)
+
+	// patch /users/:id/unlock
+	engine.ServeHTTP(w, newPatchRequest("%s/%d/unlock", router, getItem.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// get /users/:id/apikeys
+	engine.ServeHTTP(w, newGetRequest("%s/%d/apikeys", nil, router, getItem.ID))
+	assert.Equal(t, 200, w.Code)
+	var apiKeys []*schema.UserAPIKey
+	err = parsePageReader(w.Body, &apiKeys)
+	assert.Nil(t, err)
+	assert.Len(t, apiKeys, 0)
+
+	// delete /users/:id/mfa
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d/mfa", router, getItem.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
 
 	// put /users/:id
 	putItem := getItem
@@ -80,8 +104,25 @@ func TestUser(t *testing.T) {
 	err = parseOK(w.Body)
 	assert.Nil(t, err)
 
+	// put /users/:id (不允许重复使用最近的密码)
+	rw := httptest.NewRecorder()
+	pwdItem := putItem
+	pwdItem.Password = addItem.Password
+	engine.ServeHTTP(rw, newPutRequest("%s/%d", pwdItem, router, getItem.ID))
+	assert.Equal(t, 400, rw.Code)
+	var errRes struct {
+		Error struct {
+			Details []*schema.PasswordViolation `json:"details"`
+		} `json:"error"`
+	}
+	err = parseReader(rw.Body, &errRes)
+	assert.Nil(t, err)
+	if assert.Len(t, errRes.Error.Details, 1) {
+		assert.Equal(t, "history", errRes.Error.Details[0].Rule)
+	}
+
 	// query /users
-	engine.ServeHTTP(w, newGetRequest(router, newPageParam()))
+	engine.ServeHTTP(w, newGetRequest(router, newPageParam(map[string]string{"queryValue": putItem.UserName})))
 	assert.Equal(t, 200, w.Code)
 	var pageItems []*schema.User
 	err = parsePageReader(w.Body, &pageItems)
@@ -110,3 +151,246 @@ func TestUser(t *testing.T) {
 	err = parseOK(w.Body)
 	assert.Nil(t, err)
 }
+
+func TestSuperAdmin(t *testing.T) {
+	const router = apiPrefix + "v1/users"
+	var err error
+
+	w := httptest.NewRecorder()
+
+	// get /pub/current/user (未开启认证时为配置的超级管理员)
+	engine.ServeHTTP(w, newGetRequest(apiPrefix+"v1/pub/current/user", nil))
+	assert.Equal(t, 200, w.Code)
+	var loginInfo schema.UserLoginInfo
+	err = parseReader(w.Body, &loginInfo)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, loginInfo.UserID)
+	assert.True(t, loginInfo.IsSuperAdmin)
+
+	// post /menus
+	addMenuItem := &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		IsShow: 1,
+		Status: 1,
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", addMenuItem))
+	assert.Equal(t, 200, w.Code)
+	var addMenuItemRes ResID
+	err = parseReader(w.Body, &addMenuItemRes)
+	assert.Nil(t, err)
+
+	// post /roles
+	addRoleItem := &schema.Role{
+		Name:   uuid.MustUUID().String(),
+		Status: 1,
+		RoleMenus: schema.RoleMenus{
+			&schema.RoleMenu{
+				MenuID: addMenuItemRes.ID,
+			},
+		},
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/roles", addRoleItem))
+	assert.Equal(t, 200, w.Code)
+	var addRoleItemRes ResID
+	err = parseReader(w.Body, &addRoleItemRes)
+	assert.Nil(t, err)
+
+	// post /users (超级管理员)
+	addItem := &schema.User{
+		UserName:     uuid.MustUUID().String(),
+		RealName:     uuid.MustUUID().String(),
+		Status:       1,
+		Password:     "test",
+		IsSuperAdmin: true,
+		UserRoles: schema.UserRoles{
+			&schema.UserRole{
+				RoleID: addRoleItemRes.ID,
+			},
+		},
+	}
+	engine.ServeHTTP(w, newPostRequest(router, addItem))
+	assert.Equal(t, 200, w.Code)
+	var addItemRes ResID
+	err = parseReader(w.Body, &addItemRes)
+	assert.Nil(t, err)
+
+	// get /users/:id
+	engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	var getItem schema.User
+	err = parseReader(w.Body, &getItem)
+	assert.Nil(t, err)
+	assert.True(t, getItem.IsSuperAdmin)
+
+	// patch /users/:id/disable
+	engine.ServeHTTP(w, newPatchRequest("%s/%d/disable", router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// 至少保留一个启用的超级管理员
+	rw := httptest.NewRecorder()
+	engine.ServeHTTP(rw, newPatchRequest("%s/%d/disable", router, loginInfo.UserID))
+	assert.Equal(t, 400, rw.Code)
+
+	rw = httptest.NewRecorder()
+	engine.ServeHTTP(rw, newDeleteRequest("%s/%d", router, loginInfo.UserID))
+	assert.Equal(t, 400, rw.Code)
+
+	// patch /users/:id/enable
+	engine.ServeHTTP(w, newPatchRequest("%s/%d/enable", router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// delete /users/:id
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// delete /roles/:id
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/roles/%d", addRoleItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+
+	// delete /menus/:id
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", addMenuItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	err = parseOK(w.Body)
+	assert.Nil(t, err)
+}
+
+func TestUserPasswordPolicy(t *testing.T) {
+	const router = apiPrefix + "v1/users"
+	var err error
+
+	policy := config.C.PasswordPolicy
+	defer func() { config.C.PasswordPolicy = policy }()
+	config.C.PasswordPolicy.MinLength = 8
+	config.C.PasswordPolicy.RequireUpper = true
+	config.C.PasswordPolicy.RequireDigit = true
+	config.C.PasswordPolicy.RequireSymbol = true
+
+	w := httptest.NewRecorder()
+
+	// post /menus
+	addMenuItem := &schema.Menu{
+		Name:   uuid.MustUUID().String(),
+		IsShow: 1,
+		Status: 1,
+	}
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/menus", addMenuItem))
+	assert.Equal(t, 200, w.Code)
+	var addMenuItemRes ResID
+	err = parseReader(w.Body, &addMenuItemRes)
+	assert.Nil(t, err)
+
+	// post /roles
+	addRoleItem := &schema.Role{
+		Name:   uuid.MustUUID().String(),
+		Status: 1,
+		RoleMenus: schema.RoleMenus{
+			&schema.RoleMenu{
+				MenuID: addMenuItemRes.ID,
+			},
+		},
+	}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/roles", addRoleItem))
+	assert.Equal(t, 200, w.Code)
+	var addRoleItemRes ResID
+	err = parseReader(w.Body, &addRoleItemRes)
+	assert.Nil(t, err)
+
+	// post /users (复杂度规则校验明文密码)
+	addItem := &schema.User{
+		UserName: uuid.MustUUID().String(),
+		RealName: uuid.MustUUID().String(),
+		Status:   1,
+		Password: "secret",
+		UserRoles: schema.UserRoles{
+			&schema.UserRole{
+				RoleID: addRoleItemRes.ID,
+			},
+		},
+	}
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, addItem))
+	assert.Equal(t, 400, w.Code)
+	var errRes struct {
+		Error struct {
+			Details []*schema.PasswordViolation `json:"details"`
+		} `json:"error"`
+	}
+	err = parseReader(w.Body, &errRes)
+	assert.Nil(t, err)
+	rules := make([]string, len(errRes.Error.Details))
+	for i, item := range errRes.Error.Details {
+		rules[i] = item.Rule
+	}
+	assert.ElementsMatch(t, []string{"min_length", "upper", "digit", "symbol"}, rules)
+
+	addItem.Password = "Secret-123"
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(router, addItem))
+	assert.Equal(t, 200, w.Code)
+	var addItemRes ResID
+	err = parseReader(w.Body, &addItemRes)
+	assert.Nil(t, err)
+
+	// post /pub/login (登录时同样提交明文密码)
+	login := func() *schema.LoginTokenInfo {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(addItem.UserName, addItem.Password)))
+		assert.Equal(t, 200, w.Code)
+		var tokenInfo schema.LoginTokenInfo
+		err := parseReader(w.Body, &tokenInfo)
+		assert.Nil(t, err)
+		return &tokenInfo
+	}
+	assert.False(t, login().MustChangePassword)
+
+	// put /users/:id (设定下次登录必须修改密码)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newGetRequest("%s/%d", nil, router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	var getItem schema.User
+	err = parseReader(w.Body, &getItem)
+	assert.Nil(t, err)
+
+	getItem.MustChangePassword = true
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d", getItem, router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	assert.True(t, login().MustChangePassword)
+
+	// put /users/:id (修改密码后使用新的明文密码登录)
+	getItem.Password = "Changed-456"
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPutRequest("%s/%d", getItem, router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+	addItem.Password = getItem.Password
+	login()
+
+	// 不再接受md5加密的密码
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newPostRequest(apiPrefix+"v1/pub/login", newLoginParam(addItem.UserName, hash.MD5String(addItem.Password))))
+	assert.Equal(t, 400, w.Code)
+
+	// delete /users/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest("%s/%d", router, addItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+
+	// delete /roles/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/roles/%d", addRoleItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+
+	// delete /menus/:id
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, newDeleteRequest(apiPrefix+"v1/menus/%d", addMenuItemRes.ID))
+	assert.Equal(t, 200, w.Code)
+}
@@ -17,7 +17,13 @@ func BuildInjector() (*Injector, func(), error) {
 	wire.Build(
 		InitGormDB,
 		dao.RepoSet,
+		InitPasswordHasher,
+		InitLoginLockout,
+		InitOIDC,
+		InitLDAP,
+		InitJWTKeySet,
 		InitAuth,
+		InitCasbinWatcher,
 		InitCasbin,
 		InitGinEngine,
 		service.ServiceSet,
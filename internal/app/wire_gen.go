@@ -1,14 +1,18 @@
 // Code generated by Wire. DO NOT EDIT.
 
 //go:generate wire
-//+build !wireinject
+//go:build !wireinject
+// +build !wireinject
 
 package app
 
 import (
 	"github.com/LyricTian/gin-admin/v8/internal/app/api"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/dept"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/menu"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/policy"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/role"
+	"github.com/LyricTian/gin-admin/v8/internal/app/dao/tenant"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/user"
 	"github.com/LyricTian/gin-admin/v8/internal/app/dao/util"
 	"github.com/LyricTian/gin-admin/v8/internal/app/module/adapter"
@@ -23,7 +27,11 @@ import (
 // Injectors from wire.go:
 
 func BuildInjector() (*Injector, func(), error) {
-	auther, cleanup, err := InitAuth()
+	keySet, err := InitJWTKeySet()
+	if err != nil {
+		return nil, nil, err
+	}
+	auther, cleanup, err := InitAuth(keySet)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -47,86 +55,312 @@ func BuildInjector() (*Injector, func(), error) {
 	userRoleRepo := &user.UserRoleRepo{
 		DB: db,
 	}
+	roleParentRepo := &role.RoleParentRepo{
+		DB: db,
+	}
 	casbinAdapter := &adapter.CasbinAdapter{
 		RoleRepo:         roleRepo,
 		RoleMenuRepo:     roleMenuRepo,
+		RoleParentRepo:   roleParentRepo,
 		MenuResourceRepo: menuActionResourceRepo,
 		UserRepo:         userRepo,
 		UserRoleRepo:     userRoleRepo,
 	}
-	syncedEnforcer, cleanup3, err := InitCasbin(casbinAdapter)
+	policyVersionRepo := &policy.PolicyVersionRepo{
+		DB: db,
+	}
+	watcherWatcher, cleanup3, err := InitCasbinWatcher(policyVersionRepo)
 	if err != nil {
 		cleanup2()
 		cleanup()
 		return nil, nil, err
 	}
+	syncedEnforcer, cleanup4, err := InitCasbin(casbinAdapter, watcherWatcher)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	menuRepo := &menu.MenuRepo{
 		DB: db,
 	}
 	menuActionRepo := &menu.MenuActionRepo{
 		DB: db,
 	}
+	passwordHasher, err := InitPasswordHasher()
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	lockoutLockout, cleanup5, err := InitLoginLockout()
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	provider, cleanup6, err := InitOIDC()
+	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	trans := &util.Trans{
+		DB: db,
+	}
+	userPasswordRepo := &user.UserPasswordRepo{
+		DB: db,
+	}
+	passwordSrv := &service.PasswordSrv{
+		PasswordHasher:   passwordHasher,
+		UserPasswordRepo: userPasswordRepo,
+	}
+	lockoutSrv := &service.LockoutSrv{
+		Lockout: lockoutLockout,
+	}
+	userMFARepo := &user.UserMFARepo{
+		DB: db,
+	}
+	userRecoveryCodeRepo := &user.UserRecoveryCodeRepo{
+		DB: db,
+	}
+	mfaSrv := &service.MFASrv{
+		TransRepo:            trans,
+		UserRepo:             userRepo,
+		UserMFARepo:          userMFARepo,
+		UserRecoveryCodeRepo: userRecoveryCodeRepo,
+	}
+	userAPIKeyRepo := &user.UserAPIKeyRepo{
+		DB: db,
+	}
+	userAPIKeyRoleRepo := &user.UserAPIKeyRoleRepo{
+		DB: db,
+	}
+	apiKeySrv := &service.APIKeySrv{
+		TransRepo:          trans,
+		UserRepo:           userRepo,
+		UserRoleRepo:       userRoleRepo,
+		UserAPIKeyRepo:     userAPIKeyRepo,
+		UserAPIKeyRoleRepo: userAPIKeyRoleRepo,
+	}
+	superAdminSrv := &service.SuperAdminSrv{
+		Auth:          auther,
+		Enforcer:      syncedEnforcer,
+		CasbinWatcher: watcherWatcher,
+		TransRepo:     trans,
+		UserRepo:      userRepo,
+		UserRoleRepo:  userRoleRepo,
+		PasswordSrv:   passwordSrv,
+	}
+	localAuthenticator := &service.LocalAuthenticator{
+		UserRepo:       userRepo,
+		PasswordHasher: passwordHasher,
+	}
+	client := InitLDAP()
+	userIdentityRepo := &user.UserIdentityRepo{
+		DB: db,
+	}
+	identitySrv := &service.IdentitySrv{
+		Enforcer:         syncedEnforcer,
+		CasbinWatcher:    watcherWatcher,
+		TransRepo:        trans,
+		UserRepo:         userRepo,
+		UserRoleRepo:     userRoleRepo,
+		RoleRepo:         roleRepo,
+		UserIdentityRepo: userIdentityRepo,
+		PasswordSrv:      passwordSrv,
+	}
+	ldapAuthenticator := &service.LDAPAuthenticator{
+		Client:      client,
+		IdentitySrv: identitySrv,
+	}
+	authenticators, err := service.NewAuthenticators(localAuthenticator, ldapAuthenticator)
+	if err != nil {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	loginSrv := &service.LoginSrv{
 		Auth:           auther,
+		TransRepo:      trans,
+		KeySet:         keySet,
+		PasswordSrv:    passwordSrv,
+		LockoutSrv:     lockoutSrv,
+		MFASrv:         mfaSrv,
+		Authenticators: authenticators,
 		UserRepo:       userRepo,
 		UserRoleRepo:   userRoleRepo,
 		RoleRepo:       roleRepo,
 		RoleMenuRepo:   roleMenuRepo,
+		RoleParentRepo: roleParentRepo,
 		MenuRepo:       menuRepo,
 		MenuActionRepo: menuActionRepo,
+		SuperAdminSrv:  superAdminSrv,
 	}
-	loginAPI := &api.LoginAPI{
-		LoginSrv: loginSrv,
+	sessionSrv := &service.SessionSrv{
+		Auth:     auther,
+		UserRepo: userRepo,
 	}
-	trans := &util.Trans{
-		DB: db,
+	oidcSrv := &service.OIDCSrv{
+		Provider:    provider,
+		IdentitySrv: identitySrv,
+	}
+	permissionSrv := &service.PermissionSrv{
+		Enforcer:               syncedEnforcer,
+		CasbinAdapter:          casbinAdapter,
+		UserRepo:               userRepo,
+		UserRoleRepo:           userRoleRepo,
+		RoleRepo:               roleRepo,
+		RoleMenuRepo:           roleMenuRepo,
+		RoleParentRepo:         roleParentRepo,
+		MenuRepo:               menuRepo,
+		MenuActionRepo:         menuActionRepo,
+		MenuActionResourceRepo: menuActionResourceRepo,
+		SuperAdminSrv:          superAdminSrv,
+	}
+	loginAPI := &api.LoginAPI{
+		LoginSrv:      loginSrv,
+		SessionSrv:    sessionSrv,
+		MFASrv:        mfaSrv,
+		APIKeySrv:     apiKeySrv,
+		OIDCSrv:       oidcSrv,
+		PermissionSrv: permissionSrv,
 	}
 	menuSrv := &service.MenuSrv{
+		Enforcer:               syncedEnforcer,
+		CasbinWatcher:          watcherWatcher,
 		TransRepo:              trans,
 		MenuRepo:               menuRepo,
 		MenuActionRepo:         menuActionRepo,
 		MenuActionResourceRepo: menuActionResourceRepo,
+		RoleRepo:               roleRepo,
+		RoleMenuRepo:           roleMenuRepo,
+		RoleParentRepo:         roleParentRepo,
+		UserRepo:               userRepo,
 	}
 	menuAPI := &api.MenuAPI{
 		MenuSrv: menuSrv,
 	}
+	deptRepo := &dept.DeptRepo{
+		DB: db,
+	}
+	deptSrv := &service.DeptSrv{
+		TransRepo: trans,
+		DeptRepo:  deptRepo,
+		UserRepo:  userRepo,
+	}
+	deptAPI := &api.DeptAPI{
+		DeptSrv: deptSrv,
+	}
+	roleDataUserRepo := &role.RoleDataUserRepo{
+		DB: db,
+	}
 	roleSrv := &service.RoleSrv{
 		Enforcer:               syncedEnforcer,
+		CasbinWatcher:          watcherWatcher,
 		TransRepo:              trans,
 		RoleRepo:               roleRepo,
 		RoleMenuRepo:           roleMenuRepo,
+		RoleDataUserRepo:       roleDataUserRepo,
+		RoleParentRepo:         roleParentRepo,
 		UserRepo:               userRepo,
 		MenuActionResourceRepo: menuActionResourceRepo,
 	}
 	roleAPI := &api.RoleAPI{
 		RoleSrv: roleSrv,
 	}
+	userDeptRepo := &user.UserDeptRepo{
+		DB: db,
+	}
 	userSrv := &service.UserSrv{
-		Enforcer:     syncedEnforcer,
-		TransRepo:    trans,
-		UserRepo:     userRepo,
-		UserRoleRepo: userRoleRepo,
-		RoleRepo:     roleRepo,
+		Auth:             auther,
+		Enforcer:         syncedEnforcer,
+		CasbinWatcher:    watcherWatcher,
+		TransRepo:        trans,
+		UserRepo:         userRepo,
+		UserRoleRepo:     userRoleRepo,
+		UserDeptRepo:     userDeptRepo,
+		RoleRepo:         roleRepo,
+		RoleDataUserRepo: roleDataUserRepo,
+		DeptSrv:          deptSrv,
+		PasswordHasher:   passwordHasher,
+		PasswordSrv:      passwordSrv,
+		LockoutSrv:       lockoutSrv,
+		MFASrv:           mfaSrv,
+		APIKeySrv:        apiKeySrv,
+		UserIdentityRepo: userIdentityRepo,
+		SuperAdminSrv:    superAdminSrv,
 	}
 	userAPI := &api.UserAPI{
-		UserSrv: userSrv,
+		UserSrv:    userSrv,
+		SessionSrv: sessionSrv,
+	}
+	dataScopeSrv := &service.DataScopeSrv{
+		UserRepo:         userRepo,
+		UserRoleRepo:     userRoleRepo,
+		UserDeptRepo:     userDeptRepo,
+		RoleRepo:         roleRepo,
+		RoleDataUserRepo: roleDataUserRepo,
+		DeptSrv:          deptSrv,
+		SuperAdminSrv:    superAdminSrv,
+	}
+	permissionAPI := &api.PermissionAPI{
+		PermissionSrv: permissionSrv,
+	}
+	tenantRepo := &tenant.TenantRepo{
+		DB: db,
+	}
+	tenantSrv := &service.TenantSrv{
+		TransRepo:     trans,
+		TenantRepo:    tenantRepo,
+		UserRepo:      userRepo,
+		RoleRepo:      roleRepo,
+		SuperAdminSrv: superAdminSrv,
+	}
+	tenantAPI := &api.TenantAPI{
+		TenantSrv: tenantSrv,
 	}
 	routerRouter := &router.Router{
-		Auth:           auther,
-		CasbinEnforcer: syncedEnforcer,
-		LoginAPI:       loginAPI,
-		MenuAPI:        menuAPI,
-		RoleAPI:        roleAPI,
-		UserAPI:        userAPI,
+		Auth:          auther,
+		APIKeySrv:     apiKeySrv,
+		SuperAdminSrv: superAdminSrv,
+		TenantSrv:     tenantSrv,
+		PermissionSrv: permissionSrv,
+		DataScopeSrv:  dataScopeSrv,
+		LoginAPI:      loginAPI,
+		MenuAPI:       menuAPI,
+		DeptAPI:       deptAPI,
+		RoleAPI:       roleAPI,
+		UserAPI:       userAPI,
+		PermissionAPI: permissionAPI,
+		TenantAPI:     tenantAPI,
 	}
 	engine := InitGinEngine(routerRouter)
 	injector := &Injector{
 		Engine:         engine,
+		Router:         routerRouter,
 		Auth:           auther,
 		CasbinEnforcer: syncedEnforcer,
 		MenuSrv:        menuSrv,
+		SuperAdminSrv:  superAdminSrv,
+		PermissionSrv:  permissionSrv,
 	}
 	return injector, func() {
+		cleanup6()
+		cleanup5()
+		cleanup4()
 		cleanup3()
 		cleanup2()
 		cleanup()
@@ -3,13 +3,92 @@ package auth
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 // 定义错误
 var (
-	ErrInvalidToken = errors.New("invalid token")
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrSessionNotFound = errors.New("session not found")
 )
 
+// Session 登录会话(同一次登录通过刷新令牌轮换产生的令牌共享同一会话)
+type Session struct {
+	ID         string `json:"id"`           // 会话ID(令牌jti)
+	Subject    string `json:"subject"`      // 令牌主体
+	IP         string `json:"ip"`           // 客户端IP
+	UserAgent  string `json:"user_agent"`   // 客户端标识
+	CreatedAt  int64  `json:"created_at"`   // 创建时间戳
+	LastSeenAt int64  `json:"last_seen_at"` // 最近活动时间戳
+	ExpiresAt  int64  `json:"expires_at"`   // 过期时间戳
+	Scope      string `json:"scope"`        // 令牌权限范围
+	Tenant     string `json:"tenant"`       // 令牌所属租户
+}
+
+// 令牌权限范围
+const (
+	// ScopePasswordChange 仅允许修改密码(需要修改密码或密码已过期)
+	ScopePasswordChange = "password_change"
+)
+
+// Claims 令牌声明
+type Claims struct {
+	Subject   string // 令牌主体
+	SessionID string // 会话ID
+	Scope     string // 权限范围(为空表示不受限制)
+	Tenant    string // 所属租户(为空表示未区分租户)
+}
+
+type scopeCtx struct{}
+
+// NewScopeContext 设定签发令牌的权限范围
+func NewScopeContext(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeCtx{}, scope)
+}
+
+// FromScopeContext 获取签发令牌的权限范围
+func FromScopeContext(ctx context.Context) string {
+	if v, ok := ctx.Value(scopeCtx{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+type tenantCtx struct{}
+
+// NewTenantContext 设定签发令牌所属的租户
+func NewTenantContext(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantCtx{}, tenant)
+}
+
+// FromTenantContext 获取签发令牌所属的租户
+func FromTenantContext(ctx context.Context) string {
+	if v, ok := ctx.Value(tenantCtx{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+type clientCtx struct{}
+
+type clientInfo struct {
+	ip        string
+	userAgent string
+}
+
+// NewClientContext 设定客户端信息(用于记录会话)
+func NewClientContext(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientCtx{}, clientInfo{ip: ip, userAgent: userAgent})
+}
+
+// FromClientContext 获取客户端信息
+func FromClientContext(ctx context.Context) (ip, userAgent string) {
+	if v, ok := ctx.Value(clientCtx{}).(clientInfo); ok {
+		return v.ip, v.userAgent
+	}
+	return "", ""
+}
+
 // TokenInfo 令牌信息
 type TokenInfo interface {
 	// 获取访问令牌
@@ -18,6 +97,10 @@ type TokenInfo interface {
 	GetTokenType() string
 	// 获取令牌到期时间戳
 	GetExpiresAt() int64
+	// 获取刷新令牌
+	GetRefreshToken() string
+	// 获取刷新令牌到期时间戳
+	GetRefreshExpiresAt() int64
 	// JSON编码
 	EncodeToJSON() ([]byte, error)
 }
@@ -27,12 +110,39 @@ type Auther interface {
 	// 生成令牌
 	GenerateToken(ctx context.Context, userID string) (TokenInfo, error)
 
+	// 使用刷新令牌换取新的令牌(刷新令牌同时轮换)
+	RefreshToken(ctx context.Context, refreshToken string) (TokenInfo, error)
+
 	// 销毁令牌
 	DestroyToken(ctx context.Context, accessToken string) error
 
 	// 解析用户ID
 	ParseUserID(ctx context.Context, accessToken string) (string, error)
 
+	// 解析令牌声明
+	ParseToken(ctx context.Context, accessToken string) (*Claims, error)
+
+	// 查询用户的会话列表
+	QuerySessions(ctx context.Context, userID string) ([]*Session, error)
+
+	// 吊销用户的指定会话
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+
+	// 吊销用户的全部会话
+	RevokeAllSessions(ctx context.Context, userID string) error
+
+	// 吊销用户已签发的全部令牌
+	RevokeUser(ctx context.Context, userID string) error
+
+	// 生成挑战令牌(如两步验证)
+	GenerateChallenge(ctx context.Context, userID string, expiration time.Duration) (string, error)
+
+	// 解析挑战令牌
+	ParseChallenge(ctx context.Context, challenge string) (string, error)
+
+	// 销毁挑战令牌
+	DestroyChallenge(ctx context.Context, challenge string) error
+
 	// 释放资源
 	Release() error
 }
@@ -2,20 +2,38 @@ package jwtauth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 
 	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
 )
 
 const defaultKey = "gin-admin"
 
+// 存储键前缀
+const (
+	refreshTokenPrefix     = "refresh_token:"
+	usedRefreshTokenPrefix = "refresh_token_used:"
+	sessionPrefix          = "session:"
+)
+
+// 会话最近活动时间的更新间隔(避免每次请求都写入存储)
+const sessionTouchInterval = 60
+
 var defaultOptions = options{
-	tokenType:     "Bearer",
-	expired:       7200,
-	signingMethod: jwt.SigningMethodHS512,
-	signingKey:    []byte(defaultKey),
+	tokenType:      "Bearer",
+	expired:        7200,
+	refreshExpired: 604800,
+	signingMethod:  jwt.SigningMethodHS512,
+	signingKey:     []byte(defaultKey),
 	keyfunc: func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, auth.ErrInvalidToken
@@ -25,11 +43,13 @@ var defaultOptions = options{
 }
 
 type options struct {
-	signingMethod jwt.SigningMethod
-	signingKey    interface{}
-	keyfunc       jwt.Keyfunc
-	expired       int
-	tokenType     string
+	signingMethod  jwt.SigningMethod
+	signingKey     interface{}
+	keyfunc        jwt.Keyfunc
+	keyID          string
+	expired        int
+	refreshExpired int
+	tokenType      string
 }
 
 // Option 定义参数项
@@ -56,6 +76,23 @@ func SetKeyfunc(keyFunc jwt.Keyfunc) Option {
 	}
 }
 
+// SetKeyID 设定令牌头部的密钥ID(kid)
+func SetKeyID(keyID string) Option {
+	return func(o *options) {
+		o.keyID = keyID
+	}
+}
+
+// SetKeySet 设定签名与验证密钥集合
+func SetKeySet(ks *KeySet) Option {
+	return func(o *options) {
+		o.signingMethod = ks.SigningMethod()
+		o.signingKey = ks.SigningKey()
+		o.keyfunc = ks.Keyfunc
+		o.keyID = ks.KeyID()
+	}
+}
+
 // SetExpired 设定令牌过期时长(单位秒，默认7200)
 func SetExpired(expired int) Option {
 	return func(o *options) {
@@ -63,6 +100,13 @@ func SetExpired(expired int) Option {
 	}
 }
 
+// SetRefreshExpired 设定刷新令牌过期时长(单位秒，默认604800)
+func SetRefreshExpired(expired int) Option {
+	return func(o *options) {
+		o.refreshExpired = expired
+	}
+}
+
 // New 创建认证实例
 func New(store Storer, opts ...Option) *JWTAuth {
 	o := defaultOptions
@@ -78,21 +122,63 @@ func New(store Storer, opts ...Option) *JWTAuth {
 
 // JWTAuth jwt认证
 type JWTAuth struct {
-	opts  *options
-	store Storer
+	opts    *options
+	store   Storer
+	revoked revokedCache
+}
+
+// tokenClaims 令牌声明
+type tokenClaims struct {
+	jwt.StandardClaims
+	Scope  string `json:"scope,omitempty"`  // 权限范围
+	Tenant string `json:"tenant,omitempty"` // 所属租户
+}
+
+// refreshTokenItem 刷新令牌存储数据
+type refreshTokenItem struct {
+	Subject string `json:"sub"` // 令牌主体
+	Family  string `json:"fam"` // 令牌族(即会话ID，同一次登录轮换产生的令牌共享)
 }
 
 // GenerateToken 生成令牌
 func (a *JWTAuth) GenerateToken(ctx context.Context, userID string) (auth.TokenInfo, error) {
+	now := time.Now()
+	session := &auth.Session{
+		ID:         uuid.MustString(),
+		Subject:    userID,
+		CreatedAt:  now.Unix(),
+		LastSeenAt: now.Unix(),
+		Scope:      auth.FromScopeContext(ctx),
+		Tenant:     auth.FromTenantContext(ctx),
+	}
+	session.IP, session.UserAgent = auth.FromClientContext(ctx)
+
+	err := a.saveSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.generateToken(ctx, userID, session.ID, session.Scope, session.Tenant)
+}
+
+func (a *JWTAuth) generateToken(ctx context.Context, userID, family, scope, tenant string) (auth.TokenInfo, error) {
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(a.opts.expired) * time.Second).Unix()
 
-	token := jwt.NewWithClaims(a.opts.signingMethod, &jwt.StandardClaims{
-		IssuedAt:  now.Unix(),
-		ExpiresAt: expiresAt,
-		NotBefore: now.Unix(),
-		Subject:   userID,
+	token := jwt.NewWithClaims(a.opts.signingMethod, &tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        family,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt,
+			NotBefore: now.Unix(),
+			Subject:   userID,
+		},
+		Scope:  scope,
+		Tenant: tenant,
 	})
+	if kid := a.opts.keyID; kid != "" {
+		token.Header["kid"] = kid
+	}
 
 	tokenString, err := token.SignedString(a.opts.signingKey)
 	if err != nil {
@@ -104,17 +190,253 @@ func (a *JWTAuth) GenerateToken(ctx context.Context, userID string) (auth.TokenI
 		TokenType:   a.opts.tokenType,
 		AccessToken: tokenString,
 	}
+
+	// 如果设定了存储，则同时签发刷新令牌
+	err = a.callStore(func(store Storer) error {
+		refreshToken, err := newRefreshToken()
+		if err != nil {
+			return err
+		}
+
+		expired := time.Duration(a.opts.refreshExpired) * time.Second
+		buf, err := json.Marshal(refreshTokenItem{Subject: userID, Family: family})
+		if err != nil {
+			return err
+		}
+
+		err = store.SetValue(ctx, refreshTokenPrefix+hashRefreshToken(refreshToken), string(buf), expired)
+		if err != nil {
+			return err
+		}
+
+		tokenInfo.RefreshToken = refreshToken
+		tokenInfo.RefreshExpiresAt = now.Add(expired).Unix()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return tokenInfo, nil
 }
 
+// 生成不透明的刷新令牌
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// 存储中仅保留刷新令牌的哈希值
+func hashRefreshToken(refreshToken string) string {
+	h := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(h[:])
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌，已轮换的刷新令牌被再次使用时将吊销整个令牌族
+func (a *JWTAuth) RefreshToken(ctx context.Context, refreshToken string) (auth.TokenInfo, error) {
+	if refreshToken == "" || a.store == nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	key := hashRefreshToken(refreshToken)
+	val, ok, err := a.store.Get(ctx, refreshTokenPrefix+key)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		// 已经轮换过的刷新令牌被重复使用，视为令牌泄露
+		val, used, err := a.store.Get(ctx, usedRefreshTokenPrefix+key)
+		if err != nil {
+			return nil, err
+		} else if used {
+			var item refreshTokenItem
+			if err := json.Unmarshal([]byte(val), &item); err != nil {
+				return nil, err
+			}
+			if err := a.RevokeSession(ctx, item.Subject, item.Family); err != nil && err != auth.ErrSessionNotFound {
+				return nil, err
+			}
+		}
+		return nil, auth.ErrInvalidToken
+	}
+
+	var item refreshTokenItem
+	if err := json.Unmarshal([]byte(val), &item); err != nil {
+		return nil, err
+	}
+
+	// 删除成功的请求才能完成轮换，并发使用同一刷新令牌同样视为重复使用
+	deleted, err := a.store.Delete(ctx, refreshTokenPrefix+key)
+	if err != nil {
+		return nil, err
+	} else if !deleted {
+		if err := a.RevokeSession(ctx, item.Subject, item.Family); err != nil && err != auth.ErrSessionNotFound {
+			return nil, err
+		}
+		return nil, auth.ErrInvalidToken
+	}
+
+	expired := time.Duration(a.opts.refreshExpired) * time.Second
+	err = a.store.SetValue(ctx, usedRefreshTokenPrefix+key, val, expired)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := a.getSession(ctx, item.Subject, item.Family)
+	if err != nil {
+		return nil, err
+	} else if session == nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	session.LastSeenAt = time.Now().Unix()
+	if ip, userAgent := auth.FromClientContext(ctx); ip != "" {
+		session.IP, session.UserAgent = ip, userAgent
+	}
+	if err := a.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	// 刷新后的令牌保持会话的权限范围及所属租户
+	return a.generateToken(ctx, item.Subject, item.Family, session.Scope, session.Tenant)
+}
+
+func sessionKey(subject, sessionID string) string {
+	return sessionPrefix + subject + ":" + sessionID
+}
+
+// 保存会话(有效期与刷新令牌一致)
+func (a *JWTAuth) saveSession(ctx context.Context, session *auth.Session) error {
+	return a.callStore(func(store Storer) error {
+		expired := time.Duration(a.opts.refreshExpired) * time.Second
+		session.ExpiresAt = time.Now().Add(expired).Unix()
+
+		buf, err := json.Marshal(session)
+		if err != nil {
+			return err
+		}
+		return store.SetValue(ctx, sessionKey(session.Subject, session.ID), string(buf), expired)
+	})
+}
+
+// 获取会话(不存在时返回nil)
+func (a *JWTAuth) getSession(ctx context.Context, subject, sessionID string) (*auth.Session, error) {
+	var session *auth.Session
+	err := a.callStore(func(store Storer) error {
+		val, ok, err := store.Get(ctx, sessionKey(subject, sessionID))
+		if err != nil || !ok {
+			return err
+		}
+
+		session = new(auth.Session)
+		return json.Unmarshal([]byte(val), session)
+	})
+	return session, err
+}
+
+// 更新会话的最近活动时间
+func (a *JWTAuth) touchSession(ctx context.Context, session *auth.Session) error {
+	now := time.Now().Unix()
+	if now-session.LastSeenAt < sessionTouchInterval {
+		return nil
+	}
+
+	session.LastSeenAt = now
+	if ip, userAgent := auth.FromClientContext(ctx); ip != "" {
+		session.IP, session.UserAgent = ip, userAgent
+	}
+
+	buf, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	expired := time.Unix(session.ExpiresAt, 0).Sub(time.Now())
+	if expired <= 0 {
+		return nil
+	}
+	return a.store.SetValue(ctx, sessionKey(session.Subject, session.ID), string(buf), expired)
+}
+
+// QuerySessions 查询令牌主体的会话列表(按最近活动时间倒序)
+func (a *JWTAuth) QuerySessions(ctx context.Context, subject string) ([]*auth.Session, error) {
+	var list []*auth.Session
+	err := a.callStore(func(store Storer) error {
+		values, err := store.Scan(ctx, sessionKey(subject, ""))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().Unix()
+		for _, val := range values {
+			session := new(auth.Session)
+			if err := json.Unmarshal([]byte(val), session); err != nil {
+				return err
+			} else if session.Subject != subject || session.ExpiresAt <= now {
+				continue
+			}
+			list = append(list, session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastSeenAt > list[j].LastSeenAt
+	})
+	return list, nil
+}
+
+// RevokeSession 吊销会话(该会话下的访问令牌与刷新令牌全部失效)
+func (a *JWTAuth) RevokeSession(ctx context.Context, subject, sessionID string) error {
+	if sessionID == "" {
+		return auth.ErrSessionNotFound
+	}
+
+	return a.callStore(func(store Storer) error {
+		exists, err := store.Delete(ctx, sessionKey(subject, sessionID))
+		if err != nil {
+			return err
+		} else if !exists {
+			return auth.ErrSessionNotFound
+		}
+		return nil
+	})
+}
+
+// RevokeAllSessions 吊销令牌主体的全部会话
+func (a *JWTAuth) RevokeAllSessions(ctx context.Context, subject string) error {
+	sessions, err := a.QuerySessions(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		err := a.RevokeSession(ctx, subject, session.ID)
+		if err != nil && err != auth.ErrSessionNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
 // 解析令牌
-func (a *JWTAuth) parseToken(tokenString string) (*jwt.StandardClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, a.opts.keyfunc)
+func (a *JWTAuth) parseToken(tokenString string) (*tokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, a.opts.keyfunc)
 	if err != nil || !token.Valid {
 		return nil, auth.ErrInvalidToken
 	}
 
-	return token.Claims.(*jwt.StandardClaims), nil
+	// 挑战令牌不能作为访问令牌使用
+	claims := token.Claims.(*tokenClaims)
+	if claims.Audience != "" {
+		return nil, auth.ErrInvalidToken
+	}
+	return claims, nil
 }
 
 func (a *JWTAuth) callStore(fn func(Storer) error) error {
@@ -131,22 +453,40 @@ func (a *JWTAuth) DestroyToken(ctx context.Context, tokenString string) error {
 		return err
 	}
 
-	// 如果设定了存储，则将未过期的令牌放入
-	return a.callStore(func(store Storer) error {
+	// 如果设定了存储，则将未过期的令牌放入，同时吊销对应的会话
+	err = a.callStore(func(store Storer) error {
 		expired := time.Unix(claims.ExpiresAt, 0).Sub(time.Now())
 		return store.Set(ctx, tokenString, expired)
 	})
+	if err != nil {
+		return err
+	}
+
+	err = a.RevokeSession(ctx, claims.Subject, claims.Id)
+	if err != nil && err != auth.ErrSessionNotFound {
+		return err
+	}
+	return nil
 }
 
 // ParseUserID 解析用户ID
 func (a *JWTAuth) ParseUserID(ctx context.Context, tokenString string) (string, error) {
+	claims, err := a.ParseToken(ctx, tokenString)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// ParseToken 解析令牌声明
+func (a *JWTAuth) ParseToken(ctx context.Context, tokenString string) (*auth.Claims, error) {
 	if tokenString == "" {
-		return "", auth.ErrInvalidToken
+		return nil, auth.ErrInvalidToken
 	}
 
 	claims, err := a.parseToken(tokenString)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	err = a.callStore(func(store Storer) error {
@@ -155,13 +495,37 @@ func (a *JWTAuth) ParseUserID(ctx context.Context, tokenString string) (string,
 		} else if exists {
 			return auth.ErrInvalidToken
 		}
-		return nil
+
+		// 修改密码、停用或删除用户之前签发的令牌失效
+		if notBefore, err := a.getNotBefore(ctx, store, claims.Subject); err != nil {
+			return err
+		} else if claims.IssuedAt < notBefore {
+			return auth.ErrInvalidToken
+		}
+
+		if claims.Id == "" {
+			return nil
+		}
+
+		// 会话被吊销后，该会话下的令牌立即失效
+		session, err := a.getSession(ctx, claims.Subject, claims.Id)
+		if err != nil {
+			return err
+		} else if session == nil {
+			return auth.ErrInvalidToken
+		}
+		return a.touchSession(ctx, session)
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return claims.Subject, nil
+	return &auth.Claims{
+		Subject:   claims.Subject,
+		SessionID: claims.Id,
+		Scope:     claims.Scope,
+		Tenant:    claims.Tenant,
+	}, nil
 }
 
 // Release 释放资源
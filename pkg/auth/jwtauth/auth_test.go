@@ -3,7 +3,9 @@ package jwtauth
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
 	"github.com/LyricTian/gin-admin/v8/pkg/auth/jwtauth/store/buntdb"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,3 +36,249 @@ func TestAuth(t *testing.T) {
 	assert.EqualError(t, err, "invalid token")
 	assert.Empty(t, id)
 }
+
+func TestRefreshToken(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := context.Background()
+	userID := "test"
+	token, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token.GetRefreshToken())
+
+	newToken, err := jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+	assert.Nil(t, err)
+	assert.NotEqual(t, token.GetRefreshToken(), newToken.GetRefreshToken())
+
+	id, err := jwtAuth.ParseUserID(ctx, newToken.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, userID, id)
+
+	// 重复使用已轮换的刷新令牌，整个令牌族被吊销
+	_, err = jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+	assert.EqualError(t, err, "invalid token")
+
+	_, err = jwtAuth.RefreshToken(ctx, newToken.GetRefreshToken())
+	assert.EqualError(t, err, "invalid token")
+
+	_, err = jwtAuth.ParseUserID(ctx, newToken.GetAccessToken())
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestDestroyTokenRevokesRefreshToken(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := context.Background()
+	token, err := jwtAuth.GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	err = jwtAuth.DestroyToken(ctx, token.GetAccessToken())
+	assert.Nil(t, err)
+
+	_, err = jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestSessions(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := auth.NewClientContext(context.Background(), "127.0.0.1", "test-agent")
+	userID := "1-test"
+	token1, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	token2, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	_, err = jwtAuth.GenerateToken(ctx, "1-test:other")
+	assert.Nil(t, err)
+
+	sessions, err := jwtAuth.QuerySessions(ctx, userID)
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "127.0.0.1", sessions[0].IP)
+	assert.Equal(t, "test-agent", sessions[0].UserAgent)
+
+	// 刷新令牌轮换不产生新的会话
+	token1, err = jwtAuth.RefreshToken(ctx, token1.GetRefreshToken())
+	assert.Nil(t, err)
+	sessions, err = jwtAuth.QuerySessions(ctx, userID)
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+
+	claims, err := jwtAuth.parseToken(token1.GetAccessToken())
+	assert.Nil(t, err)
+	err = jwtAuth.RevokeSession(ctx, userID, claims.Id)
+	assert.Nil(t, err)
+	err = jwtAuth.RevokeSession(ctx, userID, claims.Id)
+	assert.Equal(t, auth.ErrSessionNotFound, err)
+
+	_, err = jwtAuth.ParseUserID(ctx, token1.GetAccessToken())
+	assert.EqualError(t, err, "invalid token")
+	_, err = jwtAuth.RefreshToken(ctx, token1.GetRefreshToken())
+	assert.EqualError(t, err, "invalid token")
+
+	id, err := jwtAuth.ParseUserID(ctx, token2.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, userID, id)
+
+	err = jwtAuth.RevokeAllSessions(ctx, userID)
+	assert.Nil(t, err)
+	_, err = jwtAuth.ParseUserID(ctx, token2.GetAccessToken())
+	assert.EqualError(t, err, "invalid token")
+
+	sessions, err = jwtAuth.QuerySessions(ctx, "1-test:other")
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestRevokeUser(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := context.Background()
+	userID := "1-test"
+	token, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	otherToken, err := jwtAuth.GenerateToken(ctx, "2-other")
+	assert.Nil(t, err)
+
+	err = jwtAuth.RevokeUser(ctx, userID)
+	assert.Nil(t, err)
+
+	_, err = jwtAuth.ParseUserID(ctx, token.GetAccessToken())
+	assert.EqualError(t, err, "invalid token")
+	_, err = jwtAuth.RefreshToken(ctx, token.GetRefreshToken())
+	assert.EqualError(t, err, "invalid token")
+
+	id, err := jwtAuth.ParseUserID(ctx, otherToken.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, "2-other", id)
+
+	// 吊销之后重新登录签发的令牌有效
+	token, err = jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	id, err = jwtAuth.ParseUserID(ctx, token.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, userID, id)
+}
+
+func TestRevokedCache(t *testing.T) {
+	var c revokedCache
+	c.set("1-a", 1)
+	c.set("2-b", 2)
+
+	notBefore, ok := c.get("1-a")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), notBefore)
+
+	// 过期的标记在下一次清理时移除
+	expired := time.Now().Add(-2 * revokedCacheTTL)
+	c.items["1-a"] = revokedItem{notBefore: 1, cachedAt: expired}
+	c.sweptAt = expired
+	_, ok = c.get("1-a")
+	assert.False(t, ok)
+
+	c.set("3-c", 3)
+	assert.Len(t, c.items, 2)
+	_, ok = c.items["1-a"]
+	assert.False(t, ok)
+}
+
+func TestTokenScope(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := auth.NewScopeContext(context.Background(), auth.ScopePasswordChange)
+	token, err := jwtAuth.GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	claims, err := jwtAuth.ParseToken(context.Background(), token.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, "test", claims.Subject)
+	assert.Equal(t, auth.ScopePasswordChange, claims.Scope)
+
+	// 刷新后的令牌保持权限范围
+	token, err = jwtAuth.RefreshToken(context.Background(), token.GetRefreshToken())
+	assert.Nil(t, err)
+	claims, err = jwtAuth.ParseToken(context.Background(), token.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, auth.ScopePasswordChange, claims.Scope)
+}
+
+func TestTokenTenant(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := auth.NewTenantContext(context.Background(), "100")
+	token, err := jwtAuth.GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	claims, err := jwtAuth.ParseToken(context.Background(), token.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, "100", claims.Tenant)
+
+	// 刷新后的令牌保持所属租户
+	token, err = jwtAuth.RefreshToken(context.Background(), token.GetRefreshToken())
+	assert.Nil(t, err)
+	claims, err = jwtAuth.ParseToken(context.Background(), token.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, "100", claims.Tenant)
+}
+
+func TestChallenge(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	assert.Nil(t, err)
+
+	jwtAuth := New(store)
+
+	defer jwtAuth.Release()
+
+	ctx := context.Background()
+	userID := "1-test"
+	challenge, err := jwtAuth.GenerateChallenge(ctx, userID, time.Minute)
+	assert.Nil(t, err)
+
+	// 挑战令牌不能作为访问令牌使用
+	_, err = jwtAuth.ParseUserID(ctx, challenge)
+	assert.EqualError(t, err, "invalid token")
+
+	token, err := jwtAuth.GenerateToken(ctx, userID)
+	assert.Nil(t, err)
+	_, err = jwtAuth.ParseChallenge(ctx, token.GetAccessToken())
+	assert.EqualError(t, err, "invalid token")
+
+	subject, err := jwtAuth.ParseChallenge(ctx, challenge)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, subject)
+
+	err = jwtAuth.DestroyChallenge(ctx, challenge)
+	assert.Nil(t, err)
+	_, err = jwtAuth.ParseChallenge(ctx, challenge)
+	assert.EqualError(t, err, "invalid token")
+}
@@ -0,0 +1,86 @@
+package jwtauth
+
+import (
+	"context"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+)
+
+// 挑战令牌的受众(与访问令牌区分，不能用于访问接口)
+const challengeAudience = "challenge"
+
+// GenerateChallenge 生成挑战令牌(如两步验证)，验证通过后再签发访问令牌
+func (a *JWTAuth) GenerateChallenge(ctx context.Context, userID string, expiration time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(a.opts.signingMethod, &tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Audience:  challengeAudience,
+			Id:        uuid.MustString(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(expiration).Unix(),
+			NotBefore: now.Unix(),
+			Subject:   userID,
+		},
+	})
+	if kid := a.opts.keyID; kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token.SignedString(a.opts.signingKey)
+}
+
+func (a *JWTAuth) parseChallenge(tokenString string) (*tokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, a.opts.keyfunc)
+	if err != nil || !token.Valid {
+		return nil, auth.ErrInvalidToken
+	}
+
+	claims := token.Claims.(*tokenClaims)
+	if claims.Audience != challengeAudience {
+		return nil, auth.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ParseChallenge 解析挑战令牌，返回令牌主体
+func (a *JWTAuth) ParseChallenge(ctx context.Context, tokenString string) (string, error) {
+	claims, err := a.parseChallenge(tokenString)
+	if err != nil {
+		return "", err
+	}
+
+	err = a.callStore(func(store Storer) error {
+		if exists, err := store.Check(ctx, tokenString); err != nil {
+			return err
+		} else if exists {
+			return auth.ErrInvalidToken
+		}
+
+		if notBefore, err := a.getNotBefore(ctx, store, claims.Subject); err != nil {
+			return err
+		} else if claims.IssuedAt < notBefore {
+			return auth.ErrInvalidToken
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// DestroyChallenge 销毁挑战令牌(确保只能使用一次)
+func (a *JWTAuth) DestroyChallenge(ctx context.Context, tokenString string) error {
+	claims, err := a.parseChallenge(tokenString)
+	if err != nil {
+		return err
+	}
+
+	return a.callStore(func(store Storer) error {
+		expired := time.Unix(claims.ExpiresAt, 0).Sub(time.Now())
+		return store.Set(ctx, tokenString, expired)
+	})
+}
@@ -0,0 +1,387 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth"
+)
+
+// 定义错误
+var (
+	ErrInvalidKey     = errors.New("invalid key")
+	ErrUnsupportedKey = errors.New("unsupported key type")
+)
+
+// ParseSigningMethod 解析签名方式(HS256/HS384/HS512/RS256/RS384/RS512/ES256/ES384/ES512/EdDSA)
+func ParseSigningMethod(alg string) (jwt.SigningMethod, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported signing method: %s", alg)
+	}
+	return method, nil
+}
+
+// ParsePrivateKeyPEM 解析PEM格式私钥(PKCS1/PKCS8/SEC1)
+func ParsePrivateKeyPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// ParsePublicKeyPEM 解析PEM格式公钥(PKIX/PKCS1/证书)
+func ParsePublicKeyPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Key 验证密钥
+type Key struct {
+	ID        string            // 密钥ID(RFC 7638指纹)
+	Method    jwt.SigningMethod // 签名方式(仅验证的RSA密钥可能为空)
+	PublicKey interface{}       // 公钥
+}
+
+// KeySet 签名与验证密钥集合，签名密钥之外的公钥仅用于验证(密钥轮换)
+type KeySet struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyID      string
+	keys       map[string]*Key
+	keyList    []*Key
+}
+
+// NewKeySet 创建密钥集合，HMAC签名方式的signingKey为[]byte，其他签名方式为对应的私钥
+func NewKeySet(method jwt.SigningMethod, signingKey interface{}, verifyKeys ...interface{}) (*KeySet, error) {
+	ks := &KeySet{
+		method:     method,
+		signingKey: signingKey,
+		keys:       make(map[string]*Key),
+	}
+
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		if _, ok := signingKey.([]byte); !ok {
+			return nil, ErrInvalidKey
+		} else if len(verifyKeys) > 0 {
+			return nil, errors.New("verify keys are not supported by HMAC signing method")
+		}
+		return ks, nil
+	}
+
+	publicKey, err := publicKeyOf(signingKey)
+	if err != nil {
+		return nil, err
+	} else if !matchMethod(method, publicKey) {
+		return nil, fmt.Errorf("signing method %s does not match the private key", method.Alg())
+	}
+
+	key, err := ks.addKey(method, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	ks.keyID = key.ID
+
+	for _, publicKey := range verifyKeys {
+		if _, err := ks.addKey(nil, publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) addKey(method jwt.SigningMethod, publicKey interface{}) (*Key, error) {
+	if method == nil {
+		method = defaultMethodOf(publicKey)
+	}
+
+	kid, err := Thumbprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := ks.keys[kid]; ok {
+		return key, nil
+	}
+
+	key := &Key{
+		ID:        kid,
+		Method:    method,
+		PublicKey: publicKey,
+	}
+	ks.keys[kid] = key
+	ks.keyList = append(ks.keyList, key)
+	return key, nil
+}
+
+// SigningMethod 签名方式
+func (ks *KeySet) SigningMethod() jwt.SigningMethod {
+	return ks.method
+}
+
+// SigningKey 签名密钥
+func (ks *KeySet) SigningKey() interface{} {
+	return ks.signingKey
+}
+
+// KeyID 签名密钥ID(HMAC签名方式为空)
+func (ks *KeySet) KeyID() string {
+	return ks.keyID
+}
+
+// Keyfunc 根据令牌头部的kid查找验证密钥
+func (ks *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	if _, ok := ks.method.(*jwt.SigningMethodHMAC); ok {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, auth.ErrInvalidToken
+		}
+		return ks.signingKey, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok || !matchMethod(t.Method, key.PublicKey) {
+		return nil, auth.ErrInvalidToken
+	}
+	return key.PublicKey, nil
+}
+
+// JWKS 公钥集合(RFC 7517)，HMAC签名方式返回空集合
+func (ks *KeySet) JWKS() *JWKSet {
+	set := &JWKSet{Keys: make([]*JWK, 0, len(ks.keyList))}
+	for _, key := range ks.keyList {
+		jwk, err := NewJWK(key.PublicKey)
+		if err != nil {
+			continue
+		}
+		jwk.KeyID = key.ID
+		jwk.Use = "sig"
+		if key.Method != nil {
+			jwk.Alg = key.Method.Alg()
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func publicKeyOf(privateKey interface{}) (interface{}, error) {
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey, nil
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey, nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// 检查签名方式与公钥类型是否匹配，避免算法混淆
+func matchMethod(method jwt.SigningMethod, publicKey interface{}) bool {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		if m, ok := method.(*jwt.SigningMethodECDSA); ok {
+			return m.CurveBits == k.Curve.Params().BitSize
+		}
+	case ed25519.PublicKey:
+		_, ok := method.(*SigningMethodEd25519)
+		return ok
+	}
+	return false
+}
+
+// 仅验证的公钥无法确定RSA的哈希算法，此时不声明alg
+func defaultMethodOf(publicKey interface{}) jwt.SigningMethod {
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return jwt.SigningMethodES256
+		case 384:
+			return jwt.SigningMethodES384
+		case 521:
+			return jwt.SigningMethodES512
+		}
+	case ed25519.PublicKey:
+		return SigningMethodEdDSA
+	}
+	return nil
+}
+
+// JWK JSON Web Key(仅公钥)
+type JWK struct {
+	KeyType string `json:"kty"`           // 密钥类型
+	Use     string `json:"use,omitempty"` // 用途
+	KeyID   string `json:"kid,omitempty"` // 密钥ID
+	Alg     string `json:"alg,omitempty"` // 算法
+	N       string `json:"n,omitempty"`   // RSA模数
+	E       string `json:"e,omitempty"`   // RSA指数
+	Crv     string `json:"crv,omitempty"` // 曲线
+	X       string `json:"x,omitempty"`   // 曲线坐标x
+	Y       string `json:"y,omitempty"`   // 曲线坐标y
+}
+
+// JWKSet JSON Web Key集合
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// NewJWK 由公钥创建JWK
+func NewJWK(publicKey interface{}) (*JWK, error) {
+	enc := base64.RawURLEncoding
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			KeyType: "RSA",
+			N:       enc.EncodeToString(k.N.Bytes()),
+			E:       enc.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		params := k.Curve.Params()
+		size := (params.BitSize + 7) / 8
+		return &JWK{
+			KeyType: "EC",
+			Crv:     params.Name,
+			X:       enc.EncodeToString(padBytes(k.X.Bytes(), size)),
+			Y:       enc.EncodeToString(padBytes(k.Y.Bytes(), size)),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{
+			KeyType: "OKP",
+			Crv:     "Ed25519",
+			X:       enc.EncodeToString(k),
+		}, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// PublicKey 由JWK解析公钥
+func (k *JWK) PublicKey() (interface{}, error) {
+	enc := base64.RawURLEncoding
+	switch k.KeyType {
+	case "RSA":
+		n, err := enc.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := enc.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrUnsupportedKey
+		}
+		x, err := enc.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := enc.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, ErrUnsupportedKey
+		}
+		x, err := enc.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		} else if len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	buf := make([]byte, size)
+	copy(buf[size-len(b):], b)
+	return buf
+}
+
+// Thumbprint 计算公钥的JWK指纹(RFC 7638)，用作密钥ID
+func Thumbprint(publicKey interface{}) (string, error) {
+	jwk, err := NewJWK(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	// 成员按字典序排列且不含空白
+	var s string
+	switch jwk.KeyType {
+	case "RSA":
+		s = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "EC":
+		s = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, jwk.Crv, jwk.X, jwk.Y)
+	case "OKP":
+		s = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+
+	h := sha256.Sum256([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(h[:]), nil
+}
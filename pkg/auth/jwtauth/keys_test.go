@@ -0,0 +1,127 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeySetSigningMethods(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	cases := []struct {
+		method jwt.SigningMethod
+		key    interface{}
+	}{
+		{jwt.SigningMethodRS256, rsaKey},
+		{jwt.SigningMethodES256, ecKey},
+		{SigningMethodEdDSA, edKey},
+	}
+
+	ctx := context.Background()
+	for _, c := range cases {
+		ks, err := NewKeySet(c.method, c.key)
+		assert.Nil(t, err)
+
+		jwtAuth := New(nil, SetKeySet(ks))
+		token, err := jwtAuth.GenerateToken(ctx, "test")
+		assert.Nil(t, err)
+
+		id, err := jwtAuth.ParseUserID(ctx, token.GetAccessToken())
+		assert.Nil(t, err, c.method.Alg())
+		assert.Equal(t, "test", id)
+
+		jwks := ks.JWKS()
+		assert.Len(t, jwks.Keys, 1)
+		assert.Equal(t, ks.KeyID(), jwks.Keys[0].KeyID)
+		assert.Equal(t, c.method.Alg(), jwks.Keys[0].Alg)
+
+		publicKey, err := jwks.Keys[0].PublicKey()
+		assert.Nil(t, err)
+		kid, err := Thumbprint(publicKey)
+		assert.Nil(t, err)
+		assert.Equal(t, ks.KeyID(), kid)
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	oldKS, err := NewKeySet(jwt.SigningMethodES256, oldKey)
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	token, err := New(nil, SetKeySet(oldKS)).GenerateToken(ctx, "test")
+	assert.Nil(t, err)
+
+	// 新密钥签名，旧公钥保留用于验证
+	newKS, err := NewKeySet(jwt.SigningMethodES256, newKey, &oldKey.PublicKey)
+	assert.Nil(t, err)
+	assert.NotEqual(t, oldKS.KeyID(), newKS.KeyID())
+	assert.Len(t, newKS.JWKS().Keys, 2)
+
+	id, err := New(nil, SetKeySet(newKS)).ParseUserID(ctx, token.GetAccessToken())
+	assert.Nil(t, err)
+	assert.Equal(t, "test", id)
+
+	// 旧公钥移除后，旧令牌失效
+	rotatedKS, err := NewKeySet(jwt.SigningMethodES256, newKey)
+	assert.Nil(t, err)
+	_, err = New(nil, SetKeySet(rotatedKS)).ParseUserID(ctx, token.GetAccessToken())
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestKeySetRejectsHMACWithPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	ks, err := NewKeySet(jwt.SigningMethodRS256, rsaKey)
+	assert.Nil(t, err)
+
+	// 使用公钥作为HMAC密钥伪造令牌
+	pubBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey),
+	})
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "test"})
+	forged.Header["kid"] = ks.KeyID()
+	tokenString, err := forged.SignedString(pubBytes)
+	assert.Nil(t, err)
+
+	_, err = New(nil, SetKeySet(ks)).ParseUserID(context.Background(), tokenString)
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestParseKeyPEM(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	privBytes, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.Nil(t, err)
+	privateKey, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}))
+	assert.Nil(t, err)
+	assert.Equal(t, edKey, privateKey)
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(edKey.Public())
+	assert.Nil(t, err)
+	publicKey, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}))
+	assert.Nil(t, err)
+	assert.Equal(t, edKey.Public(), publicKey)
+}
@@ -0,0 +1,100 @@
+package jwtauth
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 用户令牌吊销标记的存储键前缀
+const revokedUserPrefix = "revoked_user:"
+
+// 吊销标记的本地缓存时间，跨实例的即时失效由会话吊销保证
+const revokedCacheTTL = 10 * time.Second
+
+type revokedItem struct {
+	notBefore int64
+	cachedAt  time.Time
+}
+
+// 用户令牌吊销标记的本地缓存
+type revokedCache struct {
+	sync.RWMutex
+	items   map[string]revokedItem
+	sweptAt time.Time
+}
+
+func (c *revokedCache) get(subject string) (int64, bool) {
+	c.RLock()
+	item, ok := c.items[subject]
+	c.RUnlock()
+	if !ok || time.Since(item.cachedAt) > revokedCacheTTL {
+		return 0, false
+	}
+	return item.notBefore, true
+}
+
+func (c *revokedCache) set(subject string, notBefore int64) {
+	now := time.Now()
+	c.Lock()
+	if c.items == nil {
+		c.items = make(map[string]revokedItem)
+	}
+
+	// 每个缓存周期清理一次过期的标记，缓存数量不超过两个周期内访问的令牌主体数
+	if now.Sub(c.sweptAt) > revokedCacheTTL {
+		for key, item := range c.items {
+			if now.Sub(item.cachedAt) > revokedCacheTTL {
+				delete(c.items, key)
+			}
+		}
+		c.sweptAt = now
+	}
+
+	c.items[subject] = revokedItem{notBefore: notBefore, cachedAt: now}
+	c.Unlock()
+}
+
+// RevokeUser 吊销令牌主体已签发的全部令牌(用于修改密码、停用或删除用户)
+func (a *JWTAuth) RevokeUser(ctx context.Context, subject string) error {
+	err := a.callStore(func(store Storer) error {
+		expired := a.opts.expired
+		if a.opts.refreshExpired > expired {
+			expired = a.opts.refreshExpired
+		}
+
+		notBefore := time.Now().Unix()
+		err := store.SetValue(ctx, revokedUserPrefix+subject, strconv.FormatInt(notBefore, 10), time.Duration(expired)*time.Second)
+		if err != nil {
+			return err
+		}
+		a.revoked.set(subject, notBefore)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 同一秒内签发的令牌无法通过签发时间区分，由吊销会话保证失效
+	return a.RevokeAllSessions(ctx, subject)
+}
+
+// 获取令牌主体的吊销时间(不存在时返回0)
+func (a *JWTAuth) getNotBefore(ctx context.Context, store Storer, subject string) (int64, error) {
+	if notBefore, ok := a.revoked.get(subject); ok {
+		return notBefore, nil
+	}
+
+	val, ok, err := store.Get(ctx, revokedUserPrefix+subject)
+	if err != nil {
+		return 0, err
+	}
+
+	var notBefore int64
+	if ok {
+		notBefore, _ = strconv.ParseInt(val, 10, 64)
+	}
+	a.revoked.set(subject, notBefore)
+	return notBefore, nil
+}
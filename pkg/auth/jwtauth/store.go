@@ -11,6 +11,14 @@ type Storer interface {
 	Set(ctx context.Context, tokenString string, expiration time.Duration) error
 	// 检查令牌是否存在
 	Check(ctx context.Context, tokenString string) (bool, error)
+	// 存储键值数据，并指定到期时间
+	SetValue(ctx context.Context, key, value string, expiration time.Duration) error
+	// 获取键值数据
+	Get(ctx context.Context, key string) (string, bool, error)
+	// 删除键(返回键是否存在)
+	Delete(ctx context.Context, key string) (bool, error)
+	// 获取指定前缀的全部键值数据
+	Scan(ctx context.Context, prefix string) ([]string, error)
 	// 关闭存储
 	Close() error
 }
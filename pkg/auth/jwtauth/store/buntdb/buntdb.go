@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/tidwall/buntdb"
@@ -32,25 +33,71 @@ type Store struct {
 
 // Set ...
 func (a *Store) Set(ctx context.Context, tokenString string, expiration time.Duration) error {
+	return a.SetValue(ctx, tokenString, "1", expiration)
+}
+
+// SetValue 存储键值数据
+func (a *Store) SetValue(ctx context.Context, key, value string, expiration time.Duration) error {
 	return a.db.Update(func(tx *buntdb.Tx) error {
 		var opts *buntdb.SetOptions
 		if expiration > 0 {
 			opts = &buntdb.SetOptions{Expires: true, TTL: expiration}
 		}
-		_, _, err := tx.Set(tokenString, "1", opts)
+		_, _, err := tx.Set(key, value, opts)
 		return err
 	})
 }
 
+// Get 获取键值数据
+func (a *Store) Get(ctx context.Context, key string) (string, bool, error) {
+	var (
+		value  string
+		exists bool
+	)
+	err := a.db.View(func(tx *buntdb.Tx) error {
+		val, err := tx.Get(key)
+		if err != nil {
+			if err == buntdb.ErrNotFound {
+				return nil
+			}
+			return err
+		}
+		value, exists = val, true
+		return nil
+	})
+	return value, exists, err
+}
+
 // Delete 删除键
-func (a *Store) Delete(ctx context.Context, tokenString string) error {
-	return a.db.Update(func(tx *buntdb.Tx) error {
-		_, err := tx.Delete(tokenString)
-		if err != nil && err != buntdb.ErrNotFound {
+func (a *Store) Delete(ctx context.Context, key string) (bool, error) {
+	var exists bool
+	err := a.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(key)
+		if err != nil {
+			if err == buntdb.ErrNotFound {
+				return nil
+			}
 			return err
 		}
+		exists = true
 		return nil
 	})
+	return exists, err
+}
+
+// Scan 获取指定前缀的全部键值数据
+func (a *Store) Scan(ctx context.Context, prefix string) ([]string, error) {
+	var values []string
+	err := a.db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendGreaterOrEqual("", prefix, func(key, value string) bool {
+			if !strings.HasPrefix(key, prefix) {
+				return false
+			}
+			values = append(values, value)
+			return true
+		})
+	})
+	return values, err
 }
 
 // Check ...
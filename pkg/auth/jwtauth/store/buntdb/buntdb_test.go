@@ -22,6 +22,61 @@ func TestStore(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, true, b)
 
-	err = store.Delete(ctx, key)
+	b, err = store.Delete(ctx, key)
 	assert.Nil(t, err)
+	assert.Equal(t, true, b)
+
+	b, err = store.Check(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, false, b)
+}
+
+func TestStoreValue(t *testing.T) {
+	store, err := NewStore(":memory:")
+	assert.Nil(t, err)
+
+	defer store.Close()
+
+	key := "test"
+	ctx := context.Background()
+	err = store.SetValue(ctx, key, "value", 0)
+	assert.Nil(t, err)
+
+	val, ok, err := store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "value", val)
+
+	b, err := store.Delete(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, true, b)
+
+	_, ok, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, false, ok)
+
+	b, err = store.Delete(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, false, b)
+}
+
+func TestStoreScan(t *testing.T) {
+	store, err := NewStore(":memory:")
+	assert.Nil(t, err)
+
+	defer store.Close()
+
+	ctx := context.Background()
+	for _, key := range []string{"a:1", "a:2", "a*:3", "b:1"} {
+		err = store.SetValue(ctx, key, key, 0)
+		assert.Nil(t, err)
+	}
+
+	values, err := store.Scan(ctx, "a:")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"a:1", "a:2"}, values)
+
+	values, err = store.Scan(ctx, "c:")
+	assert.Nil(t, err)
+	assert.Empty(t, values)
 }
@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -52,6 +54,7 @@ type redisClienter interface {
 	Exists(keys ...string) *redis.IntCmd
 	TxPipeline() redis.Pipeliner
 	Del(keys ...string) *redis.IntCmd
+	Scan(cursor uint64, match string, count int64) *redis.ScanCmd
 	Close() error
 }
 
@@ -67,19 +70,100 @@ func (s *Store) wrapperKey(key string) string {
 
 // Set ...
 func (s *Store) Set(ctx context.Context, tokenString string, expiration time.Duration) error {
-	cmd := s.cli.Set(s.wrapperKey(tokenString), "1", expiration)
+	return s.SetValue(ctx, tokenString, "1", expiration)
+}
+
+// SetValue ...
+func (s *Store) SetValue(ctx context.Context, key, value string, expiration time.Duration) error {
+	cmd := s.cli.Set(s.wrapperKey(key), value, expiration)
 	return cmd.Err()
 }
 
+// Get ...
+func (s *Store) Get(ctx context.Context, key string) (string, bool, error) {
+	cmd := s.cli.Get(s.wrapperKey(key))
+	if err := cmd.Err(); err != nil {
+		if err == redis.Nil {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return cmd.Val(), true, nil
+}
+
 // Delete ...
-func (s *Store) Delete(ctx context.Context, tokenString string) (bool, error) {
-	cmd := s.cli.Del(s.wrapperKey(tokenString))
+func (s *Store) Delete(ctx context.Context, key string) (bool, error) {
+	cmd := s.cli.Del(s.wrapperKey(key))
 	if err := cmd.Err(); err != nil {
 		return false, err
 	}
 	return cmd.Val() > 0, nil
 }
 
+// Scan 获取指定前缀的全部键值数据
+func (s *Store) Scan(ctx context.Context, prefix string) ([]string, error) {
+	var (
+		mu     sync.Mutex
+		values []string
+	)
+	scan := func(cli redisClienter) error {
+		list, err := s.scan(cli, prefix)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		values = append(values, list...)
+		mu.Unlock()
+		return nil
+	}
+
+	// 集群模式需要遍历所有主节点
+	if cc, ok := s.cli.(*redis.ClusterClient); ok {
+		err := cc.ForEachMaster(func(cli *redis.Client) error {
+			return scan(cli)
+		})
+		return values, err
+	}
+	return values, scan(s.cli)
+}
+
+var globReplacer = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+func (s *Store) scan(cli redisClienter, prefix string) ([]string, error) {
+	key := s.wrapperKey(prefix)
+	match := globReplacer.Replace(key) + "*"
+
+	var (
+		cursor uint64
+		values []string
+	)
+	for {
+		keys, next, err := cli.Scan(cursor, match, 100).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, k := range keys {
+			if !strings.HasPrefix(k, key) {
+				continue
+			}
+			val, err := cli.Get(k).Result()
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			values = append(values, val)
+		}
+
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	return values, nil
+}
+
 // Check ...
 func (s *Store) Check(ctx context.Context, tokenString string) (bool, error) {
 	cmd := s.cli.Exists(s.wrapperKey(tokenString))
@@ -33,3 +33,57 @@ func TestStore(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, true, b)
 }
+
+func TestStoreValue(t *testing.T) {
+	store := NewStore(&Config{
+		Addr:      addr,
+		DB:        1,
+		KeyPrefix: "prefix",
+	})
+
+	defer store.Close()
+
+	key := "test"
+	ctx := context.Background()
+	err := store.SetValue(ctx, key, "value", 0)
+	assert.Nil(t, err)
+
+	val, ok, err := store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "value", val)
+
+	b, err := store.Delete(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, true, b)
+
+	_, ok, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, false, ok)
+}
+
+func TestStoreScan(t *testing.T) {
+	store := NewStore(&Config{
+		Addr:      addr,
+		DB:        1,
+		KeyPrefix: "prefix",
+	})
+
+	defer store.Close()
+
+	ctx := context.Background()
+	keys := []string{"scan:a:1", "scan:a:2", "scan:a*:3"}
+	for _, key := range keys {
+		err := store.SetValue(ctx, key, key, 0)
+		assert.Nil(t, err)
+	}
+
+	values, err := store.Scan(ctx, "scan:a:")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"scan:a:1", "scan:a:2"}, values)
+
+	for _, key := range keys {
+		_, err := store.Delete(ctx, key)
+		assert.Nil(t, err)
+	}
+}
@@ -0,0 +1,251 @@
+package ldapauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// 定义错误
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Config 配置参数
+type Config struct {
+	URL                string        // 服务地址(ldap://host:389 或 ldaps://host:636)
+	StartTLS           bool          // 是否使用StartTLS
+	InsecureSkipVerify bool          // 是否跳过证书校验
+	Timeout            time.Duration // 连接与请求超时时间
+	BindDN             string        // 查询用户使用的账号(为空时匿名查询)
+	BindPassword       string        // 查询用户使用的密码
+	BaseDN             string        // 用户查询的根节点
+	UserFilter         string        // 用户查询条件(%s替换为转义后的用户名)
+	IDAttr             string        // 用户唯一标识属性(为空时使用DN)
+	UserNameAttr       string        // 用户名属性
+	RealNameAttr       string        // 姓名属性
+	EmailAttr          string        // 邮箱属性
+	PhoneAttr          string        // 手机号属性
+	GroupAttr          string        // 用户条目中的用户组属性(如memberOf，值为用户组DN)
+	GroupBaseDN        string        // 用户组查询的根节点(为空时使用BaseDN)
+	GroupFilter        string        // 用户组查询条件(%s替换为转义后的用户DN，设置后优先于GroupAttr)
+	GroupNameAttr      string        // 用户组名称属性
+}
+
+// Entry 认证通过的用户信息
+type Entry struct {
+	ID       string   // 唯一标识
+	DN       string   // 用户DN
+	UserName string   // 用户名
+	RealName string   // 姓名
+	Email    string   // 邮箱
+	Phone    string   // 手机号
+	Groups   []string // 用户组名称
+}
+
+// New 创建LDAP认证客户端
+func New(cfg Config) *Client {
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(uid=%s)"
+	}
+	if cfg.UserNameAttr == "" {
+		cfg.UserNameAttr = "uid"
+	}
+	if cfg.RealNameAttr == "" {
+		cfg.RealNameAttr = "cn"
+	}
+	if cfg.EmailAttr == "" {
+		cfg.EmailAttr = "mail"
+	}
+	if cfg.GroupNameAttr == "" {
+		cfg.GroupNameAttr = "cn"
+	}
+	if cfg.GroupBaseDN == "" {
+		cfg.GroupBaseDN = cfg.BaseDN
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &Client{cfg: cfg}
+}
+
+// Client LDAP认证客户端(每次认证使用独立的连接)
+type Client struct {
+	cfg Config
+}
+
+func (c *Client) dial(ctx context.Context) (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.cfg.Timeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.cfg.InsecureSkipVerify}
+	conn, err := ldap.DialURL(c.cfg.URL, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(c.cfg.Timeout)
+
+	if c.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// 使用查询账号绑定(未配置时保持匿名)
+func (c *Client) bindService(conn *ldap.Conn) error {
+	if c.cfg.BindDN == "" {
+		return nil
+	}
+	return conn.Bind(c.cfg.BindDN, c.cfg.BindPassword)
+}
+
+// Authenticate 查询用户并使用其DN与密码绑定，成功后返回用户信息
+func (c *Client) Authenticate(ctx context.Context, userName, password string) (*Entry, error) {
+	// 空密码会被服务端视为匿名绑定而直接成功，必须拒绝
+	if userName == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = c.bindService(conn)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: bind service account: %w", err)
+	}
+
+	attrs := []string{c.cfg.UserNameAttr, c.cfg.RealNameAttr, c.cfg.EmailAttr}
+	for _, attr := range []string{c.cfg.IDAttr, c.cfg.PhoneAttr, c.cfg.GroupAttr} {
+		if attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(c.cfg.UserFilter, ldap.EscapeFilter(userName)),
+		attrs, nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	} else if len(result.Entries) == 0 {
+		return nil, ErrUserNotFound
+	} else if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("ldap: multiple entries found for user %s", userName)
+	}
+
+	item := result.Entries[0]
+	err = conn.Bind(item.DN, password)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	entry := &Entry{
+		ID:       item.DN,
+		DN:       item.DN,
+		UserName: item.GetEqualFoldAttributeValue(c.cfg.UserNameAttr),
+		RealName: item.GetEqualFoldAttributeValue(c.cfg.RealNameAttr),
+		Email:    item.GetEqualFoldAttributeValue(c.cfg.EmailAttr),
+	}
+	if entry.UserName == "" {
+		entry.UserName = userName
+	}
+	if c.cfg.IDAttr != "" {
+		// objectGUID等二进制属性使用十六进制表示
+		if v := item.GetEqualFoldRawAttributeValue(c.cfg.IDAttr); len(v) > 0 {
+			entry.ID = formatID(v)
+		}
+	}
+	if c.cfg.PhoneAttr != "" {
+		entry.Phone = item.GetEqualFoldAttributeValue(c.cfg.PhoneAttr)
+	}
+
+	if c.cfg.GroupFilter != "" {
+		// 用户绑定后可能没有查询用户组的权限，切换回查询账号
+		err = c.bindService(conn)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: bind service account: %w", err)
+		}
+
+		entry.Groups, err = c.searchGroups(conn, item.DN)
+		if err != nil {
+			return nil, err
+		}
+	} else if c.cfg.GroupAttr != "" {
+		for _, dn := range item.GetEqualFoldAttributeValues(c.cfg.GroupAttr) {
+			if name := groupName(dn, c.cfg.GroupNameAttr); name != "" {
+				entry.Groups = append(entry.Groups, name)
+			}
+		}
+	}
+
+	return entry, nil
+}
+
+func (c *Client) searchGroups(conn *ldap.Conn, userDN string) ([]string, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(c.cfg.GroupFilter, ldap.EscapeFilter(userDN)),
+		[]string{c.cfg.GroupNameAttr}, nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, item := range result.Entries {
+		if name := item.GetEqualFoldAttributeValue(c.cfg.GroupNameAttr); name != "" {
+			groups = append(groups, name)
+		}
+	}
+	return groups, nil
+}
+
+// 从用户组DN中取出名称属性的值(如 cn=admins,ou=groups,dc=example,dc=com 取 admins)
+func groupName(dn, attr string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+
+	for _, item := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(item.Type, attr) {
+			return item.Value
+		}
+	}
+	return ""
+}
+
+func formatID(v []byte) string {
+	for _, b := range v {
+		if b < 0x20 || b > 0x7e {
+			return hex.EncodeToString(v)
+		}
+	}
+	return string(v)
+}
@@ -0,0 +1,111 @@
+package ldapauth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/ldapauth"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/ldapauth/ldaptest"
+)
+
+func newServer() *ldaptest.Server {
+	s := ldaptest.NewServer()
+	s.AddEntry(ldaptest.Entry{
+		DN:       "cn=admin,dc=example,dc=com",
+		Password: "admin",
+	})
+	s.AddEntry(ldaptest.Entry{
+		DN:       "uid=tom,ou=people,dc=example,dc=com",
+		Password: "secret",
+		Attributes: map[string][]string{
+			"objectClass": {"inetOrgPerson"},
+			"uid":         {"tom"},
+			"cn":          {"Tom"},
+			"mail":        {"tom@example.com"},
+			"memberOf":    {"cn=admins,ou=groups,dc=example,dc=com"},
+		},
+	})
+	s.AddEntry(ldaptest.Entry{
+		DN: "cn=dev,ou=groups,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"cn":          {"dev"},
+			"member":      {"uid=tom,ou=people,dc=example,dc=com"},
+		},
+	})
+	return s
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	c := ldapauth.New(ldapauth.Config{
+		URL:          s.URL(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		BaseDN:       "dc=example,dc=com",
+		UserFilter:   "(&(objectClass=inetOrgPerson)(uid=%s))",
+		GroupAttr:    "memberOf",
+	})
+
+	ctx := context.Background()
+	entry, err := c.Authenticate(ctx, "tom", "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, "uid=tom,ou=people,dc=example,dc=com", entry.DN)
+	assert.Equal(t, entry.DN, entry.ID)
+	assert.Equal(t, "tom", entry.UserName)
+	assert.Equal(t, "Tom", entry.RealName)
+	assert.Equal(t, "tom@example.com", entry.Email)
+	assert.Equal(t, []string{"admins"}, entry.Groups)
+
+	_, err = c.Authenticate(ctx, "tom", "wrong")
+	assert.Equal(t, ldapauth.ErrInvalidCredentials, err)
+
+	// 空密码(匿名绑定)必须拒绝
+	_, err = c.Authenticate(ctx, "tom", "")
+	assert.Equal(t, ldapauth.ErrInvalidCredentials, err)
+
+	_, err = c.Authenticate(ctx, "jerry", "secret")
+	assert.Equal(t, ldapauth.ErrUserNotFound, err)
+
+	// 查询条件中的特殊字符需要转义
+	_, err = c.Authenticate(ctx, "*", "secret")
+	assert.Equal(t, ldapauth.ErrUserNotFound, err)
+}
+
+func TestAuthenticateGroupFilter(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	c := ldapauth.New(ldapauth.Config{
+		URL:          s.URL(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin",
+		BaseDN:       "dc=example,dc=com",
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+		GroupFilter:  "(&(objectClass=groupOfNames)(member=%s))",
+	})
+
+	entry, err := c.Authenticate(context.Background(), "tom", "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dev"}, entry.Groups)
+}
+
+func TestAuthenticateServiceAccount(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	// 查询账号密码错误
+	c := ldapauth.New(ldapauth.Config{
+		URL:          s.URL(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "wrong",
+		BaseDN:       "dc=example,dc=com",
+	})
+	_, err := c.Authenticate(context.Background(), "tom", "secret")
+	assert.NotNil(t, err)
+	assert.NotEqual(t, ldapauth.ErrInvalidCredentials, err)
+}
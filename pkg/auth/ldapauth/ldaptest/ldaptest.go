@@ -0,0 +1,346 @@
+// Package ldaptest 提供用于测试的进程内LDAP服务(仅支持简单绑定与查询)
+package ldaptest
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Entry 目录条目
+type Entry struct {
+	DN         string              // 条目DN
+	Password   string              // 绑定密码(为空时不允许以该条目绑定)
+	Attributes map[string][]string // 属性
+}
+
+type entry struct {
+	*Entry
+	dn *ldap.DN
+}
+
+// Server 模拟的LDAP服务，匿名连接不允许查询
+type Server struct {
+	ln      net.Listener
+	mu      sync.RWMutex
+	entries []*entry
+	conns   map[net.Conn]struct{}
+	wg      sync.WaitGroup
+}
+
+// NewServer 创建并启动LDAP服务
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		ln:    ln,
+		conns: make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// URL 服务地址
+func (s *Server) URL() string {
+	return "ldap://" + s.ln.Addr().String()
+}
+
+// AddEntry 添加目录条目
+func (s *Server) AddEntry(item Entry) {
+	dn, err := ldap.ParseDN(item.DN)
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	s.entries = append(s.entries, &entry{Entry: &item, dn: dn})
+	s.mu.Unlock()
+}
+
+// Close 关闭服务及所有连接
+func (s *Server) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	bound := false
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := s.bind(op)
+			bound = code == ldap.LDAPResultSuccess && len(op.Children) > 2 && op.Children[2].Data.Len() > 0
+			responses = append(responses, newResult(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationSearchRequest:
+			if !bound {
+				responses = append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights))
+				break
+			}
+			responses = append(s.search(op), newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+		case ldap.ApplicationExtendedRequest:
+			responses = append(responses, newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultUnwillingToPerform))
+		default:
+			return
+		}
+
+		for _, res := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+			envelope.AppendChild(res)
+			if _, err := conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func newResult(tag ber.Tag, code uint16) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, ldap.LDAPResultCodeMap[code], "Diagnostic Message"))
+	return res
+}
+
+// 简单绑定，空密码按RFC 4513视为匿名绑定并返回成功
+func (s *Server) bind(op *ber.Packet) uint16 {
+	if len(op.Children) < 3 || op.Children[2].Tag != 0 {
+		return ldap.LDAPResultProtocolError
+	}
+
+	name := op.Children[1].Data.String()
+	password := op.Children[2].Data.String()
+	if password == "" {
+		return ldap.LDAPResultSuccess
+	}
+
+	dn, err := ldap.ParseDN(name)
+	if err != nil {
+		return ldap.LDAPResultInvalidCredentials
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, item := range s.entries {
+		if item.dn.EqualFold(dn) && item.Password != "" && item.Password == password {
+			return ldap.LDAPResultSuccess
+		}
+	}
+	return ldap.LDAPResultInvalidCredentials
+}
+
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return nil
+	}
+
+	baseDN, err := ldap.ParseDN(op.Children[0].Data.String())
+	if err != nil {
+		return nil
+	}
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+
+	var attrs []string
+	for _, child := range op.Children[7].Children {
+		attrs = append(attrs, child.Data.String())
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var list []*ber.Packet
+	for _, item := range s.entries {
+		if !inScope(baseDN, item.dn, scope) || !match(item.Entry, filter) {
+			continue
+		}
+
+		list = append(list, newSearchEntry(item.Entry, attrs))
+		if sizeLimit > 0 && int64(len(list)) >= sizeLimit {
+			break
+		}
+	}
+	return list
+}
+
+func inScope(base, dn *ldap.DN, scope int64) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return base.EqualFold(dn)
+	case ldap.ScopeSingleLevel:
+		return len(dn.RDNs) == len(base.RDNs)+1 && base.AncestorOfFold(dn)
+	}
+	return base.EqualFold(dn) || base.AncestorOfFold(dn)
+}
+
+func getValues(item *Entry, attr string) []string {
+	for k, v := range item.Attributes {
+		if strings.EqualFold(k, attr) {
+			return v
+		}
+	}
+	return nil
+}
+
+// 计算查询条件(支持与、或、非、等值、存在与子串匹配，忽略大小写)
+func match(item *Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !match(item, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if match(item, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !match(item, filter.Children[0])
+	case ldap.FilterPresent:
+		return len(getValues(item, filter.Data.String())) > 0
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		value := filter.Children[1].Data.String()
+		for _, v := range getValues(item, filter.Children[0].Data.String()) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterSubstrings:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		for _, v := range getValues(item, filter.Children[0].Data.String()) {
+			if matchSubstrings(strings.ToLower(v), filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func matchSubstrings(value string, parts []*ber.Packet) bool {
+	for i, part := range parts {
+		s := strings.ToLower(part.Data.String())
+		switch part.Tag {
+		case 0: // initial
+			if i != 0 || !strings.HasPrefix(value, s) {
+				return false
+			}
+			value = value[len(s):]
+		case 1: // any
+			idx := strings.Index(value, s)
+			if idx < 0 {
+				return false
+			}
+			value = value[idx+len(s):]
+		case 2: // final
+			if !strings.HasSuffix(value, s) {
+				return false
+			}
+			value = ""
+		}
+	}
+	return true
+}
+
+func newSearchEntry(item *Entry, attrs []string) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, item.DN, "Object Name"))
+
+	selectAll := len(attrs) == 0
+	for _, attr := range attrs {
+		if attr == "*" {
+			selectAll = true
+		}
+	}
+
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range item.Attributes {
+		if !selectAll && !containsFold(attrs, name) {
+			continue
+		}
+
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(set)
+		list.AppendChild(attr)
+	}
+	res.AppendChild(list)
+	return res
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 定义错误
+var (
+	ErrLocked = errors.New("locked")
+)
+
+// 存储键前缀
+const (
+	failurePrefix = "failure:"
+	lockPrefix    = "lock:"
+	delayPrefix   = "delay:"
+)
+
+// Store 计数存储接口
+type Store interface {
+	// 增加计数(首次计数时设定过期时间)
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// 获取计数
+	Get(ctx context.Context, key string) (int64, error)
+	// 设定标记
+	Set(ctx context.Context, key string, expiration time.Duration) error
+	// 获取标记的剩余有效期(不存在时返回0)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// 删除键
+	Delete(ctx context.Context, keys ...string) error
+	// 关闭存储
+	Close() error
+}
+
+// Policy 锁定策略
+type Policy struct {
+	MaxFailures  int           // 连续失败次数达到后锁定(0表示不锁定)
+	Window       time.Duration // 失败计数的统计窗口
+	LockDuration time.Duration // 锁定时长
+	BaseDelay    time.Duration // 失败后的等待时间基数(每次失败翻倍)
+	MaxDelay     time.Duration // 最大等待时间
+}
+
+// Status 锁定状态
+type Status struct {
+	Failures   int64         // 当前窗口内的失败次数
+	Locked     bool          // 是否已锁定
+	RetryAfter time.Duration // 距离允许再次尝试的时间
+}
+
+// New 创建锁定器
+func New(store Store) *Lockout {
+	return &Lockout{store: store}
+}
+
+// Lockout 失败计数与锁定
+type Lockout struct {
+	store Store
+}
+
+// Check 检查是否允许尝试，锁定或等待期间返回ErrLocked以及剩余时间
+func (a *Lockout) Check(ctx context.Context, key string) (time.Duration, error) {
+	for _, prefix := range []string{lockPrefix, delayPrefix} {
+		ttl, err := a.store.TTL(ctx, prefix+key)
+		if err != nil {
+			return 0, err
+		} else if ttl > 0 {
+			return ttl, ErrLocked
+		}
+	}
+	return 0, nil
+}
+
+// Fail 记录一次失败，达到最大失败次数后锁定，否则按失败次数递增等待时间
+func (a *Lockout) Fail(ctx context.Context, key string, p Policy) (*Status, error) {
+	n, err := a.store.Incr(ctx, failurePrefix+key, p.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.MaxFailures > 0 && n >= int64(p.MaxFailures) {
+		err := a.store.Set(ctx, lockPrefix+key, p.LockDuration)
+		if err != nil {
+			return nil, err
+		}
+
+		// 锁定解除后重新计数
+		err = a.store.Delete(ctx, failurePrefix+key, delayPrefix+key)
+		if err != nil {
+			return nil, err
+		}
+		return &Status{Failures: n, Locked: true, RetryAfter: p.LockDuration}, nil
+	}
+
+	delay := progressiveDelay(p, n)
+	if delay > 0 {
+		err := a.store.Set(ctx, delayPrefix+key, delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Status{Failures: n, RetryAfter: delay}, nil
+}
+
+func progressiveDelay(p Policy, n int64) time.Duration {
+	if p.BaseDelay <= 0 || n <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := int64(1); i < n; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// Reset 清除失败计数与锁定
+func (a *Lockout) Reset(ctx context.Context, key string) error {
+	return a.store.Delete(ctx, failurePrefix+key, lockPrefix+key, delayPrefix+key)
+}
+
+// Status 获取锁定状态
+func (a *Lockout) Status(ctx context.Context, key string) (*Status, error) {
+	n, err := a.store.Get(ctx, failurePrefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Failures: n}
+	ttl, err := a.store.TTL(ctx, lockPrefix+key)
+	if err != nil {
+		return nil, err
+	} else if ttl > 0 {
+		status.Locked = true
+		status.RetryAfter = ttl
+		return status, nil
+	}
+
+	ttl, err = a.store.TTL(ctx, delayPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+	status.RetryAfter = ttl
+	return status, nil
+}
+
+// Release 释放资源
+func (a *Lockout) Release() error {
+	return a.store.Close()
+}
@@ -0,0 +1,70 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/lockout/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockout(t *testing.T) {
+	l := New(memory.NewStore(0))
+
+	defer l.Release()
+
+	ctx := context.Background()
+	key := "user:test"
+	policy := Policy{
+		MaxFailures:  3,
+		Window:       time.Minute,
+		LockDuration: time.Minute,
+		BaseDelay:    10 * time.Millisecond,
+		MaxDelay:     15 * time.Millisecond,
+	}
+
+	_, err := l.Check(ctx, key)
+	assert.Nil(t, err)
+
+	status, err := l.Fail(ctx, key, policy)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), status.Failures)
+	assert.False(t, status.Locked)
+	assert.Equal(t, 10*time.Millisecond, status.RetryAfter)
+
+	// 等待期间不允许尝试
+	_, err = l.Check(ctx, key)
+	assert.Equal(t, ErrLocked, err)
+
+	time.Sleep(status.RetryAfter)
+	_, err = l.Check(ctx, key)
+	assert.Nil(t, err)
+
+	status, err = l.Fail(ctx, key, policy)
+	assert.Nil(t, err)
+	assert.Equal(t, 15*time.Millisecond, status.RetryAfter)
+
+	status, err = l.Fail(ctx, key, policy)
+	assert.Nil(t, err)
+	assert.True(t, status.Locked)
+
+	ttl, err := l.Check(ctx, key)
+	assert.Equal(t, ErrLocked, err)
+	assert.True(t, ttl > 0)
+
+	status, err = l.Status(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, status.Locked)
+
+	err = l.Reset(ctx, key)
+	assert.Nil(t, err)
+
+	_, err = l.Check(ctx, key)
+	assert.Nil(t, err)
+
+	status, err = l.Status(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), status.Failures)
+	assert.False(t, status.Locked)
+}
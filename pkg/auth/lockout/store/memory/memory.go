@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewStore 创建基于内存的存储
+func NewStore(gcInterval time.Duration) *Store {
+	s := &Store{
+		items: make(map[string]*item),
+		stop:  make(chan struct{}),
+	}
+	if gcInterval > 0 {
+		go s.gc(gcInterval)
+	}
+	return s
+}
+
+type item struct {
+	value    int64
+	expireAt time.Time
+}
+
+func (i *item) expired(now time.Time) bool {
+	return !i.expireAt.IsZero() && !now.Before(i.expireAt)
+}
+
+// Store 内存存储
+type Store struct {
+	sync.Mutex
+	items map[string]*item
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// 获取未过期的数据(需持有锁)
+func (s *Store) get(key string, now time.Time) *item {
+	v, ok := s.items[key]
+	if !ok {
+		return nil
+	} else if v.expired(now) {
+		delete(s.items, key)
+		return nil
+	}
+	return v
+}
+
+func expireAt(now time.Time, expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(expiration)
+}
+
+// Incr ...
+func (s *Store) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	v := s.get(key, now)
+	if v == nil {
+		v = &item{expireAt: expireAt(now, expiration)}
+		s.items[key] = v
+	}
+	v.value++
+	return v.value, nil
+}
+
+// Get ...
+func (s *Store) Get(ctx context.Context, key string) (int64, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if v := s.get(key, time.Now()); v != nil {
+		return v.value, nil
+	}
+	return 0, nil
+}
+
+// Set ...
+func (s *Store) Set(ctx context.Context, key string, expiration time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.items[key] = &item{value: 1, expireAt: expireAt(time.Now(), expiration)}
+	return nil
+}
+
+// TTL ...
+func (s *Store) TTL(ctx context.Context, key string) (time.Duration, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	if v := s.get(key, now); v != nil && !v.expireAt.IsZero() {
+		return v.expireAt.Sub(now), nil
+	}
+	return 0, nil
+}
+
+// Delete ...
+func (s *Store) Delete(ctx context.Context, keys ...string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, key := range keys {
+		delete(s.items, key)
+	}
+	return nil
+}
+
+// 定期清理过期数据
+func (s *Store) gc(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.Lock()
+			for key, v := range s.items {
+				if v.expired(now) {
+					delete(s.items, key)
+				}
+			}
+			s.Unlock()
+		}
+	}
+}
+
+// Close ...
+func (s *Store) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(time.Minute)
+
+	defer store.Close()
+
+	ctx := context.Background()
+	key := "test"
+
+	n, err := store.Incr(ctx, key, 20*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = store.Incr(ctx, key, 20*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+
+	ttl, err := store.TTL(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+
+	// 统计窗口过期后重新计数
+	time.Sleep(20 * time.Millisecond)
+	n, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+
+	err = store.Set(ctx, key, 0)
+	assert.Nil(t, err)
+
+	ttl, err = store.TTL(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	err = store.Delete(ctx, key)
+	assert.Nil(t, err)
+
+	n, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+}
@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Config redis配置参数
+type Config struct {
+	Addr      string // 地址(IP:Port)
+	DB        int    // 数据库
+	Password  string // 密码
+	KeyPrefix string // 存储key的前缀
+}
+
+// NewStore 创建基于redis存储实例
+func NewStore(cfg *Config) *Store {
+	cli := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		DB:       cfg.DB,
+		Password: cfg.Password,
+	})
+	return &Store{
+		cli:    cli,
+		prefix: cfg.KeyPrefix,
+	}
+}
+
+// NewStoreWithClient 使用redis客户端创建存储实例
+func NewStoreWithClient(cli *redis.Client, keyPrefix string) *Store {
+	return &Store{
+		cli:    cli,
+		prefix: keyPrefix,
+	}
+}
+
+// NewStoreWithClusterClient 使用redis集群客户端创建存储实例
+func NewStoreWithClusterClient(cli *redis.ClusterClient, keyPrefix string) *Store {
+	return &Store{
+		cli:    cli,
+		prefix: keyPrefix,
+	}
+}
+
+type redisClienter interface {
+	Get(key string) *redis.StringCmd
+	Set(key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Incr(key string) *redis.IntCmd
+	Expire(key string, expiration time.Duration) *redis.BoolCmd
+	TTL(key string) *redis.DurationCmd
+	Del(keys ...string) *redis.IntCmd
+	Close() error
+}
+
+// Store redis存储
+type Store struct {
+	cli    redisClienter
+	prefix string
+}
+
+func (s *Store) wrapperKey(key string) string {
+	return fmt.Sprintf("%s%s", s.prefix, key)
+}
+
+// Incr ...
+func (s *Store) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	key = s.wrapperKey(key)
+	n, err := s.cli.Incr(key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// 首次计数时设定统计窗口
+	if n == 1 && expiration > 0 {
+		if err := s.cli.Expire(key, expiration).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// Get ...
+func (s *Store) Get(ctx context.Context, key string) (int64, error) {
+	n, err := s.cli.Get(s.wrapperKey(key)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Set ...
+func (s *Store) Set(ctx context.Context, key string, expiration time.Duration) error {
+	return s.cli.Set(s.wrapperKey(key), 1, expiration).Err()
+}
+
+// TTL ...
+func (s *Store) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.cli.TTL(s.wrapperKey(key)).Result()
+	if err != nil {
+		return 0, err
+	} else if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Delete ...
+func (s *Store) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	list := make([]string, len(keys))
+	for i, key := range keys {
+		list[i] = s.wrapperKey(key)
+	}
+	return s.cli.Del(list...).Err()
+}
+
+// Close ...
+func (s *Store) Close() error {
+	return s.cli.Close()
+}
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	addr = "127.0.0.1:6379"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(&Config{
+		Addr:      addr,
+		DB:        1,
+		KeyPrefix: "prefix",
+	})
+
+	defer store.Close()
+
+	ctx := context.Background()
+	key := "lockout_test"
+
+	n, err := store.Incr(ctx, key, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	ttl, err := store.TTL(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+
+	err = store.Delete(ctx, key)
+	assert.Nil(t, err)
+
+	n, err = store.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+}
@@ -0,0 +1,433 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/jwtauth"
+)
+
+// 定义错误
+var (
+	ErrInvalidState   = errors.New("invalid state")
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrTokenRequest   = errors.New("token request failed")
+)
+
+// 授权请求(state/nonce/code_verifier)的有效期
+const stateExpiration = 10 * time.Minute
+
+// 签名公钥的缓存时间(遇到未知的密钥ID时会刷新，但两次刷新至少间隔jwksRefreshInterval)
+const (
+	jwksCacheTTL        = time.Hour
+	jwksRefreshInterval = time.Minute
+)
+
+// StateStore 授权请求状态存储接口
+type StateStore interface {
+	// 存储状态，并指定到期时间
+	Set(ctx context.Context, key, value string, expiration time.Duration) error
+	// 获取并删除状态(每个状态只能使用一次)
+	Take(ctx context.Context, key string) (string, bool, error)
+	// 关闭存储
+	Close() error
+}
+
+// Config 配置参数
+type Config struct {
+	Issuer        string       // 签发者(用于服务发现 /.well-known/openid-configuration)
+	ClientID      string       // 客户端ID
+	ClientSecret  string       // 客户端密钥
+	RedirectURL   string       // 回调地址
+	Scopes        []string     // 申请的权限范围(默认 openid profile email)
+	UsernameClaim string       // 用户名声明(默认 preferred_username)
+	GroupsClaim   string       // 用户组声明(默认 groups)
+	HTTPClient    *http.Client // HTTP客户端
+}
+
+// Claims 映射后的用户声明
+type Claims struct {
+	Subject       string   // 用户在IdP的唯一标识
+	Email         string   // 邮箱
+	EmailVerified bool     // 邮箱是否已验证
+	Name          string   // 姓名
+	UserName      string   // 用户名
+	Groups        []string // 用户组
+}
+
+// AuthRequest 授权请求
+type AuthRequest struct {
+	URL   string // 跳转到IdP的授权地址
+	State string // 状态(回调时原样返回)
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type authState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// New 创建OIDC客户端(服务发现在首次使用时进行)
+func New(cfg Config, store StateStore) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, store: store}
+}
+
+// Provider OIDC客户端(授权码模式 + PKCE)
+type Provider struct {
+	cfg   Config
+	store StateStore
+
+	mu        sync.RWMutex
+	discovery *discovery
+	keys      map[string]interface{}
+	keysAt    time.Time
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.RLock()
+	d := p.discovery
+	p.mu.RUnlock()
+	if d != nil {
+		return d, nil
+	}
+
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	d = new(discovery)
+	err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", d)
+	if err != nil {
+		return nil, err
+	} else if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc: issuer mismatch: %s", d.Issuer)
+	}
+
+	p.mu.Lock()
+	p.discovery = d
+	p.mu.Unlock()
+	return d, nil
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge 计算PKCE的code_challenge(S256)
+func CodeChallenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// AuthCodeURL 生成授权地址
+func (p *Provider) AuthCodeURL(ctx context.Context) (*AuthRequest, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var values [3]string
+	for i := range values {
+		s, err := randomString(32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = s
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	buf, err := json.Marshal(authState{Nonce: nonce, CodeVerifier: verifier})
+	if err != nil {
+		return nil, err
+	}
+	err = p.store.Set(ctx, state, string(buf), stateExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	q := make(url.Values)
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return &AuthRequest{
+		URL:   d.AuthorizationEndpoint + sep + q.Encode(),
+		State: state,
+	}, nil
+}
+
+// Exchange 使用授权码换取令牌，校验ID令牌并返回用户声明
+func (p *Provider) Exchange(ctx context.Context, code, state string) (*Claims, error) {
+	v, ok, err := p.store.Take(ctx, state)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrInvalidState
+	}
+
+	var as authState
+	if err := json.Unmarshal([]byte(v), &as); err != nil {
+		return nil, ErrInvalidState
+	}
+
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.exchangeCode(ctx, d, code, as.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.verifyIDToken(ctx, d, token.IDToken, as.Nonce)
+}
+
+func (p *Provider) exchangeCode(ctx context.Context, d *discovery, code, verifier string) (*tokenResponse, error) {
+	form := make(url.Values)
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTokenRequest, resp.Status)
+	} else if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrTokenRequest, token.Error, token.Description)
+	} else if token.IDToken == "" {
+		return nil, ErrInvalidIDToken
+	}
+	return &token, nil
+}
+
+type idTokenClaims jwt.MapClaims
+
+func (c idTokenClaims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+func (c idTokenClaims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func (c idTokenClaims) Int64(name string) int64 {
+	switch v := c[name].(type) {
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	}
+	return 0
+}
+
+func (c idTokenClaims) Bool(name string) bool {
+	switch v := c[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, d *discovery, idToken, nonce string) (*Claims, error) {
+	mapClaims := make(jwt.MapClaims)
+	token, err := jwt.ParseWithClaims(idToken, mapClaims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA, *jwt.SigningMethodRSAPSS, *jwtauth.SigningMethodEd25519:
+		default:
+			return nil, ErrInvalidIDToken
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(ctx, d, kid)
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidIDToken
+	}
+
+	// 校验签发者、受众、有效期与nonce
+	claims := idTokenClaims(mapClaims)
+	now := time.Now().Unix()
+	const leeway = 60
+	if strings.TrimSuffix(claims.String("iss"), "/") != strings.TrimSuffix(d.Issuer, "/") {
+		return nil, ErrInvalidIDToken
+	} else if !containsString(claims.Strings("aud"), p.cfg.ClientID) {
+		return nil, ErrInvalidIDToken
+	} else if exp := claims.Int64("exp"); exp == 0 || now > exp+leeway {
+		return nil, ErrInvalidIDToken
+	} else if iat := claims.Int64("iat"); iat > now+leeway {
+		return nil, ErrInvalidIDToken
+	} else if claims.String("nonce") != nonce {
+		return nil, ErrInvalidIDToken
+	} else if claims.String("sub") == "" {
+		return nil, ErrInvalidIDToken
+	}
+
+	return &Claims{
+		Subject:       claims.String("sub"),
+		Email:         claims.String("email"),
+		EmailVerified: claims.Bool("email_verified"),
+		Name:          claims.String("name"),
+		UserName:      claims.String(p.cfg.UsernameClaim),
+		Groups:        claims.Strings(p.cfg.GroupsClaim),
+	}, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Provider) getKey(ctx context.Context, d *discovery, kid string) (interface{}, error) {
+	p.mu.RLock()
+	key, ok := p.keys[kid]
+	loaded := p.keys != nil
+	age := time.Since(p.keysAt)
+	p.mu.RUnlock()
+	if ok && age < jwksCacheTTL {
+		return key, nil
+	} else if !ok && loaded && age < jwksRefreshInterval {
+		return nil, ErrInvalidIDToken
+	}
+
+	// 密钥轮换后出现未知的密钥ID，重新获取公钥
+	var set jwtauth.JWKSet
+	err := p.getJSON(ctx, d.JWKSURI, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		publicKey, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = publicKey
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysAt = time.Now()
+	p.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, ErrInvalidIDToken
+	}
+	return key, nil
+}
+
+// Release 释放资源
+func (p *Provider) Release() error {
+	return p.store.Close()
+}
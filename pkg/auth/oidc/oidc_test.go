@@ -0,0 +1,142 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc/oidctest"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc/store/memory"
+)
+
+const redirectURL = "http://localhost/oidc/callback"
+
+func newProvider(idp *oidctest.Server) *oidc.Provider {
+	return oidc.New(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  redirectURL,
+	}, memory.NewStore(time.Minute))
+}
+
+func TestExchange(t *testing.T) {
+	idp := oidctest.NewServer("gin-admin", "secret")
+	defer idp.Close()
+
+	p := newProvider(idp)
+	defer p.Release()
+
+	ctx := context.Background()
+	req, err := p.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+
+	u, err := url.Parse(req.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	assert.Equal(t, req.State, u.Query().Get("state"))
+
+	code, state, err := idp.Authorize(req.URL, map[string]interface{}{
+		"sub":                "user-1",
+		"email":              "tom@example.com",
+		"email_verified":     true,
+		"preferred_username": "tom",
+		"groups":             []string{"admins", "dev"},
+	})
+	assert.Nil(t, err)
+
+	claims, err := p.Exchange(ctx, code, state)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "tom", claims.UserName)
+	assert.Equal(t, "tom@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, []string{"admins", "dev"}, claims.Groups)
+
+	// state只能使用一次
+	_, err = p.Exchange(ctx, code, state)
+	assert.True(t, errors.Is(err, oidc.ErrInvalidState))
+}
+
+func TestExchangeInvalid(t *testing.T) {
+	idp := oidctest.NewServer("gin-admin", "secret")
+	defer idp.Close()
+
+	p := newProvider(idp)
+	defer p.Release()
+
+	ctx := context.Background()
+
+	// code_verifier与code_challenge不匹配
+	req, err := p.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+	u, _ := url.Parse(req.URL)
+	q := u.Query()
+	q.Set("code_challenge", oidc.CodeChallenge("other"))
+	u.RawQuery = q.Encode()
+	code, state, err := idp.Authorize(u.String(), map[string]interface{}{"sub": "user-1"})
+	assert.Nil(t, err)
+	_, err = p.Exchange(ctx, code, state)
+	assert.True(t, errors.Is(err, oidc.ErrTokenRequest))
+
+	// nonce不匹配
+	req, err = p.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+	code, state, err = idp.Authorize(req.URL, map[string]interface{}{"sub": "user-1", "nonce": "other"})
+	assert.Nil(t, err)
+	_, err = p.Exchange(ctx, code, state)
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+
+	// 受众不匹配
+	req, err = p.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+	code, state, err = idp.Authorize(req.URL, map[string]interface{}{"sub": "user-1", "aud": "other"})
+	assert.Nil(t, err)
+	_, err = p.Exchange(ctx, code, state)
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+
+	// 令牌已过期
+	req, err = p.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+	code, state, err = idp.Authorize(req.URL, map[string]interface{}{"sub": "user-1", "exp": time.Now().Add(-time.Hour).Unix()})
+	assert.Nil(t, err)
+	_, err = p.Exchange(ctx, code, state)
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+
+	// 未知的state
+	_, err = p.Exchange(ctx, "code", "state")
+	assert.True(t, errors.Is(err, oidc.ErrInvalidState))
+}
+
+func TestKeyRotation(t *testing.T) {
+	idp := oidctest.NewServer("gin-admin", "secret")
+	defer idp.Close()
+
+	p := newProvider(idp)
+	defer p.Release()
+
+	ctx := context.Background()
+	login := func() error {
+		req, err := p.AuthCodeURL(ctx)
+		if err != nil {
+			return err
+		}
+		code, state, err := idp.Authorize(req.URL, map[string]interface{}{"sub": "user-1"})
+		if err != nil {
+			return err
+		}
+		_, err = p.Exchange(ctx, code, state)
+		return err
+	}
+
+	assert.Nil(t, login())
+
+	// 刷新间隔内遇到未知的密钥ID不会重新获取公钥
+	assert.Nil(t, idp.RotateKey())
+	assert.True(t, errors.Is(login(), oidc.ErrInvalidIDToken))
+}
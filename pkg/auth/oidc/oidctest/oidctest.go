@@ -0,0 +1,215 @@
+// Package oidctest 提供用于测试的进程内OIDC身份提供方(IdP)
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/jwtauth"
+	"github.com/LyricTian/gin-admin/v8/pkg/auth/oidc"
+)
+
+type authCode struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	claims      map[string]interface{}
+}
+
+// Server 模拟的身份提供方，支持服务发现、授权码换取令牌(校验PKCE)与公钥集合
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu    sync.Mutex
+	keys  *jwtauth.KeySet
+	codes map[string]*authCode
+}
+
+// NewServer 创建并启动身份提供方
+func NewServer(clientID, clientSecret string) *Server {
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		codes:        make(map[string]*authCode),
+	}
+	if err := s.RotateKey(); err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/keys", s.handleKeys)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Issuer 签发者地址
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// RotateKey 更换签名密钥
+func (s *Server) RotateKey() error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	keys, err := jwtauth.NewKeySet(jwt.SigningMethodRS256, key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Authorize 模拟用户在IdP完成登录，返回回调时携带的授权码与state；
+// claims会覆盖ID令牌中的同名声明(可用于构造异常令牌)
+func (s *Server) Authorize(authURL string, claims map[string]interface{}) (code, state string, err error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	q := u.Query()
+	if q.Get("response_type") != "code" {
+		return "", "", errors.New("unsupported response_type")
+	} else if q.Get("client_id") != s.ClientID {
+		return "", "", errors.New("invalid client_id")
+	} else if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		return "", "", errors.New("invalid code_challenge")
+	}
+
+	code = randomString()
+	s.mu.Lock()
+	s.codes[code] = &authCode{
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		claims:      claims,
+	}
+	s.mu.Unlock()
+
+	return code, q.Get("state"), nil
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	keys := s.keys
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, keys.JWKS())
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+	if !ok || clientID != s.ClientID ||
+		subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.ClientSecret)) != 1 {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	// 授权码只能使用一次
+	code := r.PostFormValue("code")
+	s.mu.Lock()
+	item, ok := s.codes[code]
+	delete(s.codes, code)
+	keys := s.keys
+	s.mu.Unlock()
+
+	if !ok || item.redirectURI != r.PostFormValue("redirect_uri") {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	} else if oidc.CodeChallenge(r.PostFormValue("code_verifier")) != item.challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   s.URL,
+		"aud":   s.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": item.nonce,
+	}
+	for k, v := range item.claims {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(keys.SigningMethod(), claims)
+	token.Header["kid"] = keys.KeyID()
+	idToken, err := token.SignedString(keys.SigningKey())
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewStore 创建基于内存的存储
+func NewStore(gcInterval time.Duration) *Store {
+	s := &Store{
+		items: make(map[string]*item),
+		stop:  make(chan struct{}),
+	}
+	if gcInterval > 0 {
+		go s.gc(gcInterval)
+	}
+	return s
+}
+
+type item struct {
+	value    string
+	expireAt time.Time
+}
+
+func (i *item) expired(now time.Time) bool {
+	return !i.expireAt.IsZero() && !now.Before(i.expireAt)
+}
+
+// Store 内存存储
+type Store struct {
+	sync.Mutex
+	items map[string]*item
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// Set ...
+func (s *Store) Set(ctx context.Context, key, value string, expiration time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+
+	var expireAt time.Time
+	if expiration > 0 {
+		expireAt = time.Now().Add(expiration)
+	}
+	s.items[key] = &item{value: value, expireAt: expireAt}
+	return nil
+}
+
+// Take ...
+func (s *Store) Take(ctx context.Context, key string) (string, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	v, ok := s.items[key]
+	if !ok {
+		return "", false, nil
+	}
+	delete(s.items, key)
+
+	if v.expired(time.Now()) {
+		return "", false, nil
+	}
+	return v.value, true, nil
+}
+
+// 定期清理过期数据
+func (s *Store) gc(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.Lock()
+			for key, v := range s.items {
+				if v.expired(now) {
+					delete(s.items, key)
+				}
+			}
+			s.Unlock()
+		}
+	}
+}
+
+// Close ...
+func (s *Store) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(time.Minute)
+
+	defer store.Close()
+
+	ctx := context.Background()
+
+	err := store.Set(ctx, "test", "value", time.Minute)
+	assert.Nil(t, err)
+
+	v, ok, err := store.Take(ctx, "test")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value", v)
+
+	// 只能获取一次
+	_, ok, err = store.Take(ctx, "test")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	err = store.Set(ctx, "expired", "value", 20*time.Millisecond)
+	assert.Nil(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok, err = store.Take(ctx, "expired")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
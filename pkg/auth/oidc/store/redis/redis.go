@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Config redis配置参数
+type Config struct {
+	Addr      string // 地址(IP:Port)
+	DB        int    // 数据库
+	Password  string // 密码
+	KeyPrefix string // 存储key的前缀
+}
+
+// NewStore 创建基于redis存储实例
+func NewStore(cfg *Config) *Store {
+	cli := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		DB:       cfg.DB,
+		Password: cfg.Password,
+	})
+	return &Store{
+		cli:    cli,
+		prefix: cfg.KeyPrefix,
+	}
+}
+
+// NewStoreWithClient 使用redis客户端创建存储实例
+func NewStoreWithClient(cli *redis.Client, keyPrefix string) *Store {
+	return &Store{
+		cli:    cli,
+		prefix: keyPrefix,
+	}
+}
+
+// NewStoreWithClusterClient 使用redis集群客户端创建存储实例
+func NewStoreWithClusterClient(cli *redis.ClusterClient, keyPrefix string) *Store {
+	return &Store{
+		cli:    cli,
+		prefix: keyPrefix,
+	}
+}
+
+type redisClienter interface {
+	Set(key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	TxPipeline() redis.Pipeliner
+	Close() error
+}
+
+// Store redis存储
+type Store struct {
+	cli    redisClienter
+	prefix string
+}
+
+func (s *Store) wrapperKey(key string) string {
+	return fmt.Sprintf("%s%s", s.prefix, key)
+}
+
+// Set ...
+func (s *Store) Set(ctx context.Context, key, value string, expiration time.Duration) error {
+	return s.cli.Set(s.wrapperKey(key), value, expiration).Err()
+}
+
+// Take ...
+func (s *Store) Take(ctx context.Context, key string) (string, bool, error) {
+	key = s.wrapperKey(key)
+
+	// 在同一事务中读取并删除，保证只能被获取一次
+	pipe := s.cli.TxPipeline()
+	getCmd := pipe.Get(key)
+	pipe.Del(key)
+	_, err := pipe.Exec()
+	if err == redis.Nil {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return getCmd.Val(), true, nil
+}
+
+// Close ...
+func (s *Store) Close() error {
+	return s.cli.Close()
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	addr = "127.0.0.1:6379"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(&Config{
+		Addr:      addr,
+		DB:        1,
+		KeyPrefix: "prefix",
+	})
+
+	defer store.Close()
+
+	ctx := context.Background()
+	key := "oidc_test"
+
+	err := store.Set(ctx, key, "value", time.Minute)
+	assert.Nil(t, err)
+
+	v, ok, err := store.Take(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value", v)
+
+	_, ok, err = store.Take(ctx, key)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+package totp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 附录B测试向量(SHA1，取后6位)
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for ts, want := range cases {
+		code, err := GenerateCode(rfcSecret, time.Unix(ts, 0))
+		assert.Nil(t, err)
+		assert.Equal(t, want, code)
+	}
+
+	_, err := GenerateCode("not base32!", time.Now())
+	assert.Equal(t, ErrInvalidSecret, err)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now.Add(-Period*time.Second))
+	assert.Nil(t, err)
+
+	step, ok, err := Validate(secret, code, now, 1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok, err = Validate(secret, code, now, 0)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = Validate(secret, "12345", now, 1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("gin-admin", "admin", rfcSecret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/gin-admin:admin?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=gin-admin")
+
+	png, err := QRCode(uri)
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+}
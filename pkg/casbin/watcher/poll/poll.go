@@ -0,0 +1,98 @@
+// Package poll 通过定期查询策略版本号实现的策略变更通知(适用于未部署redis或作为redis通知的补充)
+package poll
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+)
+
+var _ watcher.Backend = (*Backend)(nil)
+
+// VersionStore 策略版本号存储
+type VersionStore interface {
+	// Incr 版本号加1
+	Incr(ctx context.Context) error
+	// Get 获取当前版本号
+	Get(ctx context.Context) (int64, error)
+}
+
+// NewBackend 创建基于版本号轮询的通知方式
+func NewBackend(store VersionStore, interval time.Duration) *Backend {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Backend{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Backend 策略变更时增加版本号，轮询时版本号的变化超过本实例的变更次数则说明其他实例变更了策略
+type Backend struct {
+	store    VersionStore
+	interval time.Duration
+	mu       sync.Mutex
+	loaded   bool
+	version  int64
+	updates  int64
+}
+
+// Publish ...
+func (b *Backend) Publish(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.store.Incr(ctx); err != nil {
+		return err
+	}
+	b.updates++
+	return nil
+}
+
+// Subscribe ...
+func (b *Backend) Subscribe(ctx context.Context, notify func(), handleErr func(error)) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		if changed, err := b.check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			handleErr(err)
+		} else if changed {
+			notify()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Backend) check(ctx context.Context) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	version, err := b.store.Get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// 首次查询只记录版本号
+	changed := b.loaded && version-b.version != b.updates
+	b.loaded = true
+	b.version = version
+	b.updates = 0
+	return changed, nil
+}
+
+// Close ...
+func (b *Backend) Close() error {
+	return nil
+}
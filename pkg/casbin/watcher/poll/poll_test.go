@@ -0,0 +1,55 @@
+package poll
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+)
+
+type memoryStore struct {
+	version int64
+}
+
+func (s *memoryStore) Incr(ctx context.Context) error {
+	atomic.AddInt64(&s.version, 1)
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context) (int64, error) {
+	return atomic.LoadInt64(&s.version), nil
+}
+
+func TestWatcher(t *testing.T) {
+	store := new(memoryStore)
+	interval := 10 * time.Millisecond
+
+	var reloadA, reloadB int64
+	a := watcher.New([]watcher.Backend{NewBackend(store, interval)})
+	defer a.Close()
+	_ = a.SetUpdateCallback(func(string) { atomic.AddInt64(&reloadA, 1) })
+
+	b := watcher.New([]watcher.Backend{NewBackend(store, interval)})
+	defer b.Close()
+	_ = b.SetUpdateCallback(func(string) { atomic.AddInt64(&reloadB, 1) })
+
+	time.Sleep(3 * interval)
+
+	// 本实例的变更不需要重新加载
+	_ = a.Update()
+	_ = a.Update()
+	time.Sleep(5 * interval)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&reloadA))
+	assert.True(t, atomic.LoadInt64(&reloadB) >= 1)
+	assert.True(t, atomic.LoadInt64(&store.version) >= 1)
+
+	atomic.StoreInt64(&reloadB, 0)
+	_ = b.Update()
+	time.Sleep(5 * interval)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&reloadA))
+	assert.Equal(t, int64(0), atomic.LoadInt64(&reloadB))
+}
@@ -0,0 +1,107 @@
+// Package redis 基于redis发布订阅的策略变更通知
+package redis
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+	"github.com/LyricTian/gin-admin/v8/pkg/util/uuid"
+)
+
+var _ watcher.Backend = (*Backend)(nil)
+
+const (
+	receiveTimeout = time.Minute
+	retryInterval  = time.Second
+)
+
+// Config redis配置参数
+type Config struct {
+	Addr     string // 地址(IP:Port)
+	DB       int    // 数据库
+	Password string // 密码
+	Channel  string // 通知频道
+}
+
+// NewBackend 创建基于redis的通知方式
+func NewBackend(cfg *Config) *Backend {
+	cli := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		DB:       cfg.DB,
+		Password: cfg.Password,
+	})
+	return &Backend{
+		cli:     cli,
+		channel: cfg.Channel,
+		id:      uuid.MustString(),
+	}
+}
+
+// Backend 通过redis频道发布策略变更，消息内容为实例标识(忽略本实例发出的通知)
+type Backend struct {
+	cli     *redis.Client
+	channel string
+	id      string
+}
+
+// Publish ...
+func (b *Backend) Publish(ctx context.Context) error {
+	return b.cli.WithContext(ctx).Publish(b.channel, b.id).Err()
+}
+
+// Subscribe ...
+func (b *Backend) Subscribe(ctx context.Context, notify func(), handleErr func(error)) {
+	ps := b.cli.Subscribe(b.channel)
+	defer ps.Close()
+
+	go func() {
+		<-ctx.Done()
+		ps.Close()
+	}()
+
+	subscribed := false
+	for {
+		msg, err := ps.ReceiveTimeout(receiveTimeout)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			// 长时间没有消息时检查连接，连接异常时下次接收会重新连接并订阅
+			if e, ok := err.(net.Error); ok && e.Timeout() {
+				_ = ps.Ping()
+				continue
+			}
+
+			handleErr(err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			// 重新连接后可能遗漏了断开期间的通知，需要重新加载
+			if m.Kind == "subscribe" {
+				if subscribed {
+					notify()
+				}
+				subscribed = true
+			}
+		case *redis.Message:
+			if m.Payload != b.id {
+				notify()
+			}
+		}
+	}
+}
+
+// Close ...
+func (b *Backend) Close() error {
+	return b.cli.Close()
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LyricTian/gin-admin/v8/pkg/casbin/watcher"
+)
+
+const (
+	addr = "127.0.0.1:6379"
+)
+
+func TestWatcher(t *testing.T) {
+	cfg := &Config{
+		Addr:    addr,
+		DB:      1,
+		Channel: "casbin_watcher_test",
+	}
+
+	var reloadA, reloadB int64
+	a := watcher.New([]watcher.Backend{NewBackend(cfg)})
+	defer a.Close()
+	_ = a.SetUpdateCallback(func(string) { atomic.AddInt64(&reloadA, 1) })
+
+	b := watcher.New([]watcher.Backend{NewBackend(cfg)})
+	defer b.Close()
+	_ = b.SetUpdateCallback(func(string) { atomic.AddInt64(&reloadB, 1) })
+
+	time.Sleep(100 * time.Millisecond)
+
+	_ = a.Update()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&reloadA))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&reloadB))
+}
@@ -0,0 +1,166 @@
+// Package watcher 实现casbin策略变更监听，用于多实例部署时同步各实例的策略
+package watcher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/casbin/casbin/v2/persist"
+)
+
+var _ persist.Watcher = (*Watcher)(nil)
+
+// Backend 策略变更的通知方式
+type Backend interface {
+	// Publish 通知其他实例策略已变更
+	Publish(ctx context.Context) error
+	// Subscribe 监听其他实例的策略变更(阻塞直到ctx取消)，收到变更时调用notify，发生错误时调用handleErr
+	Subscribe(ctx context.Context, notify func(), handleErr func(error))
+	// Close 释放资源
+	Close() error
+}
+
+type options struct {
+	errorHandler func(error)
+}
+
+// Option 定义参数项
+type Option func(*options)
+
+// SetErrorHandler 设定通知或监听出错时的处理函数
+func SetErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = fn
+	}
+}
+
+// Watcher 策略变更监听，短时间内的多次变更会合并为一次通知与一次重新加载
+type Watcher struct {
+	opts     options
+	backends []Backend
+	publishc chan struct{}
+	reloadc  chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	once     sync.Once
+	mu       sync.RWMutex
+	callback func(string)
+}
+
+// New 创建策略变更监听(未指定通知方式时不做任何处理)
+func New(backends []Backend, opts ...Option) *Watcher {
+	o := options{
+		errorHandler: func(error) {},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		opts:     o,
+		backends: backends,
+		publishc: make(chan struct{}, 1),
+		reloadc:  make(chan struct{}, 1),
+		cancel:   cancel,
+	}
+	if len(backends) == 0 {
+		return w
+	}
+
+	w.wg.Add(2 + len(backends))
+	go w.publishLoop(ctx)
+	go w.reloadLoop(ctx)
+	for _, b := range backends {
+		go func(b Backend) {
+			defer w.wg.Done()
+			b.Subscribe(ctx, w.notify, o.errorHandler)
+		}(b)
+	}
+	return w
+}
+
+func (w *Watcher) publishLoop(ctx context.Context) {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			// 关闭前发送尚未发送的通知
+			select {
+			case <-w.publishc:
+				w.publish(context.Background())
+			default:
+			}
+			return
+		case <-w.publishc:
+			w.publish(ctx)
+		}
+	}
+}
+
+func (w *Watcher) publish(ctx context.Context) {
+	for _, b := range w.backends {
+		if err := b.Publish(ctx); err != nil {
+			w.opts.errorHandler(err)
+		}
+	}
+}
+
+func (w *Watcher) reloadLoop(ctx context.Context) {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.reloadc:
+			w.mu.RLock()
+			callback := w.callback
+			w.mu.RUnlock()
+			if callback != nil {
+				callback("")
+			}
+		}
+	}
+}
+
+// 收到其他实例的变更通知(重新加载未完成时只保留一次)
+func (w *Watcher) notify() {
+	select {
+	case w.reloadc <- struct{}{}:
+	default:
+	}
+}
+
+// SetUpdateCallback 设定收到其他实例的变更通知时的回调(通常为重新加载策略)
+func (w *Watcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	w.callback = callback
+	w.mu.Unlock()
+	return nil
+}
+
+// Update 通知其他实例策略已变更(异步发送，发送完成前的多次变更只发送一次)
+func (w *Watcher) Update() error {
+	if len(w.backends) == 0 {
+		return nil
+	}
+
+	select {
+	case w.publishc <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close 停止监听并释放资源
+func (w *Watcher) Close() {
+	w.once.Do(func() {
+		w.cancel()
+		w.wg.Wait()
+		for _, b := range w.backends {
+			if err := b.Close(); err != nil {
+				w.opts.errorHandler(err)
+			}
+		}
+	})
+}
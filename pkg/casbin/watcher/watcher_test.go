@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBackend struct {
+	published int64
+	notifyc   chan struct{}
+}
+
+func (b *testBackend) Publish(ctx context.Context) error {
+	atomic.AddInt64(&b.published, 1)
+	return nil
+}
+
+func (b *testBackend) Subscribe(ctx context.Context, notify func(), handleErr func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.notifyc:
+			notify()
+		}
+	}
+}
+
+func (b *testBackend) Close() error {
+	return nil
+}
+
+func TestWatcher(t *testing.T) {
+	b := &testBackend{notifyc: make(chan struct{})}
+	w := New([]Backend{b})
+
+	var reloads int64
+	_ = w.SetUpdateCallback(func(string) { atomic.AddInt64(&reloads, 1) })
+
+	b.notifyc <- struct{}{}
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&reloads))
+
+	// 连续的变更合并发送
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, w.Update())
+	}
+	time.Sleep(10 * time.Millisecond)
+	published := atomic.LoadInt64(&b.published)
+	assert.True(t, published >= 1 && published <= 2)
+
+	// 关闭时发送尚未发送的通知
+	_ = w.Update()
+	w.Close()
+	assert.True(t, atomic.LoadInt64(&b.published) > published)
+}
+
+func TestWatcherWithoutBackend(t *testing.T) {
+	w := New(nil)
+	assert.Nil(t, w.Update())
+	w.Close()
+}
@@ -12,6 +12,7 @@ var (
 	WithStack    = errors.WithStack
 	WithMessage  = errors.WithMessage
 	WithMessagef = errors.WithMessagef
+	Is           = errors.Is
 )
 
 var (
@@ -24,4 +25,5 @@ var (
 	ErrBadRequest      = New400Response("bad request")
 	ErrInvalidParent   = New400Response("not found parent node")
 	ErrUserDisable     = New400Response("user forbidden")
+	ErrPasswordChange  = NewResponse(0, 403, "password change required")
 )
@@ -4,10 +4,11 @@ import "fmt"
 
 // ResponseError 定义响应错误
 type ResponseError struct {
-	Code    int    // 错误码
-	Message string // 错误消息
-	Status  int    // 响应状态码
-	ERR     error  // 响应错误
+	Code    int         // 错误码
+	Message string      // 错误消息
+	Status  int         // 响应状态码
+	ERR     error       // 响应错误
+	Details interface{} // 错误详情
 }
 
 func (r *ResponseError) Error() string {
@@ -55,6 +56,20 @@ func New400Response(msg string, args ...interface{}) error {
 	return NewResponse(0, 400, msg, args...)
 }
 
+func NewResponseWithDetails(code, status int, details interface{}, msg string, args ...interface{}) error {
+	res := &ResponseError{
+		Code:    code,
+		Message: fmt.Sprintf(msg, args...),
+		Status:  status,
+		Details: details,
+	}
+	return res
+}
+
+func New400ResponseWithDetails(details interface{}, msg string, args ...interface{}) error {
+	return NewResponseWithDetails(0, 400, details, msg, args...)
+}
+
 func New500Response(msg string, args ...interface{}) error {
 	return NewResponse(0, 500, msg, args...)
 }
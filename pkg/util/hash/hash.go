@@ -3,6 +3,7 @@ package hash
 import (
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 )
 
@@ -29,3 +30,15 @@ func SHA1(b []byte) string {
 func SHA1String(s string) string {
 	return SHA1([]byte(s))
 }
+
+// SHA256 SHA256哈希值
+func SHA256(b []byte) string {
+	h := sha256.New()
+	_, _ = h.Write(b)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// SHA256String SHA256哈希值
+func SHA256String(s string) string {
+	return SHA256([]byte(s))
+}
@@ -0,0 +1,207 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 定义错误
+var (
+	ErrInvalidHash     = errors.New("invalid password hash")
+	ErrUnsupportedHash = errors.New("unsupported password hash")
+)
+
+// 密码哈希算法
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// PasswordHasher 密码哈希，生成的哈希值包含算法与参数(自描述编码)
+type PasswordHasher interface {
+	// 生成密码哈希值
+	Hash(password string) (string, error)
+	// 校验密码(兼容所有支持的哈希格式)
+	Verify(encoded, password string) (bool, error)
+	// 检查哈希值是否需要使用当前算法与参数重新生成
+	NeedsRehash(encoded string) bool
+}
+
+// NewPasswordHasher 根据算法名称创建密码哈希(支持：bcrypt/argon2id)
+func NewPasswordHasher(algorithm string) (PasswordHasher, error) {
+	switch algorithm {
+	case AlgorithmBcrypt:
+		return NewBcryptHasher(bcrypt.DefaultCost), nil
+	case "", AlgorithmArgon2id:
+		return NewArgon2idHasher(DefaultArgon2idParams), nil
+	}
+	return nil, fmt.Errorf("unsupported password hash algorithm: %s", algorithm)
+}
+
+// VerifyPassword 校验密码，根据哈希值的编码自动识别算法(包括旧版SHA1哈希)
+func VerifyPassword(encoded, password string) (bool, error) {
+	switch {
+	case isBcryptHash(encoded):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		return err == nil, err
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+		other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	case IsLegacyHash(encoded):
+		return subtle.ConstantTimeCompare([]byte(encoded), []byte(SHA1String(password))) == 1, nil
+	}
+	return false, ErrUnsupportedHash
+}
+
+// IsLegacyHash 检查是否是旧版无盐的SHA1哈希值
+func IsLegacyHash(encoded string) bool {
+	if len(encoded) != 40 {
+		return false
+	}
+	for _, c := range encoded {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isBcryptHash(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+// NewBcryptHasher 创建bcrypt密码哈希
+func NewBcryptHasher(cost int) PasswordHasher {
+	return &bcryptHasher{cost: cost}
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (h *bcryptHasher) Verify(encoded, password string) (bool, error) {
+	return VerifyPassword(encoded, password)
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	if !isBcryptHash(encoded) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.cost
+}
+
+// Argon2idParams argon2id参数
+type Argon2idParams struct {
+	Time    uint32 // 迭代次数
+	Memory  uint32 // 内存(单位KB)
+	Threads uint8  // 并行度
+	SaltLen uint32 // 盐长度
+	KeyLen  uint32 // 哈希长度
+}
+
+// DefaultArgon2idParams 默认的argon2id参数
+var DefaultArgon2idParams = Argon2idParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 2,
+	SaltLen: 16,
+	KeyLen:  32,
+}
+
+// NewArgon2idHasher 创建argon2id密码哈希
+func NewArgon2idHasher(params Argon2idParams) PasswordHasher {
+	return &argon2idHasher{params: params}
+}
+
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+// 编码格式：$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	p := h.params
+	salt := make([]byte, p.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+func (h *argon2idHasher) Verify(encoded, password string) (bool, error) {
+	return VerifyPassword(encoded, password)
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		return true
+	}
+
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	p := h.params
+	return params.Time != p.Time || params.Memory != p.Memory || params.Threads != p.Threads ||
+		uint32(len(salt)) != p.SaltLen || uint32(len(key)) != p.KeyLen
+}
+
+func decodeArgon2id(encoded string) (*Argon2idParams, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	} else if version != argon2.Version {
+		return nil, nil, nil, ErrUnsupportedHash
+	}
+
+	params := new(Argon2idParams)
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	key, err := enc.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	params.SaltLen = uint32(len(salt))
+	params.KeyLen = uint32(len(key))
+	return params, salt, key, nil
+}
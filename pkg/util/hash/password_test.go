@@ -0,0 +1,57 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordHasher(t *testing.T) {
+	hashers := []PasswordHasher{
+		NewBcryptHasher(4),
+		NewArgon2idHasher(Argon2idParams{Time: 1, Memory: 1024, Threads: 1, SaltLen: 16, KeyLen: 32}),
+	}
+
+	for _, h := range hashers {
+		encoded, err := h.Hash("secret")
+		assert.Nil(t, err)
+		assert.False(t, h.NeedsRehash(encoded))
+
+		// 相同密码每次生成的哈希值不同(加盐)
+		other, err := h.Hash("secret")
+		assert.Nil(t, err)
+		assert.NotEqual(t, encoded, other)
+
+		ok, err := h.Verify(encoded, "secret")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		ok, err = h.Verify(encoded, "wrong")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	}
+
+	// 更换算法或参数后需要重新生成
+	encoded, err := hashers[0].Hash("secret")
+	assert.Nil(t, err)
+	assert.True(t, hashers[1].NeedsRehash(encoded))
+	assert.True(t, NewBcryptHasher(5).NeedsRehash(encoded))
+}
+
+func TestVerifyLegacyPassword(t *testing.T) {
+	h := NewBcryptHasher(4)
+	legacy := SHA1String("secret")
+	assert.True(t, IsLegacyHash(legacy))
+	assert.True(t, h.NeedsRehash(legacy))
+
+	ok, err := h.Verify(legacy, "secret")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify(legacy, "wrong")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = VerifyPassword("plain", "plain")
+	assert.Equal(t, ErrUnsupportedHash, err)
+}